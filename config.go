@@ -64,9 +64,28 @@ type Config struct {
 	// HandlesThePathAsIs can be used to set both the LenientOnTrailingSlash
 	// and the LenientOnUncleanPath at the same time.
 	HandlesThePathAsIs bool
+
+	// CaseInsensitive means that the static parts of a responder's template
+	// are matched against the request's URL regardless of case. The regular
+	// expressions of the template are not affected. A case-insensitive static
+	// responder's template must differ from its static siblings' templates
+	// regardless of case, otherwise the responder's registration fails.
+	CaseInsensitive bool
+
+	// RedirectsToCanonicalCase makes the case-insensitive resource redirect
+	// the request to the URL with the path segment in its template's case.
+	// The redirect happens when the request is handled. The property also
+	// sets the CaseInsensitive property. It has no effect on hosts.
+	RedirectsToCanonicalCase bool
+
+	// HandlesHeadWithGet makes the responder handle a HEAD request with its
+	// GET handler when it doesn't have a HEAD handler. The response body
+	// written by the GET handler is discarded, but its length is reported
+	// in the "Content-Length" header.
+	HandlesHeadWithGet bool
 }
 
-// asFlags returns the Config properties set to true as an 8-bit _ConfigFlags.
+// asFlags returns the Config properties set to true as a 16-bit _ConfigFlags.
 func (config Config) asFlags() _ConfigFlags {
 	var cfs _ConfigFlags
 	if config.SubtreeHandler {
@@ -101,13 +120,25 @@ func (config Config) asFlags() _ConfigFlags {
 		cfs.set(flagHandlesThePathAsIs)
 	}
 
+	if config.CaseInsensitive {
+		cfs.set(flagCaseInsensitive)
+	}
+
+	if config.RedirectsToCanonicalCase {
+		cfs.set(flagCaseInsensitive | flagRedirectsToCanonicalCase)
+	}
+
+	if config.HandlesHeadWithGet {
+		cfs.set(flagHandlesHeadWithGet)
+	}
+
 	return cfs
 }
 
 // --------------------------------------------------
 
 // _ConfigFlags keeps the resource properties as bit flags.
-type _ConfigFlags uint8
+type _ConfigFlags uint16
 
 const (
 	flagActive _ConfigFlags = 1 << iota
@@ -118,6 +149,9 @@ const (
 	flagStrictOnTrailingSlash
 	flagLenientOnTrailingSlash
 	flagLenientOnUncleanPath
+	flagCaseInsensitive
+	flagRedirectsToCanonicalCase
+	flagHandlesHeadWithGet
 	flagHandlesThePathAsIs = flagLenientOnTrailingSlash | flagLenientOnUncleanPath
 )
 
@@ -139,5 +173,33 @@ func (cfs _ConfigFlags) asConfig() Config {
 		LenientOnTrailingSlash:   cfs.has(flagLenientOnTrailingSlash),
 		LenientOnUncleanPath:     cfs.has(flagLenientOnUncleanPath),
 		HandlesThePathAsIs:       cfs.has(flagHandlesThePathAsIs),
+		CaseInsensitive:          cfs.has(flagCaseInsensitive),
+		RedirectsToCanonicalCase: cfs.has(flagRedirectsToCanonicalCase),
+		HandlesHeadWithGet:       cfs.has(flagHandlesHeadWithGet),
 	}
 }
+
+// --------------------------------------------------
+
+// setConfigurationOfEvery sets the config for the passed hosts and resources
+// and all their subtree resources. The responders configured with properties
+// other than security and trailing slash are skipped.
+func setConfigurationOfEvery(_rs []_Responder, config Config) error {
+	var err = traverseAndCall(
+		_rs,
+		func(_r _Responder) error {
+			var cfs = _r.configFlags()
+			if (cfs &^ (flagActive | flagSecure | flagTrailingSlash)) > 0 {
+				return nil
+			}
+
+			return _r.TryToSetConfiguration(config)
+		},
+	)
+
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// --------------------------------------------------
+
+// CORSPolicy contains the properties of the cross-origin resource sharing
+// policy of a router, host, or resource. The policy is inherited by the
+// hosts and resources below in the tree that don't have their own policy.
+//
+// The default OPTIONS handler answers the preflight requests with the
+// policy and the HTTP methods of the responder. The responses to the
+// actual cross-origin requests are decorated with the policy's headers
+// before the HTTP method handler is called.
+type CORSPolicy struct {
+	// AllowedOrigins are the origins allowed to make cross-origin requests.
+	// An asterisk "*" alone allows any origin. An origin can also contain a
+	// single asterisk as a wildcard, e.g., "https://*.example.com". When the
+	// list is empty, no origin is allowed.
+	AllowedOrigins []string
+
+	// AllowedHeaders are the request headers allowed in cross-origin
+	// requests. An asterisk "*" alone allows the headers requested in the
+	// preflight request.
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers the clients are allowed to
+	// access.
+	ExposedHeaders []string
+
+	// AllowsCredentials means that the clients are allowed to send
+	// credentials with cross-origin requests. It cannot be set when the
+	// allowed origins contain an asterisk "*" alone, so the credentials are
+	// never shared with any origin.
+	AllowsCredentials bool
+
+	// MaxAge is the number of seconds the result of the preflight request
+	// can be cached. When zero, the header is not sent.
+	MaxAge int
+}
+
+// clone returns a copy of the policy that doesn't share its slices.
+func (cp *CORSPolicy) clone() *CORSPolicy {
+	var c = *cp
+	c.AllowedOrigins = append([]string(nil), cp.AllowedOrigins...)
+	c.AllowedHeaders = append([]string(nil), cp.AllowedHeaders...)
+	c.ExposedHeaders = append([]string(nil), cp.ExposedHeaders...)
+	return &c
+}
+
+// validate returns an error if one of the policy's properties is invalid.
+func (cp *CORSPolicy) validate() error {
+	for _, o := range cp.AllowedOrigins {
+		if o == "" || strings.Count(o, "*") > 1 {
+			return newErr("%w: origin %q", errInvalidArgument, o)
+		}
+	}
+
+	if cp.AllowsCredentials && cp.allowsAnyOrigin() {
+		return newErr(
+			"%w: credentials are allowed for any origin",
+			errInvalidArgument,
+		)
+	}
+
+	if cp.MaxAge < 0 {
+		return newErr("%w: negative max age", errInvalidArgument)
+	}
+
+	return nil
+}
+
+// allowsAnyOrigin returns true if the policy's allowed origins contain an
+// asterisk "*" alone.
+func (cp *CORSPolicy) allowsAnyOrigin() bool {
+	for _, o := range cp.AllowedOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsOrigin returns true if the origin matches one of the policy's
+// allowed origins.
+func (cp *CORSPolicy) allowsOrigin(origin string) bool {
+	for _, o := range cp.AllowedOrigins {
+		if o == "*" {
+			return true
+		}
+
+		var prefix, suffix, found = strings.Cut(o, "*")
+		if !found {
+			if strings.EqualFold(o, origin) {
+				return true
+			}
+
+			continue
+		}
+
+		if len(origin) > len(prefix)+len(suffix) &&
+			strings.EqualFold(origin[:len(prefix)], prefix) &&
+			strings.EqualFold(origin[len(origin)-len(suffix):], suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowOrigin sets the "Access-Control-Allow-Origin" header and its related
+// headers.
+func (cp *CORSPolicy) allowOrigin(h http.Header, origin string) {
+	if cp.allowsAnyOrigin() {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+
+	if cp.AllowsCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// varyOnOrigin adds the "Origin" to the "Vary" header when the response
+// depends on the request's origin. Only the policy that allows any origin
+// responds the same way to all origins. Otherwise, the header is added whether
+// the origin is allowed or not, so the caches don't serve the response to the
+// other origins.
+func (cp *CORSPolicy) varyOnOrigin(h http.Header) {
+	if cp.allowsAnyOrigin() {
+		return
+	}
+
+	addVary(h, "Origin")
+}
+
+// decorate sets the CORS headers of the response to the actual cross-origin
+// request.
+func (cp *CORSPolicy) decorate(w http.ResponseWriter, r *http.Request) {
+	var h = w.Header()
+	cp.varyOnOrigin(h)
+
+	var origin = r.Header.Get("Origin")
+	if origin == "" || !cp.allowsOrigin(origin) {
+		return
+	}
+
+	cp.allowOrigin(h, origin)
+
+	if len(cp.ExposedHeaders) > 0 {
+		h.Set(
+			"Access-Control-Expose-Headers",
+			strings.Join(cp.ExposedHeaders, ", "),
+		)
+	}
+}
+
+// handlePreflight answers the preflight request if the request's origin and
+// method are allowed. The methods are the HTTP methods of the responder. If
+// the preflight request is not allowed, it's not answered and false is
+// returned.
+func (cp *CORSPolicy) handlePreflight(
+	w http.ResponseWriter,
+	r *http.Request,
+	methods []string,
+) bool {
+	cp.varyOnOrigin(w.Header())
+
+	var origin = r.Header.Get("Origin")
+	if !cp.allowsOrigin(origin) {
+		return false
+	}
+
+	var rm = r.Header.Get("Access-Control-Request-Method")
+	var allowed bool
+	for _, m := range methods {
+		if m == rm {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		return false
+	}
+
+	var h = w.Header()
+	addVary(
+		h,
+		"Access-Control-Request-Method",
+		"Access-Control-Request-Headers",
+	)
+	cp.allowOrigin(h, origin)
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	if len(cp.AllowedHeaders) == 1 && cp.AllowedHeaders[0] == "*" {
+		if rhs := r.Header.Get("Access-Control-Request-Headers"); rhs != "" {
+			h.Set("Access-Control-Allow-Headers", rhs)
+		}
+	} else if len(cp.AllowedHeaders) > 0 {
+		h.Set(
+			"Access-Control-Allow-Headers",
+			strings.Join(cp.AllowedHeaders, ", "),
+		)
+	}
+
+	if cp.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(cp.MaxAge))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// -------------------------
+
+// isPreflightRequest returns true if the request is a CORS preflight request.
+func isPreflightRequest(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// corsPolicyOf returns the CORS policy of the responder or router. If it
+// doesn't have one, the policy of its closest ancestor is returned. When
+// none of them has a policy, nil is returned.
+func corsPolicyOf(p _Parent) *CORSPolicy {
+	for ; p != nil; p = p.parent() {
+		var cp *CORSPolicy
+		switch p := p.(type) {
+		case *Router:
+			cp = p.corsPolicy
+		case *Host:
+			cp = p.corsPolicy
+		case *Resource:
+			cp = p.corsPolicy
+		}
+
+		if cp != nil {
+			return cp
+		}
+	}
+
+	return nil
+}
+
+// corsPolicyOfArgs returns the CORS policy of the responder handling the
+// request, or nil if the args don't belong to a responder.
+func corsPolicyOfArgs(args *Args) *CORSPolicy {
+	if args == nil || args._r == nil {
+		return nil
+	}
+
+	return corsPolicyOf(args._r)
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// --------------------------------------------------
+
+func TestCORSPolicy_allowsOrigin(t *testing.T) {
+	var cp = &CORSPolicy{
+		AllowedOrigins: []string{
+			"https://example.com",
+			"https://*.example.com",
+		},
+	}
+
+	checkValue(t, cp.allowsOrigin("https://example.com"), true)
+	checkValue(t, cp.allowsOrigin("https://EXAMPLE.com"), true)
+	checkValue(t, cp.allowsOrigin("https://api.example.com"), true)
+	checkValue(t, cp.allowsOrigin("https://.example.com"), false)
+	checkValue(t, cp.allowsOrigin("http://api.example.com"), false)
+	checkValue(t, cp.allowsOrigin("https://example.org"), false)
+	checkValue(t, cp.allowsOrigin(""), false)
+
+	cp = &CORSPolicy{AllowedOrigins: []string{"*"}}
+	checkValue(t, cp.allowsOrigin("https://example.org"), true)
+
+	cp = &CORSPolicy{}
+	checkValue(t, cp.allowsOrigin("https://example.org"), false)
+}
+
+func TestCORSPolicy_validate(t *testing.T) {
+	testPanicker(t, true, func() {
+		NewRouter().SetCORSPolicy(
+			CORSPolicy{AllowedOrigins: []string{"https://*.*.com"}},
+		)
+	})
+
+	testPanicker(t, true, func() {
+		NewRouter().SetCORSPolicy(CORSPolicy{AllowedOrigins: []string{""}})
+	})
+
+	testPanicker(t, true, func() {
+		NewDormantResource("r").SetCORSPolicy(CORSPolicy{MaxAge: -1})
+	})
+
+	testPanicker(t, true, func() {
+		NewRouter().SetCORSPolicy(CORSPolicy{
+			AllowedOrigins:    []string{"https://example.com", "*"},
+			AllowsCredentials: true,
+		})
+	})
+
+	testPanicker(t, false, func() {
+		NewRouter().SetCORSPolicy(CORSPolicy{
+			AllowedOrigins:    []string{"https://*.example.com"},
+			AllowsCredentials: true,
+		})
+	})
+}
+
+func TestCORS(t *testing.T) {
+	var ro = NewRouter()
+	var h = func(w http.ResponseWriter, r *http.Request, _ *Args) bool {
+		w.Write([]byte(r.Method))
+		return true
+	}
+
+	ro.SetCORSPolicy(CORSPolicy{
+		AllowedOrigins:    []string{"https://*.example.com"},
+		AllowedHeaders:    []string{"Content-Type", "X-Token"},
+		ExposedHeaders:    []string{"X-Total"},
+		AllowsCredentials: true,
+		MaxAge:            600,
+	})
+
+	ro.SetURLHandlerFor("GET PUT", "http://api.example.com/users", h)
+	ro.SetURLHandlerFor("GET", "http://api.example.com/public/data", h)
+	ro.SetURLHandlerFor("GET", "http://api.example.com/private", h)
+
+	var public = ro.Resource("http://api.example.com/public")
+	public.SetCORSPolicy(CORSPolicy{AllowedOrigins: []string{"*"}})
+
+	var private = ro.Resource("http://api.example.com/private")
+	private.SetCORSPolicy(CORSPolicy{})
+
+	checkValue(
+		t,
+		ro.RegisteredResource(
+			"http://api.example.com/public/data",
+		).CORSPolicy().AllowedOrigins,
+		[]string{"*"},
+	)
+
+	var cases = []struct {
+		name, method, url, origin, requestMethod string
+		wantStatusCode                           int
+		wantHeaders                              map[string]string
+	}{
+		{
+			"preflight",
+			"OPTIONS", "http://api.example.com/users",
+			"https://app.example.com", "PUT",
+			http.StatusNoContent,
+			map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Methods":     "GET, OPTIONS, PUT",
+				"Access-Control-Allow-Headers":     "Content-Type, X-Token",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+				"Allow":                            "",
+			},
+		},
+		{
+			"preflight of a not allowed method",
+			"OPTIONS", "http://api.example.com/users",
+			"https://app.example.com", "DELETE",
+			http.StatusOK,
+			map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Allow":                       "GET, OPTIONS, PUT",
+			},
+		},
+		{
+			"preflight from a not allowed origin",
+			"OPTIONS", "http://api.example.com/users",
+			"https://example.org", "PUT",
+			http.StatusOK,
+			map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Allow":                       "GET, OPTIONS, PUT",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			"actual request",
+			"PUT", "http://api.example.com/users",
+			"https://app.example.com", "",
+			http.StatusOK,
+			map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Total",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			"actual request from a not allowed origin",
+			"GET", "http://api.example.com/users",
+			"https://example.org", "",
+			http.StatusOK,
+			map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			"not allowed method",
+			"DELETE", "http://api.example.com/users",
+			"https://app.example.com", "",
+			http.StatusMethodNotAllowed,
+			map[string]string{
+				"Access-Control-Allow-Origin": "https://app.example.com",
+			},
+		},
+		{
+			"same-origin request",
+			"GET", "http://api.example.com/users",
+			"", "",
+			http.StatusOK,
+			map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			"any origin",
+			"GET", "http://api.example.com/public/data",
+			"https://example.org", "",
+			http.StatusOK,
+			map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+				"Vary":                             "",
+			},
+		},
+		{
+			"turned off",
+			"GET", "http://api.example.com/private",
+			"https://app.example.com", "",
+			http.StatusOK,
+			map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			var r = httptest.NewRequest(c.method, c.url, nil)
+			if c.origin != "" {
+				r.Header.Set("Origin", c.origin)
+			}
+
+			if c.requestMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", c.requestMethod)
+			}
+
+			ro.ServeHTTP(w, r)
+			checkValue(t, w.Code, c.wantStatusCode)
+
+			for k, v := range c.wantHeaders {
+				checkValue(t, w.Header().Get(k), v)
+			}
+		})
+	}
+}
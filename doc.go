@@ -37,6 +37,18 @@ Wildcard templates have only one wildcard segment and no static or regex segment
 	"{article}"
 	// Hosts cannot have a wildcard template.
 
+A wildcard template whose value name is followed by "..." is a catch-all template: "{valueName...}". The resource with a catch-all template matches all the remaining path segments of the request's URL, and their unescaped path is its value. As the escaped slash "%2F" can't be told from the separator in the unescaped value, the Args' RawCatchAllValue method returns the value as it was escaped in the request's URL. Static and pattern resources take precedence over the catch-all resource of the same parent. A catch-all resource cannot have child resources.
+
+	// Catch-all templates
+	"/static/{filepath...}"
+	"https:///buckets/{bucket}/{key...}"
+
+A wildcard template whose value name is followed by "?" is an optional template. It may have a default value after the "=" sign: "{valueName?=default}". When the request's URL doesn't have the optional segment and the next path segment matches one of the optional resource's static or pattern child resources, the request is passed to that child resource and the segment's value is the default value. When the request's URL ends before the optional segment, and its parent cannot handle the request itself, the optional resource handles the request with the default value. When the resource's URL is built, the optional segment is omitted if its value equals the default value.
+
+	// Optional templates
+	"/{lang?=en}/docs"
+	"/{page?}"
+
 The host segment templates must always follow the scheme with the colon ":" and the two slashes "//" of the authority component. The path segment templates may be preceded by a slash "/" or a scheme, a colon ":", and three slashes "///" (two authority component slashes and the third separator slash). Like in "https:///blog". The preceding slash is just a separator. It doesn't denote the root resource, except when it is used alone. The template "/" or "https:///" denotes the root resource. Both the host and path segment templates can have a trailing slash. When its template starts with "https" unless configured to redirect, the host or resource will not handle a request when used under HTTP and respond with a "404 Not Found" status code. When its template has a trailing slash unless configured to be lenient or strict, the resource will redirect the request that was made to a URL without a trailing slash to the one with a trailing slash and vice versa. The trailing slash has no effect on the host. But if the host is a subtree handler and should respond to the request, its configurations related to the trailing slash will be used on the last path segment.
 
 As a side note, every parent resource should have a trailing slash in its template. For example, in a resource tree "/parent/child/grandchild", two non-leaf resources should be referenced with templates "parent/" and "child/". NanoMux doesn't force this, but it's good practice to follow. It helps the clients avoid forming a broken URL when adding a relative URL to the base URL. By default, if the resource has a trailing slash, NanoMux redirects the requests made to the URL without a trailing slash to the URL with a trailing slash. So the clients will have the correct URL.
@@ -53,6 +65,27 @@ For convenience, a regex segment's or wildcard segment's value name is used as t
 
 If the template's static part needs a "$" sign at the beginning or curly braces anywhere, they can be escaped with a backslash `\`. Like in the template `\$tatic\{template\}`. For some reason, if the template name or value name needs a colon ":", it can also be escapbed with a backslash: `$smileys\:):{smiley\:)}`. When retrieving the host, resource, or value of the regex or wildcard segment, names are used unescaped without a backslash `\`.
 
+Instead of a regex pattern, a regex segment can have the name of a pattern type: "{id:int}". The built-in pattern types are int, uint, alpha, alnum, slug, uuid, date, hex, and base64url. Custom pattern types can be registered with the RegisterPatternType function. The templates with a pattern type and its regex pattern are considered the same. A pattern that consists only of a type name is always the pattern type. This changes the behavior of the templates like "{v:date}", which matched the word "date" literally before the pattern types were added. To match it literally, the pattern must be wrapped in a non-capturing group: "{v:(?:date)}". An existing type name can be registered again only with the same pattern, and its converter is kept unless it's registered again with the RegisterConverter function.
+
+	// Pattern templates with pattern types
+	"/users/{id:int}"
+	"/posts/{date:date}/{title:slug}"
+	"https:///files/{fileID:uuid}"
+
+The values of the regex and wildcard segments are strings. The HostPathValues type has typed getters such as GetInt and GetBool, and the generic Value function can parse a value with any function. The values of the segments with the int, uint, and date pattern types can be retrieved converted to an int, a uint, and a time.Time with the Args' PathValue method. The RegisterConverter function registers a pattern type with a converter.
+
+	nanomux.RegisterConverter(
+		"bool",
+		"true|false",
+		func(v string) (interface{}, error) {
+			return strconv.ParseBool(v)
+		},
+	)
+
+	var r = router.Resource("/features/{enabled:bool}")
+	// ...
+	var enabled, err = args.PathValue("enabled") // enabled is a bool.
+
 Constructors of the Host and Resource types and some methods take URL templates or path templates.
 
 	// URL templates
@@ -141,6 +174,45 @@ There is one restriction: the root resource cannot be registered under a host. I
 
 Handlers must return true if they respond to the request. Sometimes middlewares and the responder itself need to know whether the request was handled or not. For example, when the middleware responds to the request instead of calling the request passer, the responder may assume that none of its child resources responded to the request in its subtree, so it responds with "404 Not Found" to the request that was already responded to. To prevent this, the middleware's handler must return true if it responds to the request, or it must return the value returned from the argument handler.
 
+Handlers can also be written as an ErrHandler, which returns an error instead of writing the error response itself. The ErrHr converter converts an ErrHandler to a Handler. The returned error is recorded in the *Args and rendered after the request handler's middlewares return, so a middleware can get it with the Err method of the *Args. It's rendered with the error renderer of the responder, which can be set with the SetErrorRenderer method of the router, host, or resource and is inherited down the tree. NanoMux provides the RenderErrorAsText, RenderErrorAsJSON, and RenderErrorAsProblemJSON renderers. The status code and the message of the response can be given with an *HTTPError. Other errors are rendered as "500 Internal Server Error". The Handle methods of an Impl can also have the signature of the ErrHandler.
+
+	func GetUser(
+		w http.ResponseWriter,
+		r *http.Request,
+		args *nanomux.Args,
+	) error {
+		var user, err = findUser(args.HostPathValues().Get("id"))
+		if err != nil {
+			return nanomux.NewHTTPError(http.StatusNotFound, "user not found")
+		}
+
+		// ...
+	}
+
+	// ...
+
+	router.SetErrorRenderer(nanomux.RenderErrorAsProblemJSON)
+	router.SetURLHandlerFor("GET", "/users/{id}", nanomux.ErrHr(GetUser))
+
+The generic JSON converter converts a typed function to a Handler. The returned handler decodes the request's body by its media type, binds the host, path, and query values to the fields of the request type with the "path", "query", and "form" tags, calls the function, and encodes its response as JSON. The errors are rendered with the error renderer of the responder.
+
+	type GetUserRequest struct {
+		ID     int  `path:"id"`
+		Detail bool `query:"detail"`
+	}
+
+	func GetUser(
+		ctx context.Context,
+		req GetUserRequest,
+		args *nanomux.Args,
+	) (*User, error) {
+		// ...
+	}
+
+	// ...
+
+	router.SetURLHandlerFor("GET", "/users/{id:int}", nanomux.JSON(GetUser))
+
 Sometimes resources have to handle HTTP methods they don't support. NanoMux provides a default not allowed HTTP method handler that responds with a "405 Method Not Allowed" status code, listing all HTTP methods the resource supports in the "Allow" header. But when the host or resource needs a custom implementation, its SetHandlerFor method can be used to replace the default handler. To denote the not allowed HTTP method handler, the exclamation mark "!" must be used instead of an HTTP method.
 
 	func HandleNotAllowedMethod(
@@ -155,8 +227,24 @@ Sometimes resources have to handle HTTP methods they don't support. NanoMux prov
 
 	blogs.SetHandlerFor("!", HandleNotAllowedMethod)
 
+Several handlers can be set for the same HTTP method when they produce or consume different media types. The handler is selected by the q-values of the request's "Accept" header and by its "Content-Type" header. When none of the handlers is acceptable, the request is responded to with the "406 Not Acceptable" or "415 Unsupported Media Type" status codes.
+
+	blogs.SetHandlerFor("GET", GetBlogsAsJSON, nanomux.Produces("application/json"))
+	blogs.SetHandlerFor("GET", GetBlogsAsHTML, nanomux.Produces("text/html"))
+	blogs.SetHandlerFor("POST", PostBlogForm, nanomux.Consumes("multipart/form-data"))
+
 In addition to the not allowed HTTP method handler, if the host or resource has at least one HTTP method handler, NanoMux also provides a default OPTIONS HTTP method handler.
 
+When the host or resource is configured with the Config's HandlesHeadWithGet property, it handles HEAD requests with its GET handler if it doesn't have a HEAD handler. The response body written by the GET handler is discarded, but its length is reported in the "Content-Length" header. HEAD is then also listed in the "Allow" header.
+
+The router, host, and resource can have a CORS policy set with their SetCORSPolicy method. The policy is inherited by the hosts and resources below in the tree that don't have their own policy. The default OPTIONS handler answers the preflight requests using the policy and the HTTP methods of the responder, and the responses to the actual cross-origin requests are decorated with the policy's headers. A policy that allows any origin with an asterisk "*" alone cannot allow credentials, and setting it panics.
+
+	router.SetCORSPolicy(nanomux.CORSPolicy{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		MaxAge:         600,
+	})
+
 The host and resource allow setting a handler for a child resource in their subtree. If the subtree resource doesn't exist, it will be created.
 
 	func PostBlog(
@@ -484,6 +572,28 @@ The WrapRequestPasser, WrapRequestHandler, and WrapHandlerOf methods of the Host
 
 When calling the WrapHandlerOf, WrapPathHandlerOf, and WrapSubtreeHandlersOf methods, "*" may be used to denote all HTTP methods for which handlers exist. When "*" is used instead of an HTTP method, all the existing HTTP method handlers of the responder are wrapped.
 
+Middleware that records metrics or logs usually needs a label with a low cardinality instead of the request's path. The MatchedTemplate method of the *Args argument returns the URL template of the host or resource that is passing or handling the request, and the MatchedResources method returns the chain of the resources that matched the path segments. The Redirected, NotFound, MethodNotAllowed, and BadRequest methods report how the request was responded to. In the middleware of the request passer, these are available after the next handler returns. A middleware converted with the Mw function can retrieve the *Args argument with the ArgsFrom function.
+
+	func Log(next nanomux.Handler) nanomux.Handler {
+		return func(
+			w http.ResponseWriter,
+			r *http.Request,
+			args *nanomux.Args,
+		) bool {
+			var handled = next(w, r, args)
+			log.Println(r.Method, args.MatchedTemplate(), args.NotFound())
+			return handled
+		}
+	}
+
+	// ...
+
+	host.WrapRequestPasser(Log)
+
+The Router calls the hooks of its Tracer, set with the SetTracer method, when it starts routing a request, each time a host or resource matches a segment of the request's URL, before the request handler is called, and after the request is responded to. The tracing package adapts a tracer with the shape of the OpenTelemetry tracing API to these hooks, so the time spent on routing, the redirect decisions, and the time spent in the handlers can be seen in the traces.
+
+The metrics package provides a middleware that records request counts, latencies, in-flight requests, and response sizes labeled with the host and resource templates, and a handler that exposes them in the Prometheus text exposition format.
+
 Router
 
 The Router type is more suitable when multiple hosts with different root domains or subdomains are needed.
@@ -565,6 +675,71 @@ The Router type is more suitable when multiple hosts with different root domains
 		// ...
 	}
 
+The tree must not be changed while the router is serving requests, unless the changes are made with the Router's Update method. The Update method calls the passed function with a copy of the tree and, if the function succeeds, makes the copy the router's tree atomically. Requests that are being served finish on the previous tree, and the hosts and resources retrieved before the update must be retrieved from the router again. If the function returns an error or panics, the copy is discarded and the router's tree is left as it was. Once the first update is published, the tree can be changed only through the RouterTx passed to the function, and the other changes fail with the ErrOutsideUpdate error.
+
+	var err = router.Update(func(tx *nm.RouterTx) error {
+		tx.SetURLHandlerFor("GET", "http://forecast.example.com/tomorrow", GetTomorrowsForecast)
+		tx.UnregisterResourceAt("http://forecast.example.com/today")
+		return nil
+	})
+
+The functions and methods that create, register, unregister, and configure hosts and resources panic on invalid arguments and conflicts. Each of them has a TryTo counterpart that returns the error instead, for when the routes come from plugins or configuration and must be validated without recovering from panics. The TryTo counterparts exist for the NewHost, NewHostUsingConfig, NewDormantHost, NewDormantHostUsingConfig, NewResource, NewResourceUsingConfig, NewDormantResource, and NewDormantResourceUsingConfig functions. They also exist for the Router's Host, HostUsingConfig, RegisterHost, RegisteredHost, UnregisterHost, Resource, ResourceUsingConfig, RegisterResource, RegisterResourceUnder, RegisteredResource, UnregisterResourceAt, WrapRequestPasser, SetConfigurationForAll, WrapAllRequestPassers, WrapAllRequestHandlers, WrapAllHandlersOf, SetHandlerForNotFound, WrapHandlerOfNotFound, SetPermanentRedirectCode, SetRedirectHandler, WrapRedirectHandler, SetCORSPolicy, SetErrorRenderer, and SetTracer methods and for all its setter and wrapper methods with the At suffix, as well as its SetURLHandlerFor and WrapURLHandlerOf methods. The hosts and resources have the TryTo counterparts of their Resource, ResourceUsingConfig, RegisterResource, RegisterResourceUnder, RegisteredResource, UnregisterResource, UnregisterResourceAt, SetConfiguration, SetImplementation, SetHandlerFor, WrapRequestPasser, WrapRequestHandler, WrapHandlerOf, SetPermanentRedirectCode, SetRedirectHandler, WrapRedirectHandler, RedirectRequestTo, RedirectAnyRequestTo, SetHandlerForNotFound, WrapHandlerOfNotFound, SetCORSPolicy, SetErrorRenderer, SetConfigurationForSubtree, WrapSubtreeRequestPassers, WrapSubtreeRequestHandlers, and WrapSubtreeHandlersOf methods and of all their setter and wrapper methods with the At suffix, as well as their SetPathHandlerFor and WrapPathHandlerOf methods. The package-level functions that set the defaults, such as SetPermanentRedirectCode and SetHandlerForNotFound, and the methods that only retrieve values don't have TryTo counterparts. The registration errors, such as ErrDuplicateNameAmongSiblings and ErrConflictingSecurity, and the ErrNilArgument, which is also wrapped by the subpackages when they panic on a nil argument, can be checked with errors.Is. When the error is caused by a conflict, it's also a *RegistrationError with the operation, the template, and the existing responder, if there is one.
+
+	var err = router.TryToSetURLHandlerFor("GET", tmplFromPlugin, handlerFromPlugin)
+	if errors.Is(err, nm.ErrDuplicateNameAmongSiblings) {
+		var re *nm.RegistrationError
+		errors.As(err, &re)
+		// ...
+	}
+
+Routes can also be loaded from a JSON configuration with the Router's LoadRoutes method. The handlers and middlewares of the routes are referred to by their names in the Registry. The routes are validated with the same rules as the Router's methods, and all the errors are reported with their line and column in the configuration. The names missing in the Registry are reported with the ErrUnknownHandler and ErrUnknownMiddleware errors. If there is an error, none of the routes are loaded.
+
+	var f, err = os.Open("routes.json")
+	// ...
+
+	err = router.LoadRoutes(f, &nm.Registry{
+		Handlers: map[string]nm.Handler{
+			"getMainPage": GetMainPage,
+			"getNews":     GetNews,
+		},
+		Middlewares: map[string]nm.Middleware{
+			"checkCredentials": CheckCredentials,
+		},
+	})
+
+The Router's OpenAPI method exports the tree as an OpenAPI 3 document. The paths and their parameters are built from the resources' templates, with the regular expressions of the dynamic segments as the parameters' patterns, and the servers are built from the hosts' templates. The operations can be described with the Describe handler option or by the Impl types that implement the OperationDescriber interface. The OpenAPI specification requires a default value for each server variable, so the defaults of the hosts' dynamic segments must be passed in the OpenAPIInfo's ServerVariables. The catch-all path parameters have the "x-nanomux-catch-all" schema extension, as they match the slashes too. When the resources of different hosts have the same path, their operations share the path item, and the same HTTP method handled by more than one of them is reported with the ErrConflictingOperation error.
+
+	router.SetURLHandlerFor(
+		"GET",
+		"http://news.example.com/{category}",
+		GetNewsByCategory,
+		nm.Describe(nm.OpenAPIOperation{
+			Summary: "Returns the news of the category.",
+			Tags:    []string{"news"},
+		}),
+	)
+
+	var spec, err = router.OpenAPI(nm.OpenAPIInfo{
+		Title:   "News",
+		Version: "1.0",
+	})
+
+The routing of the Router can be tested with the nanomuxtest package. Its Harness serves the requests and reports which responder handled them, with what host and path values, and whether they were redirected or responded with 404 or 405. The responders are found with the Router's Resolve method, so the router is not modified.
+
+	var h = nanomuxtest.New(t, router)
+	h.Request("GET", "http://news.example.com/sports").Serve().
+		ExpectResponder("http://news.example.com/{category}").
+		ExpectHostPathValues(map[string]string{"category": "sports"})
+
+To find out how a request would be routed without serving it, the Router, Host, and Resource provide the Resolve method. Resolve runs the request through the same matching code the router uses to serve it, with the responders recording their decisions instead of responding, and returns the Resolution that lists the matched hosts and resources, the extracted host and path values, and the request handler, wrapped with its middlewares, that would be called. If the request would be redirected or responded with 404 or 405, the Resolution explains why and, for 404, at which segment the matching stopped and which templates were tried. The middlewares of the request passers and the handlers are not called.
+
+	var res, err = router.Resolve("GET", "http://news.example.com/sports/")
+	if err != nil {
+		// ...
+	}
+
+	fmt.Println(res)
+
 Converting http.Handler and http.HandlerFunc
 
 It is possible to use an http.Handler and an http.HandlerFunc with NanoMux. For that, NanoMux provides four converters: Hr, HrWithArgs, FnHr, and FnHrWithArgs. The Hr and HrWithArgs convert the http.Handler, while the FnHr and FnHrWithArgs convert the function with the signature of the http.HandlerFunc to the nanomux.Handler.
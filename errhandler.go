@@ -0,0 +1,307 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// --------------------------------------------------
+
+// ErrHandler is the type of function used for handling HTTP requests that
+// returns an error instead of writing the error response itself. The returned
+// error is rendered by the error renderer of the responder. ErrHandler can be
+// converted to a Handler with the ErrHr converter.
+type ErrHandler func(w http.ResponseWriter, r *http.Request, args *Args) error
+
+// ErrHr converts an ErrHandler to a nanomux.Handler. When the ErrHandler
+// returns an error, the returned handler records it in the *Args argument.
+// The error is rendered with the error renderer of the responder handling
+// the request after the request handler and its middlewares return. So the
+// middlewares can get the error with the Err method of the *Args.
+func ErrHr(eh ErrHandler) Handler {
+	if eh == nil {
+		panicWithErr("%w", ErrNilArgument)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		var err = eh(w, r, args)
+		if err != nil {
+			return handleErr(w, r, args, err)
+		}
+
+		return true
+	}
+}
+
+// handleErr records the error in the args to be rendered after the request
+// handler's middlewares return. If there are no args, the error is rendered
+// immediately.
+func handleErr(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+	err error,
+) bool {
+	if args == nil {
+		return errorRendererOfArgs(args)(w, r, err, args)
+	}
+
+	args.err = err
+	return true
+}
+
+// renderRecordedErr renders the error recorded in the args, if there is one.
+// It's called after the handler and its middlewares return. If there is no
+// error, the handler's return value is returned.
+func renderRecordedErr(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+	handled bool,
+) bool {
+	if args == nil || args.err == nil {
+		return handled
+	}
+
+	var err = args.err
+	args.err = nil
+	return errorRendererOfArgs(args)(w, r, err, args)
+}
+
+// --------------------------------------------------
+
+// HTTPError is an error that carries the status code and the details of the
+// response to be rendered.
+type HTTPError struct {
+	// Status is the HTTP status code of the response.
+	Status int
+
+	// Code is an application-specific error code.
+	Code string
+
+	// Message is the message to be sent to the client. When it's empty, the
+	// status text is sent.
+	Message string
+
+	// Details can contain any additional information that must be sent to
+	// the client. It's used by the JSON renderers.
+	Details interface{}
+
+	// Err is the underlying error. It's not sent to the client.
+	Err error
+}
+
+// NewHTTPError returns a new *HTTPError with the status code and message.
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// Error returns the message of the error. If the error has an underlying
+// error, its message is also included.
+func (he *HTTPError) Error() string {
+	var msg = he.message()
+	if he.Err != nil {
+		return msg + ": " + he.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (he *HTTPError) Unwrap() error {
+	return he.Err
+}
+
+// status returns the status code of the error. If the status code is not
+// set, 500 is returned.
+func (he *HTTPError) status() int {
+	if he.Status == 0 {
+		return http.StatusInternalServerError
+	}
+
+	return he.Status
+}
+
+// message returns the message of the error. If the message is empty, the
+// status text is returned.
+func (he *HTTPError) message() string {
+	if he.Message == "" {
+		return http.StatusText(he.status())
+	}
+
+	return he.Message
+}
+
+// asHTTPError returns the *HTTPError in the err's chain. If there is none, an
+// *HTTPError with the 500 status code wrapping the err is returned. The err's
+// message is not sent to the client.
+func asHTTPError(err error) *HTTPError {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		return he
+	}
+
+	return &HTTPError{Status: http.StatusInternalServerError, Err: err}
+}
+
+// --------------------------------------------------
+
+// ErrorRenderer is the type of function used to render errors returned from
+// the ErrHandler as responses.
+type ErrorRenderer func(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+	args *Args,
+) bool
+
+// RenderErrorAsText renders the error as a plain text response. If the err
+// is not an *HTTPError, it's rendered as "500 Internal Server Error".
+func RenderErrorAsText(
+	w http.ResponseWriter,
+	_ *http.Request,
+	err error,
+	_ *Args,
+) bool {
+	var he = asHTTPError(err)
+	http.Error(w, he.message(), he.status())
+	return true
+}
+
+// RenderErrorAsJSON renders the error as a JSON object with the status,
+// code, message, and details fields. If the err is not an *HTTPError, it's
+// rendered as "500 Internal Server Error".
+func RenderErrorAsJSON(
+	w http.ResponseWriter,
+	_ *http.Request,
+	err error,
+	_ *Args,
+) bool {
+	var he = asHTTPError(err)
+	writeJSONError(w, "application/json", he.status(), struct {
+		Status  int         `json:"status"`
+		Code    string      `json:"code,omitempty"`
+		Message string      `json:"message"`
+		Details interface{} `json:"details,omitempty"`
+	}{he.status(), he.Code, he.message(), he.Details})
+
+	return true
+}
+
+// RenderErrorAsProblemJSON renders the error as an RFC 7807 problem details
+// object with the "application/problem+json" media type. The code and
+// details of the *HTTPError are added as extension members. If the err is
+// not an *HTTPError, it's rendered as "500 Internal Server Error".
+func RenderErrorAsProblemJSON(
+	w http.ResponseWriter,
+	_ *http.Request,
+	err error,
+	_ *Args,
+) bool {
+	var he = asHTTPError(err)
+	var status = he.status()
+	writeJSONError(w, "application/problem+json", status, struct {
+		Type    string      `json:"type"`
+		Title   string      `json:"title"`
+		Status  int         `json:"status"`
+		Detail  string      `json:"detail,omitempty"`
+		Code    string      `json:"code,omitempty"`
+		Details interface{} `json:"details,omitempty"`
+	}{"about:blank", http.StatusText(status), status, he.Message, he.Code, he.Details})
+
+	return true
+}
+
+// writeJSONError writes the v as the JSON body of the error response.
+func writeJSONError(
+	w http.ResponseWriter,
+	contentType string,
+	status int,
+	v interface{},
+) {
+	var b, err = json.Marshal(v)
+	if err != nil {
+		// The details can't be marshaled.
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+
+		return
+	}
+
+	var h = w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+// -------------------------
+
+var commonErrorRenderer ErrorRenderer = RenderErrorAsText
+
+// errorRendererOf returns the error renderer of the responder or router. If
+// it doesn't have one, the renderer of its closest ancestor is returned. When
+// none of them has a renderer, the error is rendered as plain text.
+func errorRendererOf(p _Parent) ErrorRenderer {
+	for ; p != nil; p = p.parent() {
+		var er ErrorRenderer
+		switch p := p.(type) {
+		case *Router:
+			er = p.errorRenderer
+		case *Host:
+			er = p.errorRenderer
+		case *Resource:
+			er = p.errorRenderer
+		}
+
+		if er != nil {
+			return er
+		}
+	}
+
+	return commonErrorRenderer
+}
+
+// errorRendererOfArgs returns the error renderer of the responder handling
+// the request.
+func errorRendererOfArgs(args *Args) ErrorRenderer {
+	if args == nil || args._r == nil {
+		return commonErrorRenderer
+	}
+
+	return errorRendererOf(args._r)
+}
+
+// handleBadRequest marks the request as a bad request in the args and renders
+// the "400 Bad Request" error with the error renderer of the responder
+// handling the request. It's used when the request's path can't be unescaped.
+// When the request is being resolved, the resolution fails instead.
+func handleBadRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+	err error,
+) bool {
+	if args != nil {
+		if args.resolution != nil {
+			args.resolution.badSegment = true
+			return true
+		}
+
+		args.outcome = outcomeBadRequest
+	}
+
+	return errorRendererOfArgs(args)(
+		w, r,
+		&HTTPError{Status: http.StatusBadRequest, Err: err},
+		args,
+	)
+}
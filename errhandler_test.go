@@ -0,0 +1,211 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// --------------------------------------------------
+
+func TestHTTPError(t *testing.T) {
+	var he = NewHTTPError(http.StatusNotFound, "user not found")
+	checkValue(t, he.Error(), "user not found")
+
+	var errBase = fmt.Errorf("no rows")
+	he = &HTTPError{Status: http.StatusNotFound, Err: errBase}
+	checkValue(t, he.Error(), "Not Found: no rows")
+	checkValue(t, errors.Is(he, errBase), true)
+
+	var err = fmt.Errorf("wrapped: %w", he)
+	checkValue(t, asHTTPError(err), he)
+
+	he = asHTTPError(errBase)
+	checkValue(t, he.status(), http.StatusInternalServerError)
+	checkValue(t, he.message(), "Internal Server Error")
+}
+
+func TestErrorRenderers(t *testing.T) {
+	var he = &HTTPError{
+		Status:  http.StatusBadRequest,
+		Code:    "invalid_name",
+		Message: "name is too long",
+		Details: map[string]int{"max": 10},
+	}
+
+	var cases = []struct {
+		name            string
+		renderer        ErrorRenderer
+		err             error
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			"text",
+			RenderErrorAsText,
+			he,
+			http.StatusBadRequest,
+			"text/plain; charset=utf-8",
+			"name is too long\n",
+		},
+		{
+			"text internal",
+			RenderErrorAsText,
+			fmt.Errorf("secret"),
+			http.StatusInternalServerError,
+			"text/plain; charset=utf-8",
+			"Internal Server Error\n",
+		},
+		{
+			"json",
+			RenderErrorAsJSON,
+			he,
+			http.StatusBadRequest,
+			"application/json",
+			`{"status":400,"code":"invalid_name","message":"name is too long","details":{"max":10}}`,
+		},
+		{
+			"json internal",
+			RenderErrorAsJSON,
+			fmt.Errorf("secret"),
+			http.StatusInternalServerError,
+			"application/json",
+			`{"status":500,"message":"Internal Server Error"}`,
+		},
+		{
+			"problem json",
+			RenderErrorAsProblemJSON,
+			he,
+			http.StatusBadRequest,
+			"application/problem+json",
+			`{"type":"about:blank","title":"Bad Request","status":400,"detail":"name is too long","code":"invalid_name","details":{"max":10}}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			var r = httptest.NewRequest("GET", "/", nil)
+			checkValue(t, c.renderer(w, r, c.err, nil), true)
+			checkValue(t, w.Code, c.wantStatusCode)
+			checkValue(t, w.Header().Get("Content-Type"), c.wantContentType)
+			checkValue(t, w.Body.String(), c.wantBody)
+		})
+	}
+}
+
+type _ErrImpl struct{}
+
+func (impl *_ErrImpl) HandleGet(
+	w http.ResponseWriter,
+	_ *http.Request,
+	_ *Args,
+) error {
+	w.Write([]byte("GET"))
+	return nil
+}
+
+func (impl *_ErrImpl) HandleDelete(
+	_ http.ResponseWriter,
+	_ *http.Request,
+	_ *Args,
+) error {
+	return NewHTTPError(http.StatusForbidden, "")
+}
+
+func TestErrHr(t *testing.T) {
+	testPanicker(t, true, func() { ErrHr(nil) })
+
+	var ro = NewRouter()
+	ro.SetURLHandlerFor(
+		"get",
+		"http://example.com/a/b",
+		ErrHr(func(http.ResponseWriter, *http.Request, *Args) error {
+			return NewHTTPError(http.StatusConflict, "conflict")
+		}),
+	)
+
+	ro.SetImplementationAt("http://example.com/a/c", &_ErrImpl{})
+
+	var w = httptest.NewRecorder()
+	var r = httptest.NewRequest("GET", "http://example.com/a/b", nil)
+	ro.ServeHTTP(w, r)
+	checkValue(t, w.Code, http.StatusConflict)
+	checkValue(t, w.Body.String(), "conflict\n")
+
+	ro.SetErrorRenderer(RenderErrorAsJSON)
+	ro.RegisteredResource("http://example.com/a/c").SetErrorRenderer(
+		RenderErrorAsProblemJSON,
+	)
+
+	checkValue(
+		t,
+		fmt.Sprintf("%p", ro.RegisteredResource(
+			"http://example.com/a/b",
+		).ErrorRenderer()),
+		fmt.Sprintf("%p", RenderErrorAsJSON),
+	)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "http://example.com/a/b", nil)
+	ro.ServeHTTP(w, r)
+	checkValue(t, w.Code, http.StatusConflict)
+	checkValue(t, w.Header().Get("Content-Type"), "application/json")
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "http://example.com/a/c", nil)
+	ro.ServeHTTP(w, r)
+	checkValue(t, w.Code, http.StatusOK)
+	checkValue(t, w.Body.String(), "GET")
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("DELETE", "http://example.com/a/c", nil)
+	ro.ServeHTTP(w, r)
+	checkValue(t, w.Code, http.StatusForbidden)
+	checkValue(t, w.Header().Get("Content-Type"), "application/problem+json")
+}
+
+func TestErrHr_middleware(t *testing.T) {
+	var errConflict = NewHTTPError(http.StatusConflict, "conflict")
+	var ro = NewRouter()
+	ro.SetURLHandlerFor(
+		"get",
+		"http://example.com/a",
+		ErrHr(func(http.ResponseWriter, *http.Request, *Args) error {
+			return errConflict
+		}),
+	)
+
+	var w = httptest.NewRecorder()
+	var mwErr error
+	var mwBody string
+	ro.WrapRequestHandlerAt(
+		"http://example.com/a",
+		func(next Handler) Handler {
+			return func(
+				w http.ResponseWriter,
+				r *http.Request,
+				args *Args,
+			) bool {
+				var handled = next(w, r, args)
+				mwErr = args.Err()
+				mwBody = w.(*httptest.ResponseRecorder).Body.String()
+				return handled
+			}
+		},
+	)
+
+	ro.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/a", nil))
+
+	// The middleware gets the error before it's rendered.
+	checkValue(t, mwErr, error(errConflict))
+	checkValue(t, mwBody, "")
+	checkValue(t, w.Code, http.StatusConflict)
+	checkValue(t, w.Body.String(), "conflict\n")
+}
@@ -11,44 +11,15 @@ import (
 
 // --------------------------------------------------
 
-var (
-	// errNilArgument is returned when one of the function arguments is nil.
-	errNilArgument = fmt.Errorf("nil argument")
+// ErrNilArgument is returned when one of the function arguments is nil. The
+// subpackages also wrap it when they panic on a nil argument.
+var ErrNilArgument = fmt.Errorf("nil argument")
 
+var (
 	// errInvalidArgument is returned when one of the function arguments is
 	// not valid for use.
 	errInvalidArgument = fmt.Errorf("invalid argument")
 
-	// errConflictingHost is returned when there is a conflict between the
-	// resource's host and its parent resource's host or a host in a URL
-	// template. Conflict can be the presence or absence of the host or a
-	// difference in a host template.
-	errConflictingHost = fmt.Errorf("conflicting host")
-
-	// errConflictingPath is returned when there is a difference between a
-	// resource's prefix path and a prefix path in a URL template.
-	errConflictingPath = fmt.Errorf("conflicting path")
-
-	// errConflictingPathSegment is returned when there is a difference between
-	// one of the resource's prefix path segments and its corresponding path
-	// segment in a URL template.
-	errConflictingPathSegment = fmt.Errorf("conflicting path segment")
-
-	// errConflictingSecurity is returned when the argument URL template has a
-	// different scheme from the resource's scheme, or the resource is insecure
-	// (https is not required by the resource to respond), and the argument
-	// config has the RedirectInsecureRequest property set.
-	errConflictingSecurity = fmt.Errorf("conflicting security")
-
-	// errConflictingTrailingSlash is returned when the argument URL template
-	// has a different trailing slash property than the one the resource was
-	// configured with.
-	errConflictingTrailingSlash = fmt.Errorf("conflicting trailing slash")
-
-	// ErrConfictingConfig is returned when the argument config is different
-	// from the resource's configuration.
-	errConflictingConfig = fmt.Errorf("conflicting config")
-
 	// errEmptyHostTemplate is returned when a host is required but its
 	// template is empty or the URL template doesn't contain a host template.
 	errEmptyHostTemplate = fmt.Errorf("empty host template")
@@ -68,82 +39,187 @@ var (
 	// path template.
 	errUnwantedPathTemplate = fmt.Errorf("unwanted path template")
 
-	// errNonRouterParent is returned on an attempt to register a host or a root
+	// errAmbiguousName is returned when more than one responder in the tree
+	// has the name used to find a responder.
+	errAmbiguousName = fmt.Errorf("ambiguous name")
+
+	// errNoHTTPMethod is returned when the HTTP methods argument string is
+	// empty.
+	errNoHTTPMethod = fmt.Errorf("no HTTP method has been given")
+
+	// errNoHandlerExists is returned on an attempt to wrap a non-existent
+	// handler of the HTTP method.
+	errNoHandlerExists = fmt.Errorf("no handler exists")
+
+	// errNoMiddleware is returned when the middleware argument has a nil value.
+	errNoMiddleware = fmt.Errorf("no middleware has been provided")
+)
+
+// --------------------------------------------------
+
+// Registration errors.
+var (
+	// ErrConflictingHost is returned when there is a conflict between the
+	// resource's host and its parent resource's host or a host in a URL
+	// template. Conflict can be the presence or absence of the host or a
+	// difference in a host template.
+	ErrConflictingHost = fmt.Errorf("conflicting host")
+
+	// ErrConflictingPath is returned when there is a difference between a
+	// resource's prefix path and a prefix path in a URL template.
+	ErrConflictingPath = fmt.Errorf("conflicting path")
+
+	// ErrConflictingPathSegment is returned when there is a difference between
+	// one of the resource's prefix path segments and its corresponding path
+	// segment in a URL template.
+	ErrConflictingPathSegment = fmt.Errorf("conflicting path segment")
+
+	// ErrConflictingSecurity is returned when the argument URL template has a
+	// different scheme from the resource's scheme, or the resource is insecure
+	// (https is not required by the resource to respond), and the argument
+	// config has the RedirectInsecureRequest property set.
+	ErrConflictingSecurity = fmt.Errorf("conflicting security")
+
+	// ErrConflictingTrailingSlash is returned when the argument URL template
+	// has a different trailing slash property than the one the resource was
+	// configured with.
+	ErrConflictingTrailingSlash = fmt.Errorf("conflicting trailing slash")
+
+	// ErrConflictingConfig is returned when the argument config is different
+	// from the resource's configuration.
+	ErrConflictingConfig = fmt.Errorf("conflicting config")
+
+	// ErrNonRouterParent is returned on an attempt to register a host or a root
 	// resource under another host or resource.
-	errNonRouterParent = fmt.Errorf("non-router parent")
+	ErrNonRouterParent = fmt.Errorf("non-router parent")
 
-	// errDuplicateHostTemplate is returned when registering a new host if there
+	// ErrCatchAllParent is returned on an attempt to register a resource
+	// under a resource with a catch-all template.
+	ErrCatchAllParent = fmt.Errorf("catch-all parent")
+
+	// ErrDuplicateHostTemplate is returned when registering a new host if there
 	// is another host with the same template and both of them can handle a
 	// request.
-	errDuplicateHostTemplate = fmt.Errorf("duplicate host template")
+	ErrDuplicateHostTemplate = fmt.Errorf("duplicate host template")
 
-	// errDuplicateResourceTemplate is returned when registering a new resource
+	// ErrDuplicateResourceTemplate is returned when registering a new resource
 	// if there is another resource with the same template and both of them can
 	// handle a request.
-	errDuplicateResourceTemplate = fmt.Errorf("duplicate resource template")
+	ErrDuplicateResourceTemplate = fmt.Errorf("duplicate resource template")
 
-	// errDuplicateNameInTheURL is returned when a new resource's name is not
+	// ErrDuplicateNameInTheURL is returned when a new resource's name is not
 	// unique in its URL.
-	errDuplicateNameInTheURL = fmt.Errorf("duplicate name in the URL")
+	ErrDuplicateNameInTheURL = fmt.Errorf("duplicate name in the URL")
 
-	// errDuplicateValueNameInTheURL is returned when one of the value names
+	// ErrDuplicateValueNameInTheURL is returned when one of the value names
 	// in the resource's template is a duplicate of a value name in the host's
 	// or another resource's template.
-	errDuplicateValueNameInTheURL = fmt.Errorf(
+	ErrDuplicateValueNameInTheURL = fmt.Errorf(
 		"duplicate value name in the URL",
 	)
 
-	// errDuplicateNameAmongSiblings is returned when a new resource's name
+	// ErrDuplicateNameAmongSiblings is returned when a new resource's name
 	// is not unique among the resources registered under the same host or
 	// resource.
-	errDuplicateNameAmongSiblings = fmt.Errorf("duplicate name among siblings")
+	ErrDuplicateNameAmongSiblings = fmt.Errorf("duplicate name among siblings")
 
-	// errDormantHost is returned when a host doesn't have a handler for any
+	// ErrDormantHost is returned when a host doesn't have a handler for any
 	// HTTP method and an attempt to set a handler for the not allowed HTTP
 	// methods or to wrap one of the HTTP method handlers occurs.
-	errDormantHost = fmt.Errorf("dormant host")
+	ErrDormantHost = fmt.Errorf("dormant host")
 
-	// errDormantResource is returned when a resource doesn't have a handler
+	// ErrDormantResource is returned when a resource doesn't have a handler
 	// for any HTTP method and an attempt to set a handler for the not allowed
 	// HTTP methods or to wrap one of the HTTP method handlers occurs.
-	errDormantResource = fmt.Errorf("dormant resource")
+	ErrDormantResource = fmt.Errorf("dormant resource")
 
-	// errRegisteredHost is returned on an attempt to register an already
+	// ErrRegisteredHost is returned on an attempt to register an already
 	// registered host. A host is considered registered even if it is registered
 	// under a different router.
-	errRegisteredHost = fmt.Errorf("registered host")
+	ErrRegisteredHost = fmt.Errorf("registered host")
 
-	// errRegisteredResource is returned on an attempt to register an already
+	// ErrRegisteredResource is returned on an attempt to register an already
 	// registered resource. A resource is considered registered even if it was
 	// registered under a different router, host, or resource.
-	errRegisteredResource = fmt.Errorf("registered resource")
+	ErrRegisteredResource = fmt.Errorf("registered resource")
 
-	// errNonExistentHost is returned on an attempt to change the state of a
+	// ErrNonExistentHost is returned on an attempt to change the state of a
 	// non-existent host.
-	errNonExistentHost = fmt.Errorf("non-existent host")
+	ErrNonExistentHost = fmt.Errorf("non-existent host")
 
-	// errNonExistentResource is returned on an attempt to change the state of a
+	// ErrNonExistentResource is returned on an attempt to change the state of a
 	// non-existent resource.
-	errNonExistentResource = fmt.Errorf("non-existent resource")
-
-	// errNoHTTPMethod is returned when the HTTP methods argument string is
-	// empty.
-	errNoHTTPMethod = fmt.Errorf("no HTTP method has been given")
-
-	// errNoHandlerExists is returned on an attempt to wrap a non-existent
-	// handler of the HTTP method.
-	errNoHandlerExists = fmt.Errorf("no handler exists")
+	ErrNonExistentResource = fmt.Errorf("non-existent resource")
 
-	// errConflictingStatusCode is returned on an attempt to set a different
+	// ErrConflictingStatusCode is returned on an attempt to set a different
 	// value for a status code other than the expected value. This is the case
 	// of customizable redirection status codes, where one of the
 	// StatusMovedPermanently and StatusPermanentRedirect can be chosen.
-	errConflictingStatusCode = fmt.Errorf("conflicting status code")
-
-	// errNoMiddleware is returned when the middleware argument has a nil value.
-	errNoMiddleware = fmt.Errorf("no middleware has been provided")
+	ErrConflictingStatusCode = fmt.Errorf("conflicting status code")
+
+	// ErrOutsideUpdate is returned on an attempt to change the tree of a
+	// router that has published an update, unless the change is made through
+	// the RouterTx of the Router's Update method. It's also returned when a
+	// host or resource that is no longer in the router's tree after an update
+	// is changed.
+	ErrOutsideUpdate = fmt.Errorf("change outside update")
 )
 
+// RegistrationError describes the conflict of a host or resource being
+// registered or configured with an existing one in the tree or with its own
+// template. Its Err is one of the registration errors, so it can be checked
+// with errors.Is. All the registration errors except ErrDormantHost,
+// ErrDormantResource, ErrNonExistentHost, ErrNonExistentResource,
+// ErrConflictingStatusCode, and ErrOutsideUpdate are returned as a
+// RegistrationError.
+type RegistrationError struct {
+	// Op is the operation that failed. It's "register" when a host or
+	// resource is being added to the tree, "configure" when the scheme,
+	// trailing slash, or configuration differs from the existing responder's
+	// or the template's, and "validate" when a URL template doesn't match
+	// the existing responder's URL.
+	Op string
+
+	// Template is the template that caused the conflict.
+	Template string
+
+	// Host is the existing conflicting host, if any.
+	Host *Host
+
+	// Resource is the existing conflicting resource, if any.
+	Resource *Resource
+
+	// Err is the registration error.
+	Err error
+}
+
+func (re *RegistrationError) Error() string {
+	return fmt.Sprintf("%s %q: %v", re.Op, re.Template, re.Err)
+}
+
+// Unwrap returns the registration error.
+func (re *RegistrationError) Unwrap() error {
+	return re.Err
+}
+
+// newRegistrationErr returns the RegistrationError with the caller's name
+// prefixed like the errors returned from newErr.
+func newRegistrationErr(
+	op, tmplStr string,
+	existing _Responder,
+	err error,
+) error {
+	var re = &RegistrationError{Op: op, Template: tmplStr, Err: err}
+	switch _r := existing.(type) {
+	case *Host:
+		re.Host = _r
+	case *Resource:
+		re.Resource = _r
+	}
+
+	return createErr(2, "%w", re)
+}
+
 // --------------------------------------------------
 
 // Template errors.
@@ -177,6 +253,23 @@ var (
 	ErrDifferentNames      = fmt.Errorf("different names")
 )
 
+// ErrConflictingOperation is returned from the Router's OpenAPI method when
+// the resources of different hosts, or of a host and the router, have the
+// same path and handle the same HTTP method. Their operations cannot be
+// described in the same path item.
+var ErrConflictingOperation = fmt.Errorf("conflicting operation")
+
+// Route configuration errors.
+var (
+	// ErrUnknownHandler is returned from the Router's LoadRoutes method when
+	// a handler name is not in the Registry.
+	ErrUnknownHandler = fmt.Errorf("unknown handler")
+
+	// ErrUnknownMiddleware is returned from the Router's LoadRoutes method
+	// when a middleware name is not in the Registry.
+	ErrUnknownMiddleware = fmt.Errorf("unknown middleware")
+)
+
 // --------------------------------------------------
 func createErr(skipCount int, description string, args ...interface{}) error {
 	if pc, _, _, ok := runtime.Caller(skipCount); ok {
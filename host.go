@@ -4,10 +4,7 @@
 package nanomux
 
 import (
-	"net"
 	"net/http"
-	"net/url"
-	"strings"
 )
 
 // --------------------------------------------------
@@ -22,7 +19,7 @@ type Host struct {
 // createDormantHost creates a dormant, not configured host.
 func createDormantHost(tmpl *Template) (*Host, error) {
 	if tmpl == nil {
-		return nil, newErr("%w", errNilArgument)
+		return nil, newErr("%w", ErrNilArgument)
 	}
 
 	if tmpl.IsWildcard() {
@@ -59,7 +56,12 @@ func createHost(tmplStr string, impl Impl, config *Config) (*Host, error) {
 	if config != nil {
 		config.Secure, config.HasTrailingSlash = secure, tslash
 		if config.RedirectsInsecureRequest && !secure {
-			return nil, newErr("%w", errConflictingSecurity)
+			return nil, newRegistrationErr(
+				"configure",
+				tmplStr,
+				nil,
+				ErrConflictingSecurity,
+			)
 		}
 
 		var tcfs = config.asFlags()
@@ -97,7 +99,7 @@ func createHost(tmplStr string, impl Impl, config *Config) (*Host, error) {
 // host is a subtree handler and should respond to the request. It has no
 // effect on the host itself. The template cannot be a wildcard template.
 func NewDormantHost(hostTmplStr string) *Host {
-	var h, err = createHost(hostTmplStr, nil, nil)
+	var h, err = TryToNewDormantHost(hostTmplStr)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
@@ -105,6 +107,17 @@ func NewDormantHost(hostTmplStr string) *Host {
 	return h
 }
 
+// TryToNewDormantHost is like NewDormantHost but returns an error instead of
+// panicking.
+func TryToNewDormantHost(hostTmplStr string) (*Host, error) {
+	var h, err = createHost(hostTmplStr, nil, nil)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return h, nil
+}
+
 // NewDormantHostUsingConfig returns a new dormant host (without HTTP method
 // hanlders).
 //
@@ -115,7 +128,7 @@ func NewDormantHost(hostTmplStr string) *Host {
 // The config's Secure and TrailingSlash values are ignored and may not be
 // set. The host template cannot be a wildcard template.
 func NewDormantHostUsingConfig(hostTmplStr string, config Config) *Host {
-	var h, err = createHost(hostTmplStr, nil, &config)
+	var h, err = TryToNewDormantHostUsingConfig(hostTmplStr, config)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
@@ -123,6 +136,20 @@ func NewDormantHostUsingConfig(hostTmplStr string, config Config) *Host {
 	return h
 }
 
+// TryToNewDormantHostUsingConfig is like NewDormantHostUsingConfig but returns
+// an error instead of panicking.
+func TryToNewDormantHostUsingConfig(
+	hostTmplStr string,
+	config Config,
+) (*Host, error) {
+	var h, err = createHost(hostTmplStr, nil, &config)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return h, nil
+}
+
 // NewHost returns a new host.
 //
 // The template's scheme and trailing slash values are used to configure the
@@ -153,16 +180,26 @@ func NewDormantHostUsingConfig(hostTmplStr string, config Config) *Host {
 // 	// ...
 // 	var exampleHost = NewHost("https://example.com", &ExampleHost{})
 func NewHost(hostTmplStr string, impl Impl) *Host {
+	var h, err = TryToNewHost(hostTmplStr, impl)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return h
+}
+
+// TryToNewHost is like NewHost but returns an error instead of panicking.
+func TryToNewHost(hostTmplStr string, impl Impl) (*Host, error) {
 	if impl == nil {
-		panicWithErr("%w", errNilArgument)
+		return nil, newErr("%w", ErrNilArgument)
 	}
 
 	var h, err = createHost(hostTmplStr, impl, nil)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
-	return h
+	return h, nil
 }
 
 // NewHostUsingConfig returns a new host.
@@ -205,16 +242,31 @@ func NewHostUsingConfig(
 	impl Impl,
 	config Config,
 ) *Host {
+	var h, err = TryToNewHostUsingConfig(hostTmplStr, impl, config)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return h
+}
+
+// TryToNewHostUsingConfig is like NewHostUsingConfig but returns an error
+// instead of panicking.
+func TryToNewHostUsingConfig(
+	hostTmplStr string,
+	impl Impl,
+	config Config,
+) (*Host, error) {
 	if impl == nil {
-		panicWithErr("%w", errNilArgument)
+		return nil, newErr("%w", ErrNilArgument)
 	}
 
 	var h, err = createHost(hostTmplStr, impl, &config)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
-	return h
+	return h, nil
 }
 
 // -------------------------
@@ -222,38 +274,37 @@ func NewHostUsingConfig(
 // ServeHTTP is the Host's implementation of the http.Handler interface.
 // It is called when the host is used directly.
 func (hb *Host) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var host = r.Host
-	if host == "" {
-		host = r.URL.Host
-	}
-
 	var args = getArgs(r.URL, hb.derived)
-	if host != "" {
-		if strings.LastIndexByte(host, ':') >= 0 {
-			var h, _, err = net.SplitHostPort(host)
-			if err == nil {
-				host = h
-			}
-		}
+	hb.serve(w, r, args)
+	putArgsInThePool(args)
+}
 
+// serve passes the request to the host's request receiver if the host's
+// template matches the host segment of the request's URL. Otherwise, the
+// not-found handler is called. It's used by the ServeHTTP and Resolve
+// methods.
+func (hb *Host) serve(w http.ResponseWriter, r *http.Request, args *Args) {
+	var host = requestHost(r)
+	if host != "" {
 		var matched bool
-		matched, args.hostPathValues = hb.Template().Match(
+		matched, args.hostPathValues = hb.Template().match(
 			host,
 			args.hostPathValues,
+			hb.IsCaseInsensitive(),
 		)
 
 		if matched {
+			args.matchedResponder(r, host)
 			if !hb.requestReceiver(w, r, args) {
-				notFoundResourceHandler(w, r, args)
+				handleNotFound(hb.derived, w, r, args)
 			}
 
-			putArgsInThePool(args)
 			return
 		}
 	}
 
-	notFoundResourceHandler(w, r, args)
-	putArgsInThePool(args)
+	args.explainNotFound(host, reasonNoMatchingHost, hb.ownTemplates)
+	handleNotFound(hb.derived, w, r, args)
 }
 
 // handleOrPassRequest handles the request if the host's template matches the
@@ -281,7 +332,7 @@ func (hb *Host) handleOrPassRequest(
 		}
 
 		args.nextPathSegment() // First call returns '/'.
-		if hb.requestPasser(w, r, args) {
+		if hb.requestPasserOf(args)(w, r, args) {
 			return true
 		}
 
@@ -290,86 +341,42 @@ func (hb *Host) handleOrPassRequest(
 
 		if !hb.IsSubtreeHandler() {
 			// Unreachable.
-			return notFoundResourceHandler(w, r, args)
+			args.explainNotFound("", reasonNoMatchingResource, nil)
+			return handleNotFound(hb.derived, w, r, args)
 		}
 	}
 
 	if !hb.canHandleRequest() && hb.requestRedirector == nil {
-		return notFoundResourceHandler(w, r, args)
-	}
+		if len(args.path) < 2 {
+			// The request's URL has no path segments, so the trailing
+			// optional child resource handles the request with its default
+			// value.
+			args.nextPathSegment() // First call returns '/'.
+			if hb.passRequestToOptionalResource(w, r, args) {
+				return true
+			}
 
-	var newURL *url.URL
-	if r.TLS == nil && hb.IsSecure() {
-		if !hb.RedirectsInsecureRequest() {
-			return notFoundResourceHandler(w, r, args)
+			args._r = hb.derived
 		}
 
-		newURL = cloneRequestURL(r)
-		newURL.Scheme = "https"
+		args.explainNotFound("", reasonNoHandler, nil)
+		return handleNotFound(hb.derived, w, r, args)
 	}
 
-	// If the path was cleaned and the host doesn't allow unclean paths,
-	// then the request will be redirected.
-	if args.cleanPath && !hb.IsLenientOnUncleanPath() {
-		if newURL == nil {
-			newURL = cloneRequestURL(r)
-		}
-
-		newURL.Path = args.path
+	var newURL, ok = hb.commonRedirectURL(r, args)
+	if !ok {
+		return handleNotFound(hb.derived, w, r, args)
 	}
 
-	if hb.IsSubtreeHandler() {
-		// The path must have at least three characters for it to have
-		// a trailing slash.
-		if len(args.path) > 2 && !hb.IsLenientOnTrailingSlash() {
-			if hb.HasTrailingSlash() && !args.pathHasTrailingSlash() {
-				if hb.IsStrictOnTrailingSlash() {
-					return notFoundResourceHandler(w, r, args)
-				}
-
-				if newURL == nil {
-					newURL = cloneRequestURL(r)
-				}
-
-				newURL.Path += "/"
-			} else if !hb.HasTrailingSlash() && args.pathHasTrailingSlash() {
-				if hb.IsStrictOnTrailingSlash() {
-					return notFoundResourceHandler(w, r, args)
-				}
-
-				if newURL == nil {
-					newURL = cloneRequestURL(r)
-				}
-
-				newURL.Path = newURL.Path[:len(newURL.Path)-1]
-			}
+	// The path must have at least three characters for it to have a
+	// trailing slash.
+	if hb.IsSubtreeHandler() && len(args.path) > 2 &&
+		!hb.IsLenientOnTrailingSlash() {
+		newURL, ok = hb.trailingSlashRedirectURL(newURL, r, args)
+		if !ok {
+			return handleNotFound(hb.derived, w, r, args)
 		}
 	}
 
-	if newURL != nil {
-		if newURL.Scheme == "" {
-			if r.TLS == nil {
-				newURL.Scheme = "http"
-			} else {
-				newURL.Scheme = "https"
-			}
-		}
-
-		var prc = permanentRedirectCode
-		if hb.permanentRedirectCode > 0 {
-			prc = hb.permanentRedirectCode
-		}
-
-		if hb.redirectHandler != nil {
-			return hb.redirectHandler(w, r, newURL.String(), prc, args)
-		}
-
-		return commonRedirectHandler(w, r, newURL.String(), prc, args)
-	}
-
-	if hb.requestRedirector != nil {
-		return hb.requestRedirector(w, r, args)
-	}
-
-	return hb.requestHandler(w, r, args)
+	return hb.redirectOrHandle(newURL, w, r, args)
 }
@@ -0,0 +1,491 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+// Package metrics provides a middleware that records the metrics of the
+// requests handled by the nanomux hosts and resources, and a handler that
+// exposes them in the Prometheus text exposition format.
+//
+// The metrics are labeled with the host template, the resource's path
+// template, and the request's method instead of the request's URL, so their
+// cardinality stays low. The host and resource labels are the host and path
+// parts of the URL template returned by the Args' MatchedTemplate method. The
+// methods for which the host or resource has no handler are labeled "other".
+// The following metrics are recorded:
+//
+// 	nanomux_requests_total               counter, with the "code" label
+// 	nanomux_request_duration_seconds     histogram
+// 	nanomux_requests_in_flight           gauge
+// 	nanomux_response_size_bytes          histogram
+//
+// The middleware wraps the request handlers, so only the requests that
+// reach a host's or resource's request handler are recorded. Redirected and
+// not-found requests are not recorded.
+//
+// Example:
+// 	var m = metrics.New()
+// 	var router = nanomux.NewRouter()
+// 	router.SetURLHandlerFor("GET", "http://example.com/users/{id}", GetUser)
+// 	router.SetURLHandlerFor("GET", "http://example.com/metrics", m.Handler)
+//
+// 	// ...
+//
+// 	router.WrapAllRequestHandlers(m.Middleware)
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shohruhadham/nanomux"
+)
+
+// --------------------------------------------------
+
+// DefaultDurationBuckets are the default upper bounds of the request
+// duration histogram's buckets in seconds.
+var DefaultDurationBuckets = []float64{
+	.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10,
+}
+
+// DefaultSizeBuckets are the default upper bounds of the response size
+// histogram's buckets in bytes.
+var DefaultSizeBuckets = []float64{
+	100, 1000, 10000, 100000, 1000000, 10000000,
+}
+
+// Config contains the configuration of the Metrics.
+type Config struct {
+	// Namespace is the prefix of the metric names. If it's empty, "nanomux"
+	// is used.
+	Namespace string
+
+	// DurationBuckets are the upper bounds of the request duration
+	// histogram's buckets in seconds. If they are empty, the
+	// DefaultDurationBuckets are used.
+	DurationBuckets []float64
+
+	// SizeBuckets are the upper bounds of the response size histogram's
+	// buckets in bytes. If they are empty, the DefaultSizeBuckets are used.
+	SizeBuckets []float64
+}
+
+// --------------------------------------------------
+
+// OtherMethod is the method label of the requests with the methods for which
+// the host or resource has no handler.
+const OtherMethod = "other"
+
+// _Labels are the labels of the series.
+type _Labels struct {
+	host, resource, method string
+}
+
+// labelsOf returns the labels of the request being handled by the host or
+// resource in the args.
+func labelsOf(r *http.Request, args *nanomux.Args) _Labels {
+	var ls = _Labels{method: methodLabelOf(r, args)}
+	ls.host, ls.resource = splitTemplate(args.MatchedTemplate())
+	return ls
+}
+
+// splitTemplate splits the URL template into its host and path templates.
+// The scheme is dropped.
+func splitTemplate(tmpl string) (host, path string) {
+	if i := strings.Index(tmpl, "://"); i >= 0 {
+		tmpl = tmpl[i+3:]
+	} else {
+		// The template of a resource without a host.
+		return "", tmpl
+	}
+
+	if i := strings.IndexByte(tmpl, '/'); i >= 0 {
+		return tmpl[:i], tmpl[i:]
+	}
+
+	return tmpl, ""
+}
+
+// methodLabelOf returns the request's method if the host or resource in the
+// args has a handler for it, including HEAD when it's handled by the GET
+// handler. Otherwise, OtherMethod is returned, so the requests with arbitrary
+// methods don't add new series.
+func methodLabelOf(r *http.Request, args *nanomux.Args) string {
+	var ms []string
+	if rs := args.CurrentResource(); rs != nil {
+		ms = rs.AllowedHTTPMethods()
+	} else if h := args.Host(); h != nil {
+		ms = h.AllowedHTTPMethods()
+	}
+
+	for _, m := range ms {
+		if m == r.Method {
+			return m
+		}
+	}
+
+	return OtherMethod
+}
+
+// less is used to sort the series by their labels.
+func (ls _Labels) less(ols _Labels) bool {
+	if ls.host != ols.host {
+		return ls.host < ols.host
+	}
+
+	if ls.resource != ols.resource {
+		return ls.resource < ols.resource
+	}
+
+	return ls.method < ols.method
+}
+
+// -------------------------
+
+// _Histogram counts the observations in buckets.
+type _Histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// observe adds the value to the histogram with the bucket upper bounds.
+func (h *_Histogram) observe(bounds []float64, v float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(bounds))
+	}
+
+	for i, b := range bounds {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+
+	h.sum += v
+	h.count++
+}
+
+// -------------------------
+
+// _Series are the metrics of the requests with the same labels.
+type _Series struct {
+	inFlight int64
+	codes    map[int]uint64
+	duration _Histogram
+	size     _Histogram
+}
+
+// --------------------------------------------------
+
+// Metrics records the metrics of the requests and exposes them.
+type Metrics struct {
+	namespace       string
+	durationBuckets []float64
+	sizeBuckets     []float64
+
+	mu     sync.Mutex
+	series map[_Labels]*_Series
+}
+
+// New returns the Metrics with the default configuration.
+func New() *Metrics {
+	return NewUsingConfig(Config{})
+}
+
+// NewUsingConfig returns the Metrics with the configuration. The buckets are
+// sorted and the duplicate buckets are removed.
+func NewUsingConfig(config Config) *Metrics {
+	var m = &Metrics{
+		namespace:       config.Namespace,
+		durationBuckets: normalizedBuckets(config.DurationBuckets),
+		sizeBuckets:     normalizedBuckets(config.SizeBuckets),
+		series:          map[_Labels]*_Series{},
+	}
+
+	if m.namespace == "" {
+		m.namespace = "nanomux"
+	}
+
+	if m.durationBuckets == nil {
+		m.durationBuckets = DefaultDurationBuckets
+	}
+
+	if m.sizeBuckets == nil {
+		m.sizeBuckets = DefaultSizeBuckets
+	}
+
+	return m
+}
+
+// normalizedBuckets returns the sorted copy of the buckets without
+// duplicates. If there are no buckets, nil is returned.
+func normalizedBuckets(bs []float64) []float64 {
+	if len(bs) == 0 {
+		return nil
+	}
+
+	var nbs = append([]float64(nil), bs...)
+	sort.Float64s(nbs)
+
+	var j = 0
+	for i := 1; i < len(nbs); i++ {
+		if nbs[i] != nbs[j] {
+			j++
+			nbs[j] = nbs[i]
+		}
+	}
+
+	return nbs[:j+1]
+}
+
+// seriesOf returns the series with the labels. It must be called with the
+// mutex locked.
+func (m *Metrics) seriesOf(ls _Labels) *_Series {
+	var s = m.series[ls]
+	if s == nil {
+		s = &_Series{codes: map[int]uint64{}}
+		m.series[ls] = s
+	}
+
+	return s
+}
+
+// Middleware records the metrics of the requests handled by the next
+// handler. It must wrap the request handlers of the hosts and resources,
+// for example, with the Router's WrapAllRequestHandlers method.
+func (m *Metrics) Middleware(next nanomux.Handler) nanomux.Handler {
+	return func(
+		w http.ResponseWriter,
+		r *http.Request,
+		args *nanomux.Args,
+	) bool {
+		var ls = labelsOf(r, args)
+
+		m.mu.Lock()
+		m.seriesOf(ls).inFlight++
+		m.mu.Unlock()
+
+		var rw = &_ResponseWriter{ResponseWriter: w}
+		var start = time.Now()
+		var handled bool
+
+		// The gauge of the requests in flight must be decreased even if the
+		// handler panics.
+		defer func() {
+			var d = time.Since(start)
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			var s = m.seriesOf(ls)
+			s.inFlight--
+			if handled || rw.code != 0 {
+				var code = rw.code
+				if code == 0 {
+					code = http.StatusOK
+				}
+
+				s.codes[code]++
+				s.duration.observe(m.durationBuckets, d.Seconds())
+				s.size.observe(m.sizeBuckets, float64(rw.size))
+			}
+		}()
+
+		handled = next(rw, r, args)
+		return handled
+	}
+}
+
+// Handler writes the metrics in the Prometheus text exposition format. It
+// can be set as the HTTP method handler of a host or resource.
+func (m *Metrics) Handler(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *nanomux.Args,
+) bool {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(m.exposition()))
+	return true
+}
+
+// exposition returns the metrics in the Prometheus text exposition format.
+// The series are sorted by their labels.
+func (m *Metrics) exposition() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var lss = make([]_Labels, 0, len(m.series))
+	for ls := range m.series {
+		lss = append(lss, ls)
+	}
+
+	sort.Slice(lss, func(i, j int) bool { return lss[i].less(lss[j]) })
+
+	var strb strings.Builder
+	var name = m.namespace + "_requests_total"
+	writeHeader(&strb, name, "counter", "The number of the handled requests.")
+	for _, ls := range lss {
+		var codes = m.series[ls].codes
+		var cs = make([]int, 0, len(codes))
+		for c := range codes {
+			cs = append(cs, c)
+		}
+
+		sort.Ints(cs)
+		for _, c := range cs {
+			writeSample(
+				&strb,
+				name,
+				ls,
+				"code",
+				strconv.Itoa(c),
+				float64(codes[c]),
+			)
+		}
+	}
+
+	name = m.namespace + "_request_duration_seconds"
+	writeHeader(&strb, name, "histogram", "The request durations in seconds.")
+	for _, ls := range lss {
+		var s = m.series[ls]
+		writeHistogram(&strb, name, ls, m.durationBuckets, s.duration)
+	}
+
+	name = m.namespace + "_requests_in_flight"
+	writeHeader(&strb, name, "gauge", "The number of the requests in flight.")
+	for _, ls := range lss {
+		writeSample(&strb, name, ls, "", "", float64(m.series[ls].inFlight))
+	}
+
+	name = m.namespace + "_response_size_bytes"
+	writeHeader(&strb, name, "histogram", "The response sizes in bytes.")
+	for _, ls := range lss {
+		var s = m.series[ls]
+		writeHistogram(&strb, name, ls, m.sizeBuckets, s.size)
+	}
+
+	return strb.String()
+}
+
+// --------------------------------------------------
+
+func writeHeader(strb *strings.Builder, name, typ, help string) {
+	strb.WriteString("# HELP ")
+	strb.WriteString(name)
+	strb.WriteByte(' ')
+	strb.WriteString(help)
+	strb.WriteString("\n# TYPE ")
+	strb.WriteString(name)
+	strb.WriteByte(' ')
+	strb.WriteString(typ)
+	strb.WriteByte('\n')
+}
+
+// writeSample writes the sample with the labels. If the extra label's name is
+// not empty, it's added after the labels.
+func writeSample(
+	strb *strings.Builder,
+	name string,
+	ls _Labels,
+	extraName, extraValue string,
+	v float64,
+) {
+	strb.WriteString(name)
+	strb.WriteString(`{host="`)
+	strb.WriteString(escapedLabelValue(ls.host))
+	strb.WriteString(`",resource="`)
+	strb.WriteString(escapedLabelValue(ls.resource))
+	strb.WriteString(`",method="`)
+	strb.WriteString(escapedLabelValue(ls.method))
+	strb.WriteByte('"')
+	if extraName != "" {
+		strb.WriteByte(',')
+		strb.WriteString(extraName)
+		strb.WriteString(`="`)
+		strb.WriteString(escapedLabelValue(extraValue))
+		strb.WriteByte('"')
+	}
+
+	strb.WriteString("} ")
+	strb.WriteString(formattedFloat(v))
+	strb.WriteByte('\n')
+}
+
+func writeHistogram(
+	strb *strings.Builder,
+	name string,
+	ls _Labels,
+	bounds []float64,
+	h _Histogram,
+) {
+	for i, b := range bounds {
+		var c uint64
+		if h.counts != nil {
+			c = h.counts[i]
+		}
+
+		var le = formattedFloat(b)
+		writeSample(strb, name+"_bucket", ls, "le", le, float64(c))
+	}
+
+	writeSample(strb, name+"_bucket", ls, "le", "+Inf", float64(h.count))
+	writeSample(strb, name+"_sum", ls, "", "", h.sum)
+	writeSample(strb, name+"_count", ls, "", "", float64(h.count))
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapedLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+func formattedFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// --------------------------------------------------
+
+// _ResponseWriter records the status code and the size of the response.
+type _ResponseWriter struct {
+	http.ResponseWriter
+	code int
+	size int
+}
+
+func (rw *_ResponseWriter) WriteHeader(code int) {
+	if rw.code == 0 {
+		rw.code = code
+	}
+
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *_ResponseWriter) Write(b []byte) (int, error) {
+	if rw.code == 0 {
+		rw.code = http.StatusOK
+	}
+
+	var n, err = rw.ResponseWriter.Write(b)
+	rw.size += n
+	return n, err
+}
+
+// Flush implements the http.Flusher interface if the underlying response
+// writer implements it.
+func (rw *_ResponseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		if rw.code == 0 {
+			rw.code = http.StatusOK
+		}
+
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (rw *_ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
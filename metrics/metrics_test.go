@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shohruhadham/nanomux"
+)
+
+// --------------------------------------------------
+
+func serve(ro *nanomux.Router, method, url string) *httptest.ResponseRecorder {
+	var w = httptest.NewRecorder()
+	ro.ServeHTTP(w, httptest.NewRequest(method, url, nil))
+	return w
+}
+
+func TestMetrics(t *testing.T) {
+	var m = NewUsingConfig(Config{
+		DurationBuckets: []float64{1, 0.5, 1},
+		SizeBuckets:     []float64{10},
+	})
+
+	var writer = func(body string) nanomux.Handler {
+		return func(
+			w http.ResponseWriter,
+			r *http.Request,
+			args *nanomux.Args,
+		) bool {
+			w.Write([]byte(body))
+			return true
+		}
+	}
+
+	var ro = nanomux.NewRouter()
+	ro.ResourceUsingConfig(
+		"http://example.com/users/{id:int}",
+		nanomux.Config{HandlesHeadWithGet: true},
+	)
+
+	ro.SetURLHandlerFor(
+		"GET PUT",
+		"http://example.com/users/{id:int}",
+		writer("user"),
+	)
+
+	ro.SetURLHandlerFor("GET", "http://example.com", writer("home"))
+	ro.SetURLHandlerFor("GET", "/files/", writer("a long list of files"))
+	ro.SetURLHandlerFor("GET", "/", writer("root"))
+	ro.SetURLHandlerFor("GET", "http://example.com/metrics", m.Handler)
+	ro.SetURLHandlerFor(
+		"GET",
+		"/panic",
+		func(http.ResponseWriter, *http.Request, *nanomux.Args) bool {
+			panic("handler")
+		},
+	)
+
+	ro.WrapAllRequestHandlers(m.Middleware)
+
+	func() {
+		defer func() { recover() }()
+		serve(ro, "GET", "http://localhost/panic")
+	}()
+
+	serve(ro, "GET", "http://example.com/users/1")
+	serve(ro, "HEAD", "http://example.com/users/1")
+	serve(ro, "GET", "http://example.com/users/2")
+	serve(ro, "DELETE", "http://example.com/users/2")
+	serve(ro, "PURGE", "http://example.com/users/2")
+	serve(ro, "GET", "http://example.com")
+	serve(ro, "GET", "http://localhost/files/")
+	serve(ro, "GET", "http://localhost/")
+
+	// Not recorded.
+	serve(ro, "GET", "http://example.com/users/abc")
+	serve(ro, "GET", "http://localhost/files")
+
+	var w = serve(ro, "GET", "http://example.com/metrics")
+	var ct = w.Header().Get("Content-Type")
+	if !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	var body = w.Body.String()
+	var (
+		userLs     = `host="example.com",resource="/users/{id:int}",method="GET"`
+		userHeadLs = `host="example.com",resource="/users/{id:int}",method="HEAD"`
+		user405Ls  = `host="example.com",resource="/users/{id:int}",method="other"`
+		hostLs     = `host="example.com",resource="",method="GET"`
+		filesLs    = `host="",resource="/files/",method="GET"`
+		rootLs     = `host="",resource="/",method="GET"`
+		panicLs    = `host="",resource="/panic",method="GET"`
+		metricsLs  = `host="example.com",resource="/metrics",method="GET"`
+	)
+
+	var wantLines = []string{
+		"# TYPE nanomux_requests_total counter",
+		"# TYPE nanomux_request_duration_seconds histogram",
+		"# TYPE nanomux_requests_in_flight gauge",
+		"# TYPE nanomux_response_size_bytes histogram",
+		`nanomux_requests_total{` + userLs + `,code="200"} 2`,
+		`nanomux_requests_total{` + userHeadLs + `,code="200"} 1`,
+		`nanomux_requests_total{` + user405Ls + `,code="405"} 2`,
+		`nanomux_requests_total{` + hostLs + `,code="200"} 1`,
+		`nanomux_requests_total{` + filesLs + `,code="200"} 1`,
+		`nanomux_requests_total{` + rootLs + `,code="200"} 1`,
+		`nanomux_request_duration_seconds_bucket{` + userLs + `,le="0.5"} 2`,
+		`nanomux_request_duration_seconds_bucket{` + userLs + `,le="1"} 2`,
+		`nanomux_request_duration_seconds_bucket{` + userLs + `,le="+Inf"} 2`,
+		`nanomux_request_duration_seconds_count{` + userLs + `} 2`,
+		`nanomux_requests_in_flight{` + userLs + `} 0`,
+		`nanomux_requests_in_flight{` + metricsLs + `} 1`,
+		`nanomux_requests_in_flight{` + panicLs + `} 0`,
+		`nanomux_response_size_bytes_bucket{` + userLs + `,le="10"} 2`,
+		`nanomux_response_size_bytes_sum{` + userLs + `} 8`,
+		`nanomux_response_size_bytes_bucket{` + filesLs + `,le="10"} 0`,
+		`nanomux_response_size_bytes_bucket{` + filesLs + `,le="+Inf"} 1`,
+	}
+
+	var lines = map[string]bool{}
+	for _, l := range strings.Split(body, "\n") {
+		lines[l] = true
+	}
+
+	for _, l := range wantLines {
+		if !lines[l] {
+			t.Errorf("missing line %s", l)
+		}
+	}
+
+	if strings.Contains(body, `resource="/users"`) {
+		t.Errorf("not-found request was recorded")
+	}
+
+	if strings.Contains(body, `method="DELETE"`) ||
+		strings.Contains(body, `method="PURGE"`) {
+		t.Errorf("method without a handler was recorded as is")
+	}
+
+	if t.Failed() {
+		t.Logf("exposition:\n%s", body)
+	}
+}
+
+func TestEscapedLabelValue(t *testing.T) {
+	var got = escapedLabelValue("a\\b\"c\nd")
+	if want := `a\\b\"c\nd`; got != want {
+		t.Fatalf("escapedLabelValue = %s, want %s", got, want)
+	}
+}
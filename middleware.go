@@ -44,6 +44,68 @@ func Mw(mw func(http.Handler) http.Handler) Middleware {
 
 // --------------------------------------------------
 
+// checkMiddlewares returns an error if there are no middlewares or if any of
+// them is nil.
+func checkMiddlewares(mws []Middleware) error {
+	if len(mws) == 0 {
+		return newErr("%w", errNoMiddleware)
+	}
+
+	for i, mw := range mws {
+		if mw == nil {
+			return newErr("%w at index %d", errNoMiddleware, i)
+		}
+	}
+
+	return nil
+}
+
+// wrapEveryRequestPasser wraps the request passers of the passed host and
+// resources and all their subtree resources. The middlewares are checked
+// before any of the request passers is wrapped.
+func wrapEveryRequestPasser(_rs []_Responder, mws ...Middleware) error {
+	var err = checkMiddlewares(mws)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = traverseAndCall(
+		_rs,
+		func(_r _Responder) error {
+			return _r.TryToWrapRequestPasser(mws...)
+		},
+	)
+
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
+}
+
+// wrapEveryRequestHandler wraps the request handlers of the passed host and
+// resources and all their subtree resources. The middlewares are checked
+// before any of the request handlers is wrapped.
+func wrapEveryRequestHandler(_rs []_Responder, mws ...Middleware) error {
+	var err = checkMiddlewares(mws)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = traverseAndCall(
+		_rs,
+		func(_r _Responder) error {
+			return _r.TryToWrapRequestHandler(mws...)
+		},
+	)
+
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
+}
+
 // wrapEveryHandlerOf wraps the handlers of the HTTP methods of the passed
 // host and resources and all their subtree resources. Handlers are wrapped
 // with middlewares in their passed order.
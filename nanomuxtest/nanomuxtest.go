@@ -0,0 +1,393 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+// Package nanomuxtest provides helpers to test the routing of the
+// nanomux.Router.
+//
+// The Harness serves the requests built with its Request method and returns
+// the Result, which can be checked for the responder that handled the
+// request, the host and path values extracted from the URL, the redirect, or
+// the status code. The responder and the values are taken from the router's
+// Resolve method, so the router is not modified.
+//
+// Example:
+// 	func TestRoutes(t *testing.T) {
+// 		var h = nanomuxtest.New(t, NewAppRouter())
+//
+// 		h.Request("GET", "http://example.com/users/1").Serve().
+// 			ExpectResponder("http://example.com/users/{id:int}").
+// 			ExpectHostPathValues(map[string]string{"id": "1"})
+//
+// 		h.Request("GET", "http://example.com/people").Serve().
+// 			ExpectRedirect("http://example.com/users", 308)
+//
+// 		h.Request("DELETE", "http://example.com/users").Serve().
+// 			ExpectMethodNotAllowed()
+// 	}
+package nanomuxtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shohruhadham/nanomux"
+)
+
+// --------------------------------------------------
+
+// _Record is the record of the responder that handles the request.
+type _Record struct {
+	host     *nanomux.Host
+	resource *nanomux.Resource
+	values   nanomux.HostPathValues
+	handled  bool
+}
+
+// resolve records the responder that the router selects to handle the
+// request. The request is resolved by its host and TLS state, like when the
+// router serves it. If the request's URL can't be resolved, the record is
+// empty.
+func resolve(ro *nanomux.Router, r *http.Request) *_Record {
+	var scheme = "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	var host = r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	var rec = &_Record{}
+	var res, err = ro.Resolve(r.Method, scheme+"://"+host+r.URL.RequestURI())
+
+	if err != nil || res.Handler == nil {
+		return rec
+	}
+
+	rec.host = res.Host
+	rec.resource = res.Resource
+	rec.values = res.HostPathValues
+	rec.handled = true
+	return rec
+}
+
+// --------------------------------------------------
+
+// Harness serves requests with the router and records the responders that
+// handle them.
+type Harness struct {
+	t  testing.TB
+	ro *nanomux.Router
+}
+
+// New returns the Harness of the router. The router is not modified, so the
+// routes can be changed after New is called.
+func New(t testing.TB, ro *nanomux.Router) *Harness {
+	if ro == nil {
+		t.Fatalf("nanomuxtest.New: nil router")
+	}
+
+	return &Harness{t: t, ro: ro}
+}
+
+// Request returns the builder of the request with the method and URL.
+func (h *Harness) Request(method, url string) *Request {
+	return &Request{h: h, r: httptest.NewRequest(method, url, nil)}
+}
+
+// --------------------------------------------------
+
+// Request is the builder of the request to be served by the Harness.
+type Request struct {
+	h *Harness
+	r *http.Request
+}
+
+// Header adds the header to the request.
+func (rq *Request) Header(key, value string) *Request {
+	rq.r.Header.Add(key, value)
+	return rq
+}
+
+// Body sets the body of the request.
+func (rq *Request) Body(body string) *Request {
+	rq.r.Body = io.NopCloser(strings.NewReader(body))
+	rq.r.ContentLength = int64(len(body))
+	return rq
+}
+
+// Serve serves the request with the router and returns the result. The
+// responder that handles the request is resolved before the request is
+// served.
+func (rq *Request) Serve() *Result {
+	var r = rq.r
+	var rec = resolve(rq.h.ro, r)
+
+	var w = httptest.NewRecorder()
+	rq.h.ro.ServeHTTP(w, r)
+
+	return &Result{
+		t:        rq.h.t,
+		request:  r.Method + " " + r.URL.String(),
+		Recorder: w,
+		rec:      rec,
+	}
+}
+
+// --------------------------------------------------
+
+// Result is the result of the served request. Its Expect methods report
+// failures with the Errorf method of the Harness's testing.TB and return the
+// result, so they can be chained.
+type Result struct {
+	t       testing.TB
+	request string
+
+	// Recorder is the recorded response.
+	Recorder *httptest.ResponseRecorder
+
+	rec *_Record
+}
+
+// Handled returns true if one of the router's hosts or resources handled
+// the request.
+func (res *Result) Handled() bool {
+	return res.rec.handled
+}
+
+// Host returns the host of the responder that handled the request.
+func (res *Result) Host() *nanomux.Host {
+	return res.rec.host
+}
+
+// Resource returns the resource that handled the request. It's nil when the
+// request was handled by a host.
+func (res *Result) Resource() *nanomux.Resource {
+	return res.rec.resource
+}
+
+// HostPathValues returns the host and path values extracted from the
+// request's URL.
+func (res *Result) HostPathValues() nanomux.HostPathValues {
+	return res.rec.values
+}
+
+// URLTemplate returns the URL template of the responder that handled the
+// request in the form of the nanomux.Route's URLTemplate. If the request was
+// not handled by any responder, an empty string is returned.
+func (res *Result) URLTemplate() string {
+	var routes []nanomux.Route
+	switch {
+	case res.rec.resource != nil:
+		routes = res.rec.resource.Routes()
+	case res.rec.host != nil:
+		routes = res.rec.host.Routes()
+	}
+
+	if len(routes) == 0 {
+		return ""
+	}
+
+	return routes[0].URLTemplate
+}
+
+// ExpectResponder expects the request to be handled by the responder with
+// the URL template. The template is compared in its canonical form, so the
+// names of the host and path segment templates can be omitted.
+func (res *Result) ExpectResponder(urlTmplStr string) *Result {
+	res.t.Helper()
+
+	var want, err = canonicalURLTmplStr(urlTmplStr)
+	if err != nil {
+		res.t.Errorf(
+			"%s: invalid URL template %q: %v",
+			res.request,
+			urlTmplStr,
+			err,
+		)
+
+		return res
+	}
+
+	if !res.rec.handled {
+		res.t.Errorf("%s: not handled, want responder %q", res.request, want)
+		return res
+	}
+
+	if got := res.URLTemplate(); got != want {
+		res.t.Errorf("%s: responder = %q, want %q", res.request, got, want)
+	}
+
+	return res
+}
+
+// ExpectResponderNamed expects the request to be handled by the responder
+// with the name.
+func (res *Result) ExpectResponderNamed(name string) *Result {
+	res.t.Helper()
+
+	if !res.rec.handled {
+		res.t.Errorf(
+			"%s: not handled, want responder named %q",
+			res.request,
+			name,
+		)
+
+		return res
+	}
+
+	var got string
+	if res.rec.resource != nil {
+		got = res.rec.resource.Name()
+	} else {
+		got = res.rec.host.Name()
+	}
+
+	if got != name {
+		res.t.Errorf("%s: responder name = %q, want %q", res.request, got, name)
+	}
+
+	return res
+}
+
+// ExpectHostPathValues expects the host and path values extracted from the
+// request's URL to be the same as the values.
+func (res *Result) ExpectHostPathValues(values map[string]string) *Result {
+	res.t.Helper()
+
+	if !res.rec.handled {
+		res.t.Errorf("%s: not handled, want host and path values", res.request)
+		return res
+	}
+
+	var mismatch = len(res.rec.values) != len(values)
+	for k, v := range values {
+		if res.rec.values.Get(k) != v {
+			mismatch = true
+			break
+		}
+	}
+
+	if mismatch {
+		res.t.Errorf(
+			"%s: host and path values = %v, want %v",
+			res.request,
+			res.rec.values,
+			values,
+		)
+	}
+
+	return res
+}
+
+// ExpectStatus expects the response to have the status code.
+func (res *Result) ExpectStatus(code int) *Result {
+	res.t.Helper()
+
+	if res.Recorder.Code != code {
+		res.t.Errorf(
+			"%s: status code = %d, want %d",
+			res.request,
+			res.Recorder.Code,
+			code,
+		)
+	}
+
+	return res
+}
+
+// ExpectBody expects the response to have the body.
+func (res *Result) ExpectBody(body string) *Result {
+	res.t.Helper()
+
+	if got := res.Recorder.Body.String(); got != body {
+		res.t.Errorf("%s: body = %q, want %q", res.request, got, body)
+	}
+
+	return res
+}
+
+// ExpectRedirect expects the request to be redirected to the location with
+// the status code.
+func (res *Result) ExpectRedirect(location string, code int) *Result {
+	res.t.Helper()
+
+	res.ExpectStatus(code)
+	if got := res.Recorder.Header().Get("Location"); got != location {
+		res.t.Errorf("%s: location = %q, want %q", res.request, got, location)
+	}
+
+	return res
+}
+
+// ExpectNotFound expects the response to have the 404 status code.
+func (res *Result) ExpectNotFound() *Result {
+	res.t.Helper()
+	return res.ExpectStatus(http.StatusNotFound)
+}
+
+// ExpectMethodNotAllowed expects the response to have the 405 status code.
+// If the allowed methods are passed, the response's Allow header must list
+// the same methods.
+func (res *Result) ExpectMethodNotAllowed(allowed ...string) *Result {
+	res.t.Helper()
+
+	res.ExpectStatus(http.StatusMethodNotAllowed)
+	if len(allowed) == 0 {
+		return res
+	}
+
+	var got = res.Recorder.Header().Values("Allow")
+	var gotMethods = map[string]bool{}
+	for _, v := range got {
+		for _, m := range strings.Split(v, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				gotMethods[m] = true
+			}
+		}
+	}
+
+	var mismatch = len(gotMethods) != len(allowed)
+	for _, m := range allowed {
+		if !gotMethods[m] {
+			mismatch = true
+			break
+		}
+	}
+
+	if mismatch {
+		res.t.Errorf(
+			"%s: allowed methods = %v, want %v",
+			res.request,
+			got,
+			allowed,
+		)
+	}
+
+	return res
+}
+
+// --------------------------------------------------
+
+// canonicalURLTmplStr returns the URL template in the form of the
+// nanomux.Route's URLTemplate.
+func canonicalURLTmplStr(urlTmplStr string) (string, error) {
+	var ro = nanomux.NewRouter()
+	var err = ro.TryToSetURLHandlerFor(
+		"GET",
+		urlTmplStr,
+		func(http.ResponseWriter, *http.Request, *nanomux.Args) bool {
+			return true
+		},
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	return ro.Routes()[0].URLTemplate, nil
+}
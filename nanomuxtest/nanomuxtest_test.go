@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomuxtest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shohruhadham/nanomux"
+)
+
+// --------------------------------------------------
+
+// _FakeTB records the errors instead of failing the test.
+type _FakeTB struct {
+	testing.TB
+	errs []string
+}
+
+func (tb *_FakeTB) Helper() {}
+
+func (tb *_FakeTB) Errorf(format string, args ...interface{}) {
+	tb.errs = append(tb.errs, fmt.Sprintf(format, args...))
+}
+
+func newTestRouter() *nanomux.Router {
+	var writer = func(body string) nanomux.Handler {
+		return func(
+			w http.ResponseWriter,
+			r *http.Request,
+			args *nanomux.Args,
+		) bool {
+			w.Write([]byte(body))
+			return true
+		}
+	}
+
+	var ro = nanomux.NewRouter()
+	ro.SetURLHandlerFor("GET", "http://example.com", writer("home"))
+	ro.SetURLHandlerFor(
+		"GET PUT",
+		"http://example.com/users/$user:{id:int}",
+		writer("user"),
+	)
+
+	ro.SetURLHandlerFor(
+		"POST",
+		"http://{sub:[a-z]+}.example.com/files/{path...}",
+		writer("file"),
+	)
+
+	ro.RedirectAnyRequestAt(
+		"http://example.com/people",
+		"http://example.com/users",
+		http.StatusPermanentRedirect,
+	)
+
+	return ro
+}
+
+func TestHarness(t *testing.T) {
+	var h = New(t, newTestRouter())
+
+	var res = h.Request("GET", "http://example.com/users/12").Serve().
+		ExpectResponder("http://example.com/users/{id:int}").
+		ExpectResponderNamed("user").
+		ExpectHostPathValues(map[string]string{"id": "12"}).
+		ExpectStatus(http.StatusOK).
+		ExpectBody("user")
+
+	if !res.Handled() || res.Resource() == nil || res.Host() == nil {
+		t.Fatalf("Result: the responder was not recorded")
+	}
+
+	h.Request("GET", "http://example.com").Serve().
+		ExpectResponder("http://example.com").
+		ExpectBody("home")
+
+	h.Request("POST", "http://docs.example.com/files/a/b.txt").
+		Header("Content-Type", "text/plain").
+		Body("content").
+		Serve().
+		ExpectResponder("http://{sub:[a-z]+}.example.com/files/{path...}").
+		ExpectHostPathValues(map[string]string{
+			"sub":  "docs",
+			"path": "a/b.txt",
+		})
+
+	h.Request("GET", "http://example.com/people/1").Serve().
+		ExpectRedirect(
+			"http://example.com/users/1",
+			http.StatusPermanentRedirect,
+		)
+
+	h.Request("GET", "http://example.com/users/abc").Serve().
+		ExpectNotFound()
+
+	h.Request("DELETE", "http://example.com/users/12").Serve().
+		ExpectResponderNamed("user").
+		ExpectMethodNotAllowed("GET", "PUT", "OPTIONS")
+
+	// The request's host is used when the URL doesn't have one.
+	h.Request("GET", "/users/12").Serve().
+		ExpectResponderNamed("user").
+		ExpectBody("user")
+}
+
+func TestNew_routesAddedLater(t *testing.T) {
+	var ro = newTestRouter()
+	var h = New(t, ro)
+
+	var err = ro.Update(func(tx *nanomux.RouterTx) error {
+		tx.SetURLHandlerFor(
+			"GET",
+			"http://example.com/posts/{id}",
+			func(w http.ResponseWriter, r *http.Request, _ *nanomux.Args) bool {
+				w.Write([]byte("post"))
+				return true
+			},
+		)
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	var res = h.Request("GET", "http://example.com/posts/1").Serve().
+		ExpectResponder("http://example.com/posts/{id}").
+		ExpectHostPathValues(map[string]string{"id": "1"}).
+		ExpectBody("post")
+
+	var r = ro.RegisteredResource("http://example.com/posts/{id}")
+	if res.Resource() != r {
+		t.Fatalf("Result.Resource(): not the registered resource")
+	}
+
+	h.Request("GET", "http://example.com/users/12").Serve().
+		ExpectResponderNamed("user")
+}
+
+func TestResult_failures(t *testing.T) {
+	var tb = &_FakeTB{}
+	var h = New(tb, newTestRouter())
+
+	var res = h.Request("GET", "http://example.com/users/12").Serve()
+	res.ExpectResponder("http://example.com/users/{id}")
+	res.ExpectResponderNamed("users")
+	res.ExpectHostPathValues(map[string]string{"id": "13"})
+	res.ExpectHostPathValues(map[string]string{"id": "12", "x": ""})
+	res.ExpectStatus(http.StatusCreated)
+	res.ExpectBody("")
+	res.ExpectRedirect("http://example.com", http.StatusMovedPermanently)
+	res.ExpectResponder("http://example.com/{a}{b}")
+
+	res = h.Request("GET", "http://example.com/unknown").Serve()
+	res.ExpectResponder("http://example.com/unknown")
+	res.ExpectResponderNamed("unknown")
+	res.ExpectHostPathValues(nil)
+
+	res = h.Request("DELETE", "http://example.com/users/12").Serve()
+	res.ExpectMethodNotAllowed("GET")
+
+	var wantErrs = 13
+	if len(tb.errs) != wantErrs {
+		t.Fatalf("len(errs) = %d, want %d: %q", len(tb.errs), wantErrs, tb.errs)
+	}
+}
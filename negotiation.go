@@ -0,0 +1,339 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// --------------------------------------------------
+
+// HandlerOption configures the handler of an HTTP method when it's set with
+// the SetHandlerFor method.
+type HandlerOption func(mh *_MediaHandler)
+
+// Produces makes the handler be selected only for the requests that accept
+// one of the media types in their "Accept" header. Several handlers can be
+// set for the same HTTP method with different media types. The handler is
+// selected by the q-values of the "Accept" header. If none of the handlers
+// produces an acceptable media type, the request is responded to with the
+// "406 Not Acceptable" status code.
+//
+// Example:
+// 	r.SetHandlerFor("GET", GetJSON, nanomux.Produces("application/json"))
+// 	r.SetHandlerFor("GET", GetHTML, nanomux.Produces("text/html"))
+func Produces(mediaTypes ...string) HandlerOption {
+	var mrs = parseMediaTypes(mediaTypes)
+	return func(mh *_MediaHandler) {
+		mh.produces = append(mh.produces, mrs...)
+	}
+}
+
+// Consumes makes the handler be selected only for the requests whose body
+// has one of the media types in their "Content-Type" header. The media types
+// may contain wildcards, e.g., "text/*". If none of the handlers consumes the
+// request's media type, the request is responded to with the "415
+// Unsupported Media Type" status code.
+//
+// Example:
+// 	r.SetHandlerFor("POST", PostJSON, nanomux.Consumes("application/json"))
+func Consumes(mediaTypes ...string) HandlerOption {
+	var mrs = parseMediaTypes(mediaTypes)
+	return func(mh *_MediaHandler) {
+		mh.consumes = append(mh.consumes, mrs...)
+	}
+}
+
+// --------------------------------------------------
+
+// _MediaRange is a media type or a range of media types.
+type _MediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// String returns the media range as "type/subtype".
+func (mr _MediaRange) String() string {
+	return mr.typ + "/" + mr.subtype
+}
+
+// isConcrete returns true if the media range doesn't contain wildcards.
+func (mr _MediaRange) isConcrete() bool {
+	return mr.typ != "*" && mr.subtype != "*"
+}
+
+// matches returns true if the media range contains the media type, or vice
+// versa. When the media range matches, its specificity is also returned.
+func (mr _MediaRange) matches(mt _MediaRange) (bool, int) {
+	switch {
+	case mr.typ == "*" || mt.typ == "*":
+		return true, 0
+	case mr.typ != mt.typ:
+		return false, 0
+	case mr.subtype == "*" || mt.subtype == "*":
+		return true, 1
+	case mr.subtype != mt.subtype:
+		return false, 0
+	}
+
+	return true, 2
+}
+
+// parseMediaRange parses the media range with its parameters. The q
+// parameter is kept, and the others are ignored. If the media range is
+// invalid, false is returned.
+func parseMediaRange(str string) (_MediaRange, bool) {
+	var mt, params, err = mime.ParseMediaType(strings.TrimSpace(str))
+	if err != nil {
+		return _MediaRange{}, false
+	}
+
+	var typ, subtype, found = strings.Cut(mt, "/")
+	if !found || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+		return _MediaRange{}, false
+	}
+
+	var mr = _MediaRange{typ: typ, subtype: subtype, q: 1}
+	if qs, ok := params["q"]; ok {
+		var q, err = strconv.ParseFloat(qs, 64)
+		if err != nil || q < 0 || q > 1 {
+			return _MediaRange{}, false
+		}
+
+		mr.q = q
+	}
+
+	return mr, true
+}
+
+// parseMediaTypes parses the media types of the handler options. It panics
+// if one of the media types is invalid.
+func parseMediaTypes(mediaTypes []string) []_MediaRange {
+	if len(mediaTypes) == 0 {
+		panicWithErr("%w: no media type", errInvalidArgument)
+	}
+
+	var mrs = make([]_MediaRange, len(mediaTypes))
+	for i, mt := range mediaTypes {
+		var mr, ok = parseMediaRange(mt)
+		if !ok {
+			panicWithErr("%w: media type %q", errInvalidArgument, mt)
+		}
+
+		mrs[i] = mr
+	}
+
+	return mrs
+}
+
+// parseAccept parses the "Accept" header values. Invalid media ranges are
+// skipped.
+func parseAccept(values []string) []_MediaRange {
+	var mrs []_MediaRange
+	for _, v := range values {
+		for _, str := range strings.Split(v, ",") {
+			if mr, ok := parseMediaRange(str); ok {
+				mrs = append(mrs, mr)
+			}
+		}
+	}
+
+	return mrs
+}
+
+// -------------------------
+
+// _MediaHandler is a handler of an HTTP method that produces and/or consumes
+// the media types.
+type _MediaHandler struct {
+	handler  Handler
+	produces []_MediaRange
+	consumes []_MediaRange
+
+	// operation is set by the Describe option.
+	operation *OpenAPIOperation
+}
+
+// quality returns the quality of the media type the handler produces that
+// is the most acceptable and the media type itself. The acceptable ranges
+// must not be empty.
+func (mh *_MediaHandler) quality(accepts []_MediaRange) (
+	float64,
+	_MediaRange,
+) {
+	var bestQ = -1.0
+	var best _MediaRange
+	for _, p := range mh.produces {
+		// The most specific matching range determines the quality.
+		var q, specificity = -1.0, -1
+		for _, a := range accepts {
+			if ok, s := a.matches(p); ok && s > specificity {
+				q, specificity = a.q, s
+			}
+		}
+
+		if q > bestQ {
+			bestQ, best = q, p
+		}
+	}
+
+	return bestQ, best
+}
+
+// consumesMediaType returns true if the handler consumes the media type.
+func (mh *_MediaHandler) consumesMediaType(mt _MediaRange) bool {
+	if len(mh.consumes) == 0 {
+		return true
+	}
+
+	for _, c := range mh.consumes {
+		if ok, _ := c.matches(mt); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// _MediaHandlers are the handlers of the same HTTP method that are selected
+// by the media types of the request.
+type _MediaHandlers []_MediaHandler
+
+// handle selects the handler by the media types of the request and calls it.
+func (mhs _MediaHandlers) handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+) bool {
+	var h = w.Header()
+	if mhs.produce() {
+		addVary(h, "Accept")
+	}
+
+	var candidates = mhs
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		// Handlers that consume the media type come before the handlers
+		// that consume any media type.
+		var mt, ok = parseMediaRange(ct)
+		candidates = nil
+		if ok {
+			for _, mh := range mhs {
+				if len(mh.consumes) > 0 && mh.consumesMediaType(mt) {
+					candidates = append(candidates, mh)
+				}
+			}
+		}
+
+		for _, mh := range mhs {
+			if len(mh.consumes) == 0 {
+				candidates = append(candidates, mh)
+			}
+		}
+
+		if len(candidates) == 0 {
+			http.Error(
+				w,
+				http.StatusText(http.StatusUnsupportedMediaType),
+				http.StatusUnsupportedMediaType,
+			)
+
+			return true
+		}
+	}
+
+	var accepts = parseAccept(r.Header.Values("Accept"))
+	if len(accepts) == 0 {
+		var mh = candidates[0]
+		if len(mh.produces) > 0 && mh.produces[0].isConcrete() &&
+			h.Get("Content-Type") == "" {
+			h.Set("Content-Type", mh.produces[0].String())
+		}
+
+		return mh.handler(w, r, args)
+	}
+
+	var selected *_MediaHandler
+	var selectedQ float64
+	var selectedMt _MediaRange
+	for i := range candidates {
+		var mh = &candidates[i]
+		if len(mh.produces) == 0 {
+			continue
+		}
+
+		var q, mt = mh.quality(accepts)
+		if q > selectedQ {
+			selected, selectedQ, selectedMt = mh, q, mt
+		}
+	}
+
+	if selected == nil {
+		// Handlers that don't produce specific media types are the fallback.
+		for i := range candidates {
+			if len(candidates[i].produces) == 0 {
+				return candidates[i].handler(w, r, args)
+			}
+		}
+
+		http.Error(
+			w,
+			http.StatusText(http.StatusNotAcceptable),
+			http.StatusNotAcceptable,
+		)
+
+		return true
+	}
+
+	if selectedMt.isConcrete() && h.Get("Content-Type") == "" {
+		h.Set("Content-Type", selectedMt.String())
+	}
+
+	return selected.handler(w, r, args)
+}
+
+// produce returns true if one of the handlers produces specific media types.
+func (mhs _MediaHandlers) produce() bool {
+	for _, mh := range mhs {
+		if len(mh.produces) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// producedMediaTypes returns the concrete media types produced by the
+// handlers.
+func (mhs _MediaHandlers) producedMediaTypes() []string {
+	var mts []string
+	for _, mh := range mhs {
+		for _, p := range mh.produces {
+			if p.isConcrete() {
+				mts = append(mts, p.String())
+			}
+		}
+	}
+
+	return mts
+}
+
+// consumedMediaTypes returns the media types consumed by the handlers. If
+// one of the handlers consumes any media type, nil is returned.
+func (mhs _MediaHandlers) consumedMediaTypes() []string {
+	var mts []string
+	for _, mh := range mhs {
+		if len(mh.consumes) == 0 {
+			return nil
+		}
+
+		for _, c := range mh.consumes {
+			mts = append(mts, c.String())
+		}
+	}
+
+	return mts
+}
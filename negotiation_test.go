@@ -0,0 +1,234 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// --------------------------------------------------
+
+func TestParseMediaRange(t *testing.T) {
+	var cases = []struct {
+		str    string
+		wantMr _MediaRange
+		wantOk bool
+	}{
+		{"application/json", _MediaRange{"application", "json", 1}, true},
+		{"Text/HTML; q=0.5", _MediaRange{"text", "html", 0.5}, true},
+		{"text/*;level=1;q=0", _MediaRange{"text", "*", 0}, true},
+		{"*/*", _MediaRange{"*", "*", 1}, true},
+		{"*/json", _MediaRange{}, false},
+		{"text/html; q=2", _MediaRange{}, false},
+		{"text", _MediaRange{}, false},
+		{"", _MediaRange{}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.str, func(t *testing.T) {
+			var mr, ok = parseMediaRange(c.str)
+			checkValue(t, ok, c.wantOk)
+			checkValue(t, mr, c.wantMr)
+		})
+	}
+
+	testPanicker(t, true, func() { Produces() })
+	testPanicker(t, true, func() { Consumes("json") })
+}
+
+func TestContentNegotiation(t *testing.T) {
+	var writer = func(body string) Handler {
+		return func(w http.ResponseWriter, r *http.Request, _ *Args) bool {
+			w.Write([]byte(body))
+			return true
+		}
+	}
+
+	var r = NewDormantResource("http://example.com/r")
+	r.SetHandlerFor("GET", writer("json"), Produces("application/json"))
+	r.SetHandlerFor(
+		"GET",
+		writer("html"),
+		Produces("text/html", "application/xhtml+xml"),
+	)
+
+	r.SetHandlerFor("POST", writer("fallback"))
+	r.SetHandlerFor("POST", writer("form"), Consumes("application/x-www-form-urlencoded"))
+	r.SetHandlerFor(
+		"PATCH",
+		writer("json patch"),
+		Consumes("application/json-patch+json"),
+		Produces("application/json"),
+	)
+
+	r.WrapHandlerOf(
+		"GET",
+		func(next Handler) Handler {
+			return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+				w.Header().Set("X-Wrapped", "true")
+				return next(w, r, args)
+			}
+		},
+	)
+
+	var cases = []struct {
+		name, method, accept, contentType string
+		wantStatusCode                    int
+		wantBody, wantContentType         string
+	}{
+		{"no accept", "GET", "", "", http.StatusOK, "json", "application/json"},
+		{"any", "GET", "*/*", "", http.StatusOK, "json", "application/json"},
+		{
+			"html",
+			"GET", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8", "",
+			http.StatusOK, "html", "text/html",
+		},
+		{
+			"q-values",
+			"GET", "text/html;q=0.5, application/json;q=0.7", "",
+			http.StatusOK, "json", "application/json",
+		},
+		{
+			"specific range",
+			"GET", "text/*;q=0.1, text/html;q=0.9, application/*;q=0.2", "",
+			http.StatusOK, "html", "text/html",
+		},
+		{
+			"not acceptable",
+			"GET", "image/png", "",
+			http.StatusNotAcceptable, "Not Acceptable\n", "text/plain; charset=utf-8",
+		},
+		{
+			"excluded",
+			"GET", "application/json;q=0, */*;q=0.1", "",
+			http.StatusOK, "html", "text/html",
+		},
+		{
+			"form",
+			"POST", "", "application/x-www-form-urlencoded",
+			http.StatusOK, "form", "text/plain; charset=utf-8",
+		},
+		{
+			"fallback",
+			"POST", "", "text/plain",
+			http.StatusOK, "fallback", "text/plain; charset=utf-8",
+		},
+		{
+			"unsupported media type",
+			"PATCH", "", "application/json",
+			http.StatusUnsupportedMediaType, "Unsupported Media Type\n",
+			"text/plain; charset=utf-8",
+		},
+		{
+			"json patch",
+			"PATCH", "application/json", "application/json-patch+json",
+			http.StatusOK, "json patch", "application/json",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			var req = httptest.NewRequest(c.method, "http://example.com/r", nil)
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+
+			if c.contentType != "" {
+				req.Header.Set("Content-Type", c.contentType)
+			}
+
+			r.ServeHTTP(w, req)
+			checkValue(t, w.Code, c.wantStatusCode)
+			checkValue(t, w.Body.String(), c.wantBody)
+			checkValue(t, w.Header().Get("Content-Type"), c.wantContentType)
+
+			if c.method == "GET" {
+				checkValue(t, w.Header().Get("Vary"), "Accept")
+				checkValue(t, w.Header().Get("X-Wrapped"), "true")
+			}
+		})
+	}
+
+	var w = httptest.NewRecorder()
+	var req = httptest.NewRequest("OPTIONS", "http://example.com/r", nil)
+	r.ServeHTTP(w, req)
+	checkValue(t, w.Header().Get("Allow"), "GET, OPTIONS, PATCH, POST")
+	checkValue(t, w.Header().Get("Accept-Patch"), "application/json-patch+json")
+	checkValue(t, w.Header().Get("Accept-Post"), "")
+
+	// Vary is not repeated when a middleware already added it.
+	var vary = func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			w.Header().Add("Vary", "Accept-Encoding, accept")
+			return next(w, r, args)
+		}
+	}
+
+	var vr = NewDormantResource("vr")
+	vr.SetHandlerFor("GET", writer("json"), Produces("application/json"))
+	vr.WrapRequestHandler(vary)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "http://example.com/vr", nil)
+	vr.ServeHTTP(w, req)
+	checkValue(t, w.Header().Values("Vary"), []string{"Accept-Encoding, accept"})
+
+	r.SetHandlerFor("GET", writer("plain"))
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "http://example.com/r", nil)
+	req.Header.Set("Accept", "image/png")
+	r.ServeHTTP(w, req)
+	checkValue(t, w.Body.String(), "plain")
+}
+
+func TestContentNegotiation_middlewares(t *testing.T) {
+	var calls int
+	var mw = func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			calls++
+			return next(w, r, args)
+		}
+	}
+
+	var writer = func(body string) Handler {
+		return func(w http.ResponseWriter, r *http.Request, _ *Args) bool {
+			w.Write([]byte(body))
+			return true
+		}
+	}
+
+	var r = NewDormantResource("http://example.com/r")
+	r.SetHandlerFor("GET", writer("text"))
+	r.WrapHandlerOf("GET", mw)
+	r.SetHandlerFor("GET", writer("json"), Produces("application/json"))
+
+	var serve = func(accept string) string {
+		var w = httptest.NewRecorder()
+		var req = httptest.NewRequest("GET", "http://example.com/r", nil)
+		req.Header.Set("Accept", accept)
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	// The middleware wraps the media handlers, and the fallback handler
+	// is not wrapped twice.
+	checkValue(t, serve("application/json"), "json")
+	checkValue(t, calls, 1)
+
+	checkValue(t, serve("text/plain"), "text")
+	checkValue(t, calls, 2)
+
+	// The middleware is kept when the resource is cloned.
+	var c = r.clone()
+	var w = httptest.NewRecorder()
+	var req = httptest.NewRequest("GET", "http://example.com/r", nil)
+	req.Header.Set("Accept", "application/json")
+	c.ServeHTTP(w, req)
+	checkValue(t, w.Body.String(), "json")
+	checkValue(t, calls, 3)
+}
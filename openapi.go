@@ -0,0 +1,541 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// --------------------------------------------------
+
+// OpenAPIVersion is the version of the OpenAPI specification the documents
+// are generated for.
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPIDocument is the OpenAPI document of the router's tree.
+type OpenAPIDocument struct {
+	OpenAPI string                      `json:"openapi"`
+	Info    OpenAPIInfo                 `json:"info"`
+	Servers []*OpenAPIServer            `json:"servers,omitempty"`
+	Paths   map[string]*OpenAPIPathItem `json:"paths"`
+}
+
+// OpenAPIInfo is the metadata of the API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+
+	// ServerVariables are the default values of the server variables keyed
+	// by the value names of the hosts' dynamic segments. The OpenAPI
+	// specification requires each server variable to have a default value.
+	// They are not encoded as a part of the info.
+	ServerVariables map[string]string `json:"-"`
+}
+
+// OpenAPIServer is a server derived from the host's template and scheme.
+type OpenAPIServer struct {
+	URL       string                            `json:"url"`
+	Variables map[string]*OpenAPIServerVariable `json:"variables,omitempty"`
+}
+
+// OpenAPIServerVariable is a dynamic segment of the host's template.
+type OpenAPIServerVariable struct {
+	Default     string `json:"default"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIPathItem describes the operations of a host or resource.
+type OpenAPIPathItem struct {
+	Servers    []*OpenAPIServer
+	Operations map[string]*OpenAPIOperation
+}
+
+// MarshalJSON encodes the path item with its operations keyed by the
+// lowercase HTTP methods.
+func (pi *OpenAPIPathItem) MarshalJSON() ([]byte, error) {
+	var m = make(map[string]interface{}, len(pi.Operations)+1)
+	if len(pi.Servers) > 0 {
+		m["servers"] = pi.Servers
+	}
+
+	for method, op := range pi.Operations {
+		m[strings.ToLower(method)] = op
+	}
+
+	return json.Marshal(m)
+}
+
+// OpenAPIOperation describes the handler of an HTTP method. It can be
+// attached to the handler with the Describe option or with the
+// OperationDescriber interface of the Impl types.
+//
+// The path parameters are added to the operation from the templates. The
+// path parameter of a catch-all segment matches the remaining path segments
+// with their slashes, which the OpenAPI path parameters can't describe, so
+// its schema has the "x-nanomux-catch-all" extension set to true. When
+// the operation doesn't have a request body and the handler consumes media
+// types, the request body is added with those media types. When it doesn't
+// have responses, the "200" response is added with the media types the
+// handler produces.
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter describes a path, query, header, or cookie parameter.
+type OpenAPIParameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      interface{} `json:"schema,omitempty"`
+}
+
+// OpenAPIRequestBody describes the request body of an operation. The keys of
+// the content are media types.
+type OpenAPIRequestBody struct {
+	Description string                       `json:"description,omitempty"`
+	Required    bool                         `json:"required,omitempty"`
+	Content     map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a response of an operation. The keys of the
+// content are media types.
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType contains the schema of the media type. The schema can be
+// any value that is encoded as a JSON schema object, e.g., a map or a
+// json.RawMessage.
+type OpenAPIMediaType struct {
+	Schema interface{} `json:"schema,omitempty"`
+}
+
+// OperationDescriber can be implemented by the Impl types to describe
+// their handlers in the OpenAPI document. The DescribeOperation method is
+// called with each HTTP method the type has a handler for. It may return nil.
+type OperationDescriber interface {
+	DescribeOperation(method string) *OpenAPIOperation
+}
+
+// Describe attaches the OpenAPI metadata to the handler.
+//
+// Example:
+// 	r.SetHandlerFor(
+// 		"GET",
+// 		GetUser,
+// 		nanomux.Describe(nanomux.OpenAPIOperation{
+// 			Summary: "Returns the user.",
+// 			Tags:    []string{"users"},
+// 		}),
+// 	)
+func Describe(op OpenAPIOperation) HandlerOption {
+	return func(mh *_MediaHandler) {
+		mh.operation = &op
+	}
+}
+
+// --------------------------------------------------
+
+// openAPIMethods are the HTTP methods that can have an operation in the
+// OpenAPI path item.
+var openAPIMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodPut:     true,
+	http.MethodPost:    true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+	http.MethodHead:    true,
+	http.MethodPatch:   true,
+	http.MethodTrace:   true,
+}
+
+// OpenAPIDocument returns the OpenAPI document of the hosts and resources
+// that have HTTP method handlers. The paths are built from the templates of
+// the resources, and their dynamic segments become the path parameters with
+// their regular expressions as the patterns. The servers are built from the
+// templates of the hosts and the schemes of their responders. The dynamic
+// segments of the hosts become the server variables.
+//
+// When there are several hosts, or there are resources without a host, the
+// path items of the hosts' resources have their host's server. When paths
+// of different hosts are the same, their operations are merged into the same
+// path item with the servers of all the hosts. If more than one of them
+// handles the same HTTP method, the method panics with the
+// ErrConflictingOperation error.
+//
+// Default OPTIONS handlers and custom HTTP methods are not included.
+//
+// Each dynamic segment of the hosts must have its default value in the info's
+// ServerVariables, otherwise the method panics.
+func (ro *Router) OpenAPIDocument(info OpenAPIInfo) *OpenAPIDocument {
+	var doc, err = ro.openAPIDocument(info)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return doc
+}
+
+// openAPIDocument is like OpenAPIDocument but returns an error instead of
+// panicking.
+func (ro *Router) openAPIDocument(info OpenAPIInfo) (*OpenAPIDocument, error) {
+	var doc = &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   map[string]*OpenAPIPathItem{},
+	}
+
+	type serverKey struct {
+		h      *Host
+		secure bool
+	}
+
+	var routes = ro.Routes()
+	var responders = make([]_Responder, len(routes))
+	var servers = map[serverKey]*OpenAPIServer{}
+	var hasRootRoutes bool
+	for i, rt := range routes {
+		responders[i] = rt.Host
+		if rt.Resource != nil {
+			responders[i] = rt.Resource
+		}
+
+		if rt.Host == nil {
+			hasRootRoutes = true
+			continue
+		}
+
+		var k = serverKey{rt.Host, responders[i].IsSecure()}
+		if servers[k] == nil {
+			var s, err = openAPIServerOf(rt.Host, k.secure, info.ServerVariables)
+			if err != nil {
+				return nil, newErr("%w", err)
+			}
+
+			servers[k] = s
+			doc.Servers = append(doc.Servers, s)
+		}
+	}
+
+	var pathServers = hasRootRoutes || len(servers) > 1
+	for i, rt := range routes {
+		var _r = responders[i]
+		var ops = openAPIOperationsOf(_r)
+		if len(ops) == 0 {
+			continue
+		}
+
+		var path, params = openAPIPathOf(_r)
+		for _, op := range ops {
+			op.Parameters = appendPathParams(op.Parameters, params)
+		}
+
+		var s *OpenAPIServer
+		if rt.Host != nil {
+			s = servers[serverKey{rt.Host, _r.IsSecure()}]
+		}
+
+		var pi = doc.Paths[path]
+		if pi == nil {
+			pi = &OpenAPIPathItem{Operations: ops}
+			if pathServers && s != nil {
+				pi.Servers = []*OpenAPIServer{s}
+			}
+
+			doc.Paths[path] = pi
+			continue
+		}
+
+		// The path of another host or the root resource.
+		for m, op := range ops {
+			if pi.Operations[m] != nil {
+				return nil, newErr(
+					"%w: %s %s",
+					ErrConflictingOperation,
+					m,
+					path,
+				)
+			}
+
+			pi.Operations[m] = op
+		}
+
+		if pi.Servers != nil {
+			if s == nil {
+				pi.Servers = nil
+			} else if !hasServer(pi.Servers, s) {
+				pi.Servers = append(pi.Servers, s)
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// OpenAPI returns the OpenAPI document of the router's tree encoded as JSON.
+// See the OpenAPIDocument method. Unlike the OpenAPIDocument method, when
+// a server variable doesn't have a default value or the operations of the
+// hosts conflict, an error is returned.
+func (ro *Router) OpenAPI(info OpenAPIInfo) ([]byte, error) {
+	var doc, err = ro.openAPIDocument(info)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var b []byte
+	b, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return b, nil
+}
+
+// -------------------------
+
+// openAPIServerOf returns the server of the host with the scheme. The
+// defaults are the default values of the server variables. If one of the
+// variables doesn't have a default value, the error wraps ErrMissingValue.
+func openAPIServerOf(
+	h *Host,
+	secure bool,
+	defaults map[string]string,
+) (*OpenAPIServer, error) {
+	var s = &OpenAPIServer{}
+	var strb strings.Builder
+	if secure {
+		strb.WriteString("https://")
+	} else {
+		strb.WriteString("http://")
+	}
+
+	var tmpl = h.Template()
+	for _, slice := range tmpl.slices {
+		if slice.staticStr != "" {
+			strb.WriteString(slice.staticStr)
+			continue
+		}
+
+		var vn = slice.valuePattern.name
+		strb.WriteString("{" + vn + "}")
+		if s.Variables == nil {
+			s.Variables = map[string]*OpenAPIServerVariable{}
+		}
+
+		if s.Variables[vn] == nil {
+			var dv = defaults[vn]
+			if dv == "" {
+				return nil, newErr(
+					"%w for the server variable %q",
+					ErrMissingValue,
+					vn,
+				)
+			}
+
+			var sv = &OpenAPIServerVariable{Default: dv}
+			if p := segmentPattern(slice); p != "" {
+				sv.Description = "pattern: " + p
+			}
+
+			s.Variables[vn] = sv
+		}
+	}
+
+	s.URL = strb.String()
+	return s, nil
+}
+
+// hasServer returns true if the servers contain the server.
+func hasServer(servers []*OpenAPIServer, s *OpenAPIServer) bool {
+	for _, srv := range servers {
+		if srv == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// openAPIPathOf returns the path of the responder relative to its host and
+// the parameters of the path.
+func openAPIPathOf(_r _Responder) (string, []*OpenAPIParameter) {
+	var tmpls []*Template
+loop:
+	for p := _Parent(_r); p != nil; p = p.parent() {
+		switch p := p.(type) {
+		case *Resource:
+			if p.isRoot() {
+				continue
+			}
+
+			tmpls = append(tmpls, p.Template())
+		default:
+			break loop
+		}
+	}
+
+	var strb strings.Builder
+	var params []*OpenAPIParameter
+	var seen = map[string]bool{}
+	for i := len(tmpls) - 1; i > -1; i-- {
+		strb.WriteByte('/')
+		for _, slice := range tmpls[i].slices {
+			if slice.staticStr != "" {
+				strb.WriteString(slice.staticStr)
+				continue
+			}
+
+			var vn = slice.valuePattern.name
+			strb.WriteString("{" + vn + "}")
+			if seen[vn] {
+				continue
+			}
+
+			seen[vn] = true
+
+			var schema = map[string]interface{}{"type": "string"}
+			if p := segmentPattern(slice); p != "" {
+				schema["pattern"] = p
+			}
+
+			if tmpls[i].IsOptional() && tmpls[i].DefaultValue() != "" {
+				schema["default"] = tmpls[i].DefaultValue()
+			}
+
+			if tmpls[i].IsCatchAll() {
+				schema["x-nanomux-catch-all"] = true
+			}
+
+			params = append(params, &OpenAPIParameter{
+				Name:     vn,
+				In:       "path",
+				Required: true,
+				Schema:   schema,
+			})
+		}
+	}
+
+	if r, ok := _r.(*Resource); ok && r.HasTrailingSlash() && len(tmpls) > 0 {
+		strb.WriteByte('/')
+	}
+
+	if strb.Len() == 0 {
+		return "/", params
+	}
+
+	return strb.String(), params
+}
+
+// segmentPattern returns the anchored regular expression of the dynamic
+// segment. If the segment is a wildcard, an empty string is returned.
+func segmentPattern(slice _TemplateSegment) string {
+	if slice.patternType != nil {
+		return "^(?:" + slice.patternType.pattern + ")$"
+	}
+
+	if slice.valuePattern.re == nil {
+		return ""
+	}
+
+	// Anchors are added to the regular expressions when they're parsed.
+	var p = slice.valuePattern.re.String()
+	p = strings.TrimPrefix(p, "^")
+	p = strings.TrimSuffix(p, "$")
+	return "^(?:" + p + ")$"
+}
+
+// openAPIOperationsOf returns the operations of the responder's HTTP method
+// handlers keyed by their HTTP methods.
+func openAPIOperationsOf(_r _Responder) map[string]*OpenAPIOperation {
+	var rhb = _r.requestHandlerBase()
+	if rhb == nil {
+		return nil
+	}
+
+	var ops map[string]*OpenAPIOperation
+	for _, mhp := range rhb.mhPairs {
+		if mhp.dflt || !openAPIMethods[mhp.method] {
+			continue
+		}
+
+		var op = &OpenAPIOperation{}
+		if mhp.operation != nil {
+			*op = *mhp.operation
+			op.Parameters = append([]*OpenAPIParameter(nil), op.Parameters...)
+		}
+
+		if op.RequestBody == nil {
+			if mts := mhp.mediaHandlers.consumedMediaTypes(); len(mts) > 0 {
+				op.RequestBody = &OpenAPIRequestBody{
+					Content: openAPIContent(mts),
+				}
+			}
+		}
+
+		if len(op.Responses) == 0 {
+			op.Responses = map[string]*OpenAPIResponse{
+				strconv.Itoa(http.StatusOK): {
+					Description: http.StatusText(http.StatusOK),
+					Content:     openAPIContent(mhp.mediaHandlers.producedMediaTypes()),
+				},
+			}
+		}
+
+		if ops == nil {
+			ops = map[string]*OpenAPIOperation{}
+		}
+
+		ops[mhp.method] = op
+	}
+
+	return ops
+}
+
+// openAPIContent returns the content with the media types.
+func openAPIContent(mts []string) map[string]*OpenAPIMediaType {
+	if len(mts) == 0 {
+		return nil
+	}
+
+	var content = make(map[string]*OpenAPIMediaType, len(mts))
+	for _, mt := range mts {
+		content[mt] = &OpenAPIMediaType{}
+	}
+
+	return content
+}
+
+// appendPathParams appends the path parameters that are not in the
+// parameters.
+func appendPathParams(
+	params []*OpenAPIParameter,
+	pathParams []*OpenAPIParameter,
+) []*OpenAPIParameter {
+	var lparams = len(params)
+loop:
+	for _, pp := range pathParams {
+		for _, p := range params[:lparams] {
+			if p.In == pp.In && p.Name == pp.Name {
+				continue loop
+			}
+		}
+
+		params = append(params, pp)
+	}
+
+	return params
+}
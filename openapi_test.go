@@ -0,0 +1,196 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// --------------------------------------------------
+
+type _DescribedImpl struct{}
+
+func (*_DescribedImpl) HandleGet(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+) bool {
+	return true
+}
+
+func (*_DescribedImpl) HandleDelete(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+) bool {
+	return true
+}
+
+func (*_DescribedImpl) DescribeOperation(method string) *OpenAPIOperation {
+	if method == http.MethodDelete {
+		return &OpenAPIOperation{Summary: "Deletes the file.", Deprecated: true}
+	}
+
+	return nil
+}
+
+func TestRouter_OpenAPIDocument(t *testing.T) {
+	var h = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		return true
+	}
+
+	var ro = NewRouter()
+	ro.SetURLHandlerFor(
+		"GET",
+		"https://{sub:alpha}.example.com/users/{id:int}/",
+		h,
+		Describe(OpenAPIOperation{
+			OperationID: "getUser",
+			Summary:     "Returns the user.",
+			Tags:        []string{"users"},
+			Parameters: []*OpenAPIParameter{
+				{Name: "detail", In: "query", Schema: map[string]string{
+					"type": "boolean",
+				}},
+			},
+		}),
+		Produces("application/json"),
+	)
+
+	ro.SetURLHandlerFor(
+		"POST",
+		"https://{sub:alpha}.example.com/users/{id:int}/",
+		h,
+		Consumes("application/json"),
+	)
+
+	ro.SetURLHandlerFor("SHARE", "https://{sub:alpha}.example.com/users", h)
+	ro.SetURLHandlerFor("GET", "http://example.com/{a:[a-z]{2}}-{b}", h)
+	ro.SetURLHandlerFor("GET", "/docs/{path...}", h)
+	ro.SetImplementationAt("/files/{name}", &_DescribedImpl{})
+
+	var info = OpenAPIInfo{Title: "Test", Version: "1.0"}
+	testPanicker(t, true, func() { ro.OpenAPIDocument(info) })
+
+	var _, err = ro.OpenAPI(info)
+	checkErr(t, err, true)
+
+	info.ServerVariables = map[string]string{"sub": "api"}
+	var doc = ro.OpenAPIDocument(info)
+	checkValue(t, doc.OpenAPI, OpenAPIVersion)
+	checkValue(t, len(doc.Servers), 2)
+	checkValue(t, doc.Servers[0].URL, "http://example.com")
+	checkValue(t, doc.Servers[1].URL, "https://{sub}.example.com")
+	checkValue(
+		t,
+		doc.Servers[1].Variables["sub"].Description,
+		"pattern: ^(?:[A-Za-z]+)$",
+	)
+
+	checkValue(t, doc.Servers[1].Variables["sub"].Default, "api")
+
+	var pi = doc.Paths["/users/{id}/"]
+	if pi == nil {
+		t.Fatalf("OpenAPIDocument: missing path /users/{id}/")
+	}
+
+	checkValue(t, len(pi.Operations), 2)
+	checkValue(t, pi.Servers, []*OpenAPIServer{doc.Servers[1]})
+
+	var op = pi.Operations["GET"]
+	checkValue(t, op.OperationID, "getUser")
+	checkValue(t, len(op.Parameters), 2)
+	checkValue(t, op.Parameters[1].Name, "id")
+	checkValue(t, op.Parameters[1].In, "path")
+	checkValue(t, op.Parameters[1].Required, true)
+	checkValue(t, op.Parameters[1].Schema, map[string]interface{}{
+		"type":    "string",
+		"pattern": `^(?:\d+)$`,
+	})
+
+	checkValue(t, len(op.Responses["200"].Content), 1)
+	if op.Responses["200"].Content["application/json"] == nil {
+		t.Fatalf("OpenAPIDocument: missing produced media type")
+	}
+
+	op = pi.Operations["POST"]
+	if op.RequestBody == nil ||
+		op.RequestBody.Content["application/json"] == nil {
+		t.Fatalf("OpenAPIDocument: missing consumed media type")
+	}
+
+	if doc.Paths["/users"] != nil {
+		t.Fatalf("OpenAPIDocument: custom HTTP method was included")
+	}
+
+	pi = doc.Paths["/{a}-{b}"]
+	if pi == nil {
+		t.Fatalf("OpenAPIDocument: missing path /{a}-{b}")
+	}
+
+	op = pi.Operations["GET"]
+	checkValue(t, len(op.Parameters), 2)
+	checkValue(t, op.Parameters[0].Schema, map[string]interface{}{
+		"type":    "string",
+		"pattern": `^(?:[a-z]{2})$`,
+	})
+
+	checkValue(t, op.Parameters[1].Schema, map[string]interface{}{
+		"type": "string",
+	})
+
+	pi = doc.Paths["/files/{name}"]
+	if pi == nil {
+		t.Fatalf("OpenAPIDocument: missing path /files/{name}")
+	}
+
+	checkValue(t, pi.Servers, []*OpenAPIServer(nil))
+	checkValue(t, pi.Operations["GET"].Summary, "")
+	checkValue(t, pi.Operations["DELETE"].Summary, "Deletes the file.")
+	checkValue(t, pi.Operations["DELETE"].Deprecated, true)
+
+	pi = doc.Paths["/docs/{path}"]
+	if pi == nil {
+		t.Fatalf("OpenAPIDocument: missing path /docs/{path}")
+	}
+
+	checkValue(t, pi.Operations["GET"].Parameters[0].Schema, map[string]interface{}{
+		"type":                "string",
+		"x-nanomux-catch-all": true,
+	})
+
+	var b []byte
+	b, err = ro.OpenAPI(info)
+	checkErr(t, err, false)
+
+	var m map[string]interface{}
+	checkErr(t, json.Unmarshal(b, &m), false)
+
+	var paths = m["paths"].(map[string]interface{})
+	var item = paths["/users/{id}/"].(map[string]interface{})
+	if item["get"] == nil || item["post"] == nil || item["servers"] == nil {
+		t.Fatalf("OpenAPI: path item = %v", item)
+	}
+
+	if item["options"] != nil {
+		t.Fatalf("OpenAPI: default OPTIONS handler was included")
+	}
+
+	// The operations of different hosts with the same path are merged unless
+	// they have the same HTTP method.
+	ro = NewRouter()
+	ro.SetURLHandlerFor("GET", "http://example.com/a", h)
+	ro.SetURLHandlerFor("POST", "http://example.org/a", h)
+	doc = ro.OpenAPIDocument(OpenAPIInfo{})
+	checkValue(t, len(doc.Paths["/a"].Operations), 2)
+	checkValue(t, len(doc.Paths["/a"].Servers), 2)
+
+	ro.SetURLHandlerFor("GET", "http://example.org/a", h)
+	_, err = ro.OpenAPI(OpenAPIInfo{})
+	checkValue(t, errors.Is(err, ErrConflictingOperation), true)
+	testPanicker(t, true, func() { ro.OpenAPIDocument(OpenAPIInfo{}) })
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -75,7 +76,9 @@ func FnHrWithArgs(hf http.HandlerFunc) Handler {
 // method. For example, HandleGet and HandleCustom are considered the handlers
 // of the GET and CUSTOM HTTP methods, respectively. If the type has the
 // HandleNotAllowedMethod then it's used as the handler of the not allowed
-// HTTB methods.
+// HTTB methods. If the type implements the OperationDescriber interface, its
+// handlers are described in the OpenAPI document of the router. Methods with
+// the signature of the ErrHandler are also accepted.
 type Impl interface{}
 
 // --------------------------------------------------
@@ -83,6 +86,25 @@ type Impl interface{}
 type _MethodHandlerPair struct {
 	method  string
 	handler Handler
+
+	// dflt is set when the handler is the default handler of the OPTIONS
+	// method, which is bound to its _RequestHandlerBase.
+	dflt bool
+
+	// mws are the middlewares the handler was wrapped with. They are kept
+	// to rebuild the default handler when the _RequestHandlerBase is cloned.
+	mws []Middleware
+
+	// base is the handler before it was wrapped with the middlewares.
+	base Handler
+
+	// mediaHandlers are set when the handlers of the method were set with
+	// the media types they produce or consume. The handler then selects one
+	// of them.
+	mediaHandlers _MediaHandlers
+
+	// operation is the OpenAPI metadata of the handler.
+	operation *OpenAPIOperation
 }
 
 type _MethodHandlerPairs []_MethodHandlerPair
@@ -161,13 +183,65 @@ func (mhps _MethodHandlerPairs) get(method string) (int, Handler) {
 func (mhps *_MethodHandlerPairs) set(method string, handler Handler) {
 	var i, _ = mhps.get(method)
 	if i < 0 {
-		*mhps = append(*mhps, _MethodHandlerPair{method, handler})
+		*mhps = append(
+			*mhps,
+			_MethodHandlerPair{
+				method:  method,
+				handler: handler,
+				base:    handler,
+			},
+		)
+
 		mhps.sort()
 
 		return
 	}
 
-	(*mhps)[i].handler = handler
+	(*mhps)[i] = _MethodHandlerPair{
+		method:  method,
+		handler: handler,
+		base:    handler,
+	}
+}
+
+// setMediaHandler adds the media handler to the handlers of the method. If
+// the method has a handler without media types, it's kept as a fallback. The
+// middlewares the method's handler was wrapped with wrap the media handlers.
+func (mhps *_MethodHandlerPairs) setMediaHandler(
+	method string,
+	mh _MediaHandler,
+) {
+	var i, _ = mhps.get(method)
+	if i < 0 {
+		var mhs = _MediaHandlers{mh}
+		mhps.set(method, mhs.handle)
+		i, _ = mhps.get(method)
+		(*mhps)[i].mediaHandlers = mhs
+		return
+	}
+
+	var mhp = &(*mhps)[i]
+	var mhs = mhp.mediaHandlers
+	if mhs == nil {
+		if !mhp.dflt {
+			// The fallback is the unwrapped handler, because the middlewares
+			// wrap all the media handlers.
+			mhs = _MediaHandlers{{handler: mhp.base}}
+		}
+
+		mhp.dflt = false
+	}
+
+	mhs = append(mhs[:len(mhs):len(mhs)], mh)
+	mhp.mediaHandlers = mhs
+	mhp.base = mhs.handle
+	mhp.handler = wrapWithMiddlewares(mhs.handle, mhp.mws)
+}
+
+// wrap wraps the handler at the index with the middleware.
+func (mhps _MethodHandlerPairs) wrap(i int, mw Middleware) {
+	mhps[i].handler = mw(mhps[i].handler)
+	mhps[i].mws = append(mhps[i].mws, mw)
 }
 
 // --------------------------------------------------
@@ -179,6 +253,11 @@ func (mhps *_MethodHandlerPairs) set(method string, handler Handler) {
 type _RequestHandlerBase struct {
 	mhPairs                     _MethodHandlerPairs
 	notAllowedHTTPMethodHandler Handler
+
+	// notAllowedHandlerBase is the not allowed HTTP method handler that was
+	// set before wrapping. When it's nil, the default handler was wrapped.
+	notAllowedHandlerBase Handler
+	notAllowedHandlerMws  []Middleware
 }
 
 // -------------------------
@@ -213,6 +292,14 @@ func detectHTTPMethodHandlersOf(impl Impl) (*_RequestHandlerBase, error) {
 		},
 	)
 
+	var errHandlerType = reflect.TypeOf(
+		// Signature of the ErrHandler.
+		func(http.ResponseWriter, *http.Request, *Args) error {
+			// Unreachable.
+			return nil
+		},
+	)
+
 	for hm, n := range hmns {
 		var m = v.MethodByName(n)
 		if m.Kind() != reflect.Func {
@@ -220,20 +307,37 @@ func detectHTTPMethodHandlersOf(impl Impl) (*_RequestHandlerBase, error) {
 			continue
 		}
 
-		if m.Type() != handlerType {
-			// Method doesn't have the signature of the Handler.
-			continue
-		}
+		var hf Handler
+		switch m.Type() {
+		case handlerType:
+			var f, ok = m.Interface().(func(
+				http.ResponseWriter,
+				*http.Request,
+				*Args,
+			) bool)
+
+			if !ok {
+				// Unreachable.
+				return nil, newErr("failed to get the handler method")
+			}
 
-		var hf, ok = m.Interface().(func(
-			http.ResponseWriter,
-			*http.Request,
-			*Args,
-		) bool)
+			hf = f
+		case errHandlerType:
+			var f, ok = m.Interface().(func(
+				http.ResponseWriter,
+				*http.Request,
+				*Args,
+			) error)
+
+			if !ok {
+				// Unreachable.
+				return nil, newErr("failed to get the handler method")
+			}
 
-		if !ok {
-			// Unreachable.
-			return nil, newErr("failed to get the handler method")
+			hf = ErrHr(f)
+		default:
+			// Method doesn't have the signature of the Handler or ErrHandler.
+			continue
 		}
 
 		if hm == "NOTALLOWEDMETHOD" {
@@ -248,14 +352,21 @@ func detectHTTPMethodHandlersOf(impl Impl) (*_RequestHandlerBase, error) {
 		return nil, nil
 	}
 
-	var rhb = &_RequestHandlerBase{handlers, notAllowedHTTPMethodsHandler}
+	if od, ok := impl.(OperationDescriber); ok {
+		for i := range handlers {
+			handlers[i].operation = od.DescribeOperation(handlers[i].method)
+		}
+	}
+
+	var rhb = &_RequestHandlerBase{
+		mhPairs:                     handlers,
+		notAllowedHTTPMethodHandler: notAllowedHTTPMethodsHandler,
+	}
+
 	if lhandlers > 0 {
 		var _, hf = rhb.mhPairs.get(http.MethodOptions)
 		if hf == nil {
-			rhb.mhPairs.set(
-				http.MethodOptions,
-				rhb.handleOptionsHTTPMethod,
-			)
+			rhb.setDefaultOptionsHandler()
 		}
 	} else {
 		rhb.mhPairs = nil
@@ -269,9 +380,10 @@ func detectHTTPMethodHandlersOf(impl Impl) (*_RequestHandlerBase, error) {
 func (rhb *_RequestHandlerBase) setHandlerFor(
 	methods string,
 	h Handler,
+	opts ...HandlerOption,
 ) error {
 	if h == nil {
-		return newErr("%w", errNilArgument)
+		return newErr("%w", ErrNilArgument)
 	}
 
 	var ms = toUpperSplitByCommaSpace(methods)
@@ -282,6 +394,8 @@ func (rhb *_RequestHandlerBase) setHandlerFor(
 
 	if lms == 1 && ms[0] == "!" {
 		rhb.notAllowedHTTPMethodHandler = h
+		rhb.notAllowedHandlerBase = nil
+		rhb.notAllowedHandlerMws = nil
 		return nil
 	}
 
@@ -289,21 +403,44 @@ func (rhb *_RequestHandlerBase) setHandlerFor(
 		rhb.mhPairs = _MethodHandlerPairs{}
 	}
 
+	var mh = _MediaHandler{handler: h}
+	for i, opt := range opts {
+		if opt == nil {
+			return newErr("%w at index %d", ErrNilArgument, i)
+		}
+
+		opt(&mh)
+	}
+
 	for _, m := range ms {
-		rhb.mhPairs.set(m, h)
+		if len(mh.produces) > 0 || len(mh.consumes) > 0 {
+			rhb.mhPairs.setMediaHandler(m, mh)
+		} else {
+			rhb.mhPairs.set(m, h)
+		}
+
+		if mh.operation != nil {
+			var i, _ = rhb.mhPairs.get(m)
+			rhb.mhPairs[i].operation = mh.operation
+		}
 	}
 
 	_, h = rhb.mhPairs.get(http.MethodOptions)
 	if h == nil {
-		rhb.mhPairs.set(
-			http.MethodOptions,
-			rhb.handleOptionsHTTPMethod,
-		)
+		rhb.setDefaultOptionsHandler()
 	}
 
 	return nil
 }
 
+// setDefaultOptionsHandler sets the default handler of the OPTIONS method.
+func (rhb *_RequestHandlerBase) setDefaultOptionsHandler() {
+	rhb.mhPairs.set(http.MethodOptions, rhb.handleOptionsHTTPMethod)
+
+	var i, _ = rhb.mhPairs.get(http.MethodOptions)
+	rhb.mhPairs[i].dflt = true
+}
+
 func (rhb *_RequestHandlerBase) handlerOf(method string) Handler {
 	var ms = toUpperSplitByCommaSpace(method)
 	var lms = len(ms)
@@ -347,6 +484,10 @@ func (rhb *_RequestHandlerBase) wrapHandlerOf(
 
 	if lms == 1 {
 		if ms[0] == "!" {
+			if rhb.notAllowedHandlerMws == nil {
+				rhb.notAllowedHandlerBase = rhb.notAllowedHTTPMethodHandler
+			}
+
 			rhb.notAllowedHTTPMethodHandler = rhb.handlerOf("!")
 			for i, mw := range mws {
 				if mw == nil {
@@ -356,18 +497,22 @@ func (rhb *_RequestHandlerBase) wrapHandlerOf(
 				rhb.notAllowedHTTPMethodHandler = mw(
 					rhb.notAllowedHTTPMethodHandler,
 				)
+
+				rhb.notAllowedHandlerMws = append(
+					rhb.notAllowedHandlerMws,
+					mw,
+				)
 			}
 
 			return nil
 		} else if ms[0] == "*" {
-			for _, mhp := range rhb.mhPairs {
+			for mi := range rhb.mhPairs {
 				for i, mw := range mws {
 					if mw == nil {
 						return newErr("%w at index %d", errNoMiddleware, i)
 					}
 
-					mhp.handler = mw(mhp.handler)
-					rhb.mhPairs.set(mhp.method, mhp.handler)
+					rhb.mhPairs.wrap(mi, mw)
 				}
 			}
 
@@ -376,14 +521,13 @@ func (rhb *_RequestHandlerBase) wrapHandlerOf(
 	}
 
 	for _, m := range ms {
-		if _, h := rhb.mhPairs.get(m); h != nil {
+		if mi, h := rhb.mhPairs.get(m); h != nil {
 			for i, mw := range mws {
 				if mw == nil {
 					return newErr("%w at index %d", errNoMiddleware, i)
 				}
 
-				h = mw(h)
-				rhb.mhPairs.set(m, h)
+				rhb.mhPairs.wrap(mi, mw)
 			}
 		} else {
 			return newErr("%w for the method %q", errNoHandlerExists, m)
@@ -401,13 +545,35 @@ func (rhb *_RequestHandlerBase) handleRequest(
 	args *Args,
 ) bool {
 	if rhb == nil || len(rhb.mhPairs) == 0 {
-		return notFoundResourceHandler(w, r, args)
+		var p _Parent
+		if args != nil && args._r != nil {
+			p = args._r
+		}
+
+		args.explainNotFound("", reasonNoHandler, nil)
+		return handleNotFound(p, w, r, args)
 	}
 
-	if _, handler := rhb.mhPairs.get(r.Method); handler != nil {
+	if cp := corsPolicyOfArgs(args); cp != nil && !isPreflightRequest(r) {
+		cp.decorate(w, r)
+	}
+
+	if m, handler := rhb.methodHandlerFor(r, args); handler != nil {
+		if m != r.Method {
+			// The HEAD request is handled by the GET handler.
+			var hw = &_HeadResponseWriter{ResponseWriter: w}
+			var handled = handler(hw, r, args)
+			hw.finish()
+			return handled
+		}
+
 		return handler(w, r, args)
 	}
 
+	if args != nil {
+		args.outcome = outcomeMethodNotAllowed
+	}
+
 	if rhb.notAllowedHTTPMethodHandler != nil {
 		return rhb.notAllowedHTTPMethodHandler(w, r, args)
 	}
@@ -415,25 +581,98 @@ func (rhb *_RequestHandlerBase) handleRequest(
 	return rhb.handleNotAllowedHTTPMethod(w, r, args)
 }
 
-func (rhb *_RequestHandlerBase) allowedHTTPMethods() string {
-	var strb = strings.Builder{}
+// methodHandlerFor returns the HTTP method of the handler that handles the
+// request and the handler itself. When a HEAD request is handled by the GET
+// handler, the method is GET. If there is no handler for the request's
+// method, an empty string and nil are returned.
+func (rhb *_RequestHandlerBase) methodHandlerFor(
+	r *http.Request,
+	args *Args,
+) (string, Handler) {
+	if _, handler := rhb.mhPairs.get(r.Method); handler != nil {
+		return r.Method, handler
+	}
+
+	if r.Method == http.MethodHead && handlesHeadWithGet(args) {
+		if _, handler := rhb.mhPairs.get(http.MethodGet); handler != nil {
+			return http.MethodGet, handler
+		}
+	}
+
+	return "", nil
+}
+
+// handlesHeadWithGet returns true if the responder handling the request was
+// configured to handle HEAD requests with its GET handler.
+func handlesHeadWithGet(args *Args) bool {
+	return args != nil && args._r != nil && args._r.HandlesHeadWithGet()
+}
+
+// allowedMethods returns the HTTP methods in use. When the head argument is
+// true and there is a GET handler but no HEAD handler, HEAD is added to the
+// methods.
+func (rhb *_RequestHandlerBase) allowedMethods(head bool) []string {
+	if rhb == nil || len(rhb.mhPairs) == 0 {
+		return nil
+	}
+
+	if head {
+		var _, gh = rhb.mhPairs.get(http.MethodGet)
+		var _, hh = rhb.mhPairs.get(http.MethodHead)
+		head = gh != nil && hh == nil
+	}
+
+	var ms = make([]string, 0, len(rhb.mhPairs)+1)
 	for _, mhp := range rhb.mhPairs {
-		if strb.Len() > 0 {
-			strb.WriteString(", ")
+		if head && mhp.method > http.MethodHead {
+			// Method handler pairs are sorted by their methods.
+			ms = append(ms, http.MethodHead)
+			head = false
 		}
 
-		strb.WriteString(mhp.method)
+		ms = append(ms, mhp.method)
+	}
+
+	if head {
+		ms = append(ms, http.MethodHead)
 	}
 
-	return strb.String()
+	return ms
+}
+
+func (rhb *_RequestHandlerBase) allowedHTTPMethods(args *Args) string {
+	return strings.Join(rhb.allowedMethods(handlesHeadWithGet(args)), ", ")
 }
 
 func (rhb *_RequestHandlerBase) handleOptionsHTTPMethod(
 	w http.ResponseWriter,
 	r *http.Request,
-	_ *Args,
+	args *Args,
 ) bool {
-	w.Header().Add("Allow", rhb.allowedHTTPMethods())
+	if cp := corsPolicyOfArgs(args); cp != nil && isPreflightRequest(r) {
+		var ms = rhb.allowedMethods(handlesHeadWithGet(args))
+		if cp.handlePreflight(w, r, ms) {
+			return true
+		}
+	}
+
+	var h = w.Header()
+	h.Add("Allow", rhb.allowedHTTPMethods(args))
+
+	for _, mhp := range rhb.mhPairs {
+		var mts = mhp.mediaHandlers.consumedMediaTypes()
+		if len(mts) == 0 {
+			continue
+		}
+
+		switch mhp.method {
+		case http.MethodPost:
+			h.Set("Accept-Post", strings.Join(mts, ", "))
+		case http.MethodPatch:
+			h.Set("Accept-Patch", strings.Join(mts, ", "))
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	return true
 }
@@ -441,9 +680,9 @@ func (rhb *_RequestHandlerBase) handleOptionsHTTPMethod(
 func (rhb *_RequestHandlerBase) handleNotAllowedHTTPMethod(
 	w http.ResponseWriter,
 	r *http.Request,
-	_ *Args,
+	args *Args,
 ) bool {
-	w.Header().Add("Allow", rhb.allowedHTTPMethods())
+	w.Header().Add("Allow", rhb.allowedHTTPMethods(args))
 
 	http.Error(
 		w,
@@ -456,18 +695,11 @@ func (rhb *_RequestHandlerBase) handleNotAllowedHTTPMethod(
 
 // -------------------------
 
-// AllowedHTTPMethods returns the HTTP methods in use.
+// AllowedHTTPMethods returns the HTTP methods in use. HEAD is included only
+// when there is a HEAD handler. The hosts and resources override the method
+// to also include HEAD when they handle it with their GET handler.
 func (rhb *_RequestHandlerBase) AllowedHTTPMethods() []string {
-	if rhb == nil || len(rhb.mhPairs) == 0 {
-		return nil
-	}
-
-	var ms = []string{}
-	for _, mhp := range rhb.mhPairs {
-		ms = append(ms, mhp.method)
-	}
-
-	return ms
+	return rhb.allowedMethods(false)
 }
 
 // -------------------------
@@ -483,6 +715,69 @@ func (rhb *_RequestHandlerBase) ServeHTTP(
 
 // --------------------------------------------------
 
+// _HeadResponseWriter is used to handle a HEAD request with a GET handler.
+// It discards the response body but keeps its length to report it in the
+// "Content-Length" header. The header is written when the handler returns.
+type _HeadResponseWriter struct {
+	http.ResponseWriter
+	code   int
+	length int
+	sniff  []byte
+}
+
+func (hw *_HeadResponseWriter) WriteHeader(code int) {
+	if hw.code == 0 {
+		hw.code = code
+	}
+}
+
+func (hw *_HeadResponseWriter) Write(b []byte) (int, error) {
+	if hw.code == 0 {
+		hw.code = http.StatusOK
+	}
+
+	if len(hw.sniff) < 512 {
+		var n = 512 - len(hw.sniff)
+		if n > len(b) {
+			n = len(b)
+		}
+
+		hw.sniff = append(hw.sniff, b[:n]...)
+	}
+
+	hw.length += len(b)
+	return len(b), nil
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (hw *_HeadResponseWriter) Unwrap() http.ResponseWriter {
+	return hw.ResponseWriter
+}
+
+// finish writes the header with the status code set by the handler. If the
+// handler didn't set the "Content-Length" and "Content-Type" headers, they
+// are set from the discarded body.
+func (hw *_HeadResponseWriter) finish() {
+	if hw.code == 0 {
+		return
+	}
+
+	var h = hw.Header()
+	if hw.length > 0 {
+		if h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" {
+			h.Set("Content-Length", strconv.Itoa(hw.length))
+		}
+
+		if _, ok := h["Content-Type"]; !ok {
+			h.Set("Content-Type", http.DetectContentType(hw.sniff))
+		}
+	}
+
+	hw.ResponseWriter.WriteHeader(hw.code)
+}
+
+// --------------------------------------------------
+
 var permanentRedirectCode = http.StatusPermanentRedirect
 
 // SetPermanentRedirectCode sets the status code for permanent redirects.
@@ -494,11 +789,12 @@ var permanentRedirectCode = http.StatusPermanentRedirect
 // method to GET. The 308 status code does not allow this behavior. By default,
 // the 308 status code is sent.
 //
-// Responders may have their own permanent redirect status code set.
+// Routers and responders may have their own permanent redirect status code
+// set.
 func SetPermanentRedirectCode(code int) {
 	if code != http.StatusMovedPermanently &&
 		code != http.StatusPermanentRedirect {
-		panicWithErr("%w", errConflictingStatusCode)
+		panicWithErr("%w", ErrConflictingStatusCode)
 	}
 
 	permanentRedirectCode = code
@@ -513,7 +809,7 @@ func SetPermanentRedirectCode(code int) {
 // method to GET. The 308 status code does not allow this behavior. By default,
 // the 308 status code is sent.
 //
-// Responders may have their own permanent redirect status code.
+// Routers and responders may have their own permanent redirect status code.
 func PermanentRedirectCode() int {
 	return permanentRedirectCode
 }
@@ -550,10 +846,10 @@ var commonRedirectHandler = func(
 // It's also used when responders have been configured to redirect requests
 // to a new location.
 //
-// Responders may have their own redirect handler set.
+// Routers and responders may have their own redirect handler set.
 func SetCommonRedirectHandler(fn RedirectHandler) {
 	if fn == nil {
-		panicWithErr("%w", errNilArgument)
+		panicWithErr("%w", ErrNilArgument)
 	}
 
 	commonRedirectHandler = fn
@@ -589,13 +885,82 @@ func WrapCommonRedirectHandler(
 
 	for i, mw := range mws {
 		if mw == nil {
-			panicWithErr("%w at index %d", errNilArgument, i)
+			panicWithErr("%w at index %d", ErrNilArgument, i)
 		}
 
 		commonRedirectHandler = mw(commonRedirectHandler)
 	}
 }
 
+// permanentRedirectCodeOf returns the permanent redirect code of the
+// responder or router. If it doesn't have one, the code of its closest
+// ancestor is returned. When none of them has a code, the global code
+// is returned.
+func permanentRedirectCodeOf(p _Parent) int {
+	for ; p != nil; p = p.parent() {
+		var code int
+		switch p := p.(type) {
+		case *Router:
+			code = p.permanentRedirectCode
+		case *Host:
+			code = p.permanentRedirectCode
+		case *Resource:
+			code = p.permanentRedirectCode
+		}
+
+		if code > 0 {
+			return code
+		}
+	}
+
+	return permanentRedirectCode
+}
+
+// redirectHandlerOf returns the redirect handler of the responder or router.
+// If it doesn't have one, the handler of its closest ancestor is returned.
+// When none of them has a handler, the common redirect handler is returned.
+func redirectHandlerOf(p _Parent) RedirectHandler {
+	for ; p != nil; p = p.parent() {
+		var h RedirectHandler
+		switch p := p.(type) {
+		case *Router:
+			h = p.redirectHandler
+		case *Host:
+			h = p.redirectHandler
+		case *Resource:
+			h = p.redirectHandler
+		}
+
+		if h != nil {
+			return h
+		}
+	}
+
+	return commonRedirectHandler
+}
+
+// handleRedirect marks the request as redirected in the args and calls the
+// redirect handler of the responder or router. When the request is being
+// resolved, the redirect is recorded instead.
+func handleRedirect(
+	p _Parent,
+	w http.ResponseWriter,
+	r *http.Request,
+	url string,
+	code int,
+	args *Args,
+) bool {
+	if args != nil {
+		if args.resolution != nil {
+			return args.resolution.redirect(p, url, code)
+		}
+
+		args.outcome = outcomeRedirected
+	}
+
+	return redirectHandlerOf(p)(w, r, url, code, args)
+}
+
 // -------------------------
 
 func redirector(
@@ -603,8 +968,7 @@ func redirector(
 	url string,
 	redirectCode int,
 ) (Handler, error) {
-	var lUrl = len(url)
-	if lUrl == 0 {
+	if len(url) == 0 {
 		return nil, newErr("%w: empty url", errInvalidArgument)
 	}
 
@@ -616,42 +980,46 @@ func redirector(
 		)
 	}
 
-	if tUrl := strings.TrimPrefix(url, "http"); len(tUrl) == lUrl {
-		if url[0] != '/' {
-			url = "/" + url
-			lUrl = len(url)
-		}
+	url = normalizedRedirectURL(url)
+	return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		var rURL = redirectURLFor(url, args)
+		return handleRedirect(rb.derived, w, r, rURL, redirectCode, args)
+	}, nil
+}
+
+// normalizedRedirectURL prefixes the url with a slash when it's a path that
+// doesn't start with a slash.
+func normalizedRedirectURL(url string) string {
+	if !strings.HasPrefix(url, "http") && url[0] != '/' {
+		return "/" + url
 	}
 
-	return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
-		// The url must be copied. Because it's a captured value,
-		// changes to it will be permanent.
-		var urlStr = url
-
-		var rPath = args.RemainingPath()
-		var lrPath = len(rPath)
-		if lrPath > 0 {
-			if rPath[0] == '/' {
-				if urlStr[lUrl-1] != '/' {
-					urlStr += rPath
-				} else {
-					urlStr += rPath[1:]
-				}
-			} else {
-				if urlStr[lUrl-1] == '/' {
-					urlStr += rPath
-				} else {
-					urlStr += "/" + rPath
-				}
-			}
-		}
+	return url
+}
 
-		if rb.redirectHandler == nil {
-			return commonRedirectHandler(w, r, urlStr, redirectCode, args)
+// redirectURLFor returns the normalized url with the remaining path of the
+// request's URL appended.
+func redirectURLFor(url string, args *Args) string {
+	var rPath = args.RemainingPath()
+	var lrPath = len(rPath)
+	if lrPath == 0 {
+		return url
+	}
+
+	var lUrl = len(url)
+	if rPath[0] == '/' {
+		if url[lUrl-1] != '/' {
+			return url + rPath
 		}
 
-		return rb.redirectHandler(w, r, urlStr, redirectCode, args)
-	}, nil
+		return url + rPath[1:]
+	}
+
+	if url[lUrl-1] == '/' {
+		return url + rPath
+	}
+
+	return url + "/" + rPath
 }
 
 // --------------------------------------------------
@@ -669,7 +1037,7 @@ var notFoundResourceHandler Handler = func(
 // not-found resources.
 func SetHandlerForNotFound(handler Handler) {
 	if handler == nil {
-		panicWithErr("%w", errNilArgument)
+		panicWithErr("%w", ErrNilArgument)
 	}
 
 	notFoundResourceHandler = handler
@@ -691,9 +1059,53 @@ func WrapHandlerOfNotFound(mws ...Middleware) {
 
 	for i, mw := range mws {
 		if mw == nil {
-			panicWithErr("%w at index %d", errNilArgument, i)
+			panicWithErr("%w at index %d", ErrNilArgument, i)
 		}
 
 		notFoundResourceHandler = mw(notFoundResourceHandler)
 	}
 }
+
+// notFoundHandlerOf returns the not-found handler of the responder or router.
+// If it doesn't have one, the handler of its closest ancestor is returned.
+// When none of them has a handler, the global handler is returned.
+func notFoundHandlerOf(p _Parent) Handler {
+	for ; p != nil; p = p.parent() {
+		var h Handler
+		switch p := p.(type) {
+		case *Router:
+			h = p.notFoundHandler
+		case *Host:
+			h = p.notFoundHandler
+		case *Resource:
+			h = p.notFoundHandler
+		}
+
+		if h != nil {
+			return h
+		}
+	}
+
+	return notFoundResourceHandler
+}
+
+// handleNotFound marks the request as not found in the args and calls the
+// not-found handler of the responder or router. When the request is being
+// resolved, the responder is recorded instead.
+func handleNotFound(
+	p _Parent,
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+) bool {
+	if args != nil {
+		if args.resolution != nil {
+			return args.resolution.notFoundBy(p)
+		}
+
+		args.outcome = outcomeNotFound
+	}
+
+	var handled = notFoundHandlerOf(p)(w, r, args)
+	return renderRecordedErr(w, r, args, handled)
+}
@@ -0,0 +1,451 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// --------------------------------------------------
+
+// Resolution explains how a request would be matched by the router, host, or
+// resource. It's returned from the Resolve methods.
+type Resolution struct {
+	// Steps are the hosts and resources the request would be passed to, in
+	// the order they are visited. It includes the responders that were
+	// visited but couldn't handle the request.
+	Steps []ResolutionStep
+
+	// Host is the host of the responder that would respond to the request.
+	// When the responder is a host, it's the host itself.
+	Host *Host
+
+	// Resource is the resource that would respond to the request. It's nil
+	// when the request would be responded to by a host or the router.
+	Resource *Resource
+
+	// HostPathValues are the values captured from the host and path segments
+	// of the request's URL.
+	HostPathValues HostPathValues
+
+	// HandlerMethod is the HTTP method of the handler that would handle the
+	// request. When a HEAD request would be handled by the GET handler, it's
+	// GET. It's empty when there is no handler for the request's method.
+	HandlerMethod string
+
+	// Handler is the request handler of the responder, wrapped with its
+	// middlewares, that would be called to handle the request. It calls the
+	// handler of the HandlerMethod or the not allowed HTTP method handler
+	// when MethodNotAllowed is true. It's nil when the request would be
+	// redirected or not found.
+	Handler Handler
+
+	// MethodNotAllowed is true when the responder has no handler for the
+	// request's method.
+	MethodNotAllowed bool
+
+	// Redirect is the redirect that would be issued, if any.
+	Redirect *ResolvedRedirect
+
+	// NotFound describes why the request would not be found, if it's not.
+	NotFound *ResolvedNotFound
+
+	reasons    []string
+	badSegment bool
+}
+
+// ResolutionStep is the host or resource the request would be passed to.
+type ResolutionStep struct {
+	// Segment is the host or path segment of the request's URL matched by
+	// the responder's template. For the root resource, it's "/".
+	Segment string
+
+	// Template is the responder's template.
+	Template string
+
+	// Host is the host of the step. It's nil when the step is a resource.
+	Host *Host
+
+	// Resource is the resource of the step. It's nil when the step is a host.
+	Resource *Resource
+}
+
+// ResolvedRedirect is the redirect that would be issued.
+type ResolvedRedirect struct {
+	// Reasons are the reasons of the redirect: "insecure request", "unclean
+	// path", "canonical case", "trailing slash", or "redirect" when the
+	// responder was configured with the RedirectRequestTo or
+	// RedirectAnyRequestTo methods.
+	Reasons []string
+
+	// URL is the target URL of the redirect.
+	URL string
+
+	// Code is the status code of the redirect.
+	Code int
+}
+
+// ResolvedNotFound describes why the request would not be found.
+type ResolvedNotFound struct {
+	// Segment is the host or path segment that couldn't be matched. It's
+	// empty when the segment was matched, but the responder couldn't
+	// respond to the request.
+	Segment string
+
+	// Reason is the reason the request would not be found: "no matching
+	// host", "no matching resource", "insecure request", "trailing slash",
+	// or "no handler".
+	Reason string
+
+	// Tried are the templates of the hosts or sibling resources that were
+	// tried to match the segment, in their matching order.
+	Tried []string
+}
+
+// Reasons of redirects and not-found requests.
+const (
+	reasonInsecureRequest    = "insecure request"
+	reasonUncleanPath        = "unclean path"
+	reasonCanonicalCase      = "canonical case"
+	reasonTrailingSlash      = "trailing slash"
+	reasonRedirect           = "redirect"
+	reasonNoMatchingHost     = "no matching host"
+	reasonNoMatchingResource = "no matching resource"
+	reasonNoHandler          = "no handler"
+)
+
+// String returns the explanation of the resolution, one step per line.
+func (res *Resolution) String() string {
+	var strb strings.Builder
+	for _, s := range res.Steps {
+		var kind = "resource"
+		if s.Host != nil {
+			kind = "host"
+		}
+
+		fmt.Fprintf(&strb, "%s %q matched %s\n", kind, s.Segment, s.Template)
+	}
+
+	if len(res.HostPathValues) > 0 {
+		strb.WriteString("values")
+		for _, v := range res.HostPathValues {
+			fmt.Fprintf(&strb, " %s=%q", v.key, v.value)
+		}
+
+		strb.WriteByte('\n')
+	}
+
+	switch {
+	case res.Redirect != nil:
+		fmt.Fprintf(
+			&strb,
+			"redirect %d to %s (%s)",
+			res.Redirect.Code,
+			res.Redirect.URL,
+			strings.Join(res.Redirect.Reasons, ", "),
+		)
+	case res.NotFound != nil:
+		fmt.Fprintf(&strb, "not found (%s)", res.NotFound.Reason)
+		if res.NotFound.Segment != "" || len(res.NotFound.Tried) > 0 {
+			fmt.Fprintf(
+				&strb,
+				": segment %q, tried [%s]",
+				res.NotFound.Segment,
+				strings.Join(res.NotFound.Tried, " "),
+			)
+		}
+	case res.MethodNotAllowed:
+		strb.WriteString("method not allowed")
+	default:
+		fmt.Fprintf(&strb, "handled by the %s handler", res.HandlerMethod)
+	}
+
+	return strb.String()
+}
+
+// -------------------------
+
+// addStep adds the responder to the steps.
+func (res *Resolution) addStep(segment string, _r _Responder) {
+	var s = ResolutionStep{Segment: segment, Template: _r.Template().String()}
+	switch _r := _r.(type) {
+	case *Host:
+		s.Host = _r
+	case *Resource:
+		s.Resource = _r
+	}
+
+	res.Steps = append(res.Steps, s)
+}
+
+// setResponder sets the responder that would respond to the request.
+func (res *Resolution) setResponder(p _Parent) {
+	res.Host, res.Resource = nil, nil
+	switch p := p.(type) {
+	case *Host:
+		res.Host = p
+	case *Resource:
+		res.Resource = p
+		res.Host = p.Host()
+	}
+}
+
+// notFound records that the request would not be found by the responder.
+// It returns true like the not-found handlers.
+func (res *Resolution) notFound(
+	p _Parent,
+	segment, reason string,
+	tried []string,
+) bool {
+	res.setResponder(p)
+	res.Redirect = nil
+	res.NotFound = &ResolvedNotFound{
+		Segment: segment,
+		Reason:  reason,
+		Tried:   tried,
+	}
+
+	return true
+}
+
+// notFoundBy records that the request would be responded to by the
+// not-found handler of p. The reason explained by the responder that
+// couldn't match or respond to the request is kept. It returns true like
+// the not-found handlers.
+func (res *Resolution) notFoundBy(p _Parent) bool {
+	if res.NotFound == nil {
+		return res.notFound(p, "", reasonNoMatchingResource, nil)
+	}
+
+	res.setResponder(p)
+	res.Redirect = nil
+	return true
+}
+
+// redirect records the redirect that would be issued by the responder with
+// the reasons explained so far. When no reason was explained, the responder
+// was configured to redirect the request. It returns true like the redirect
+// handlers.
+func (res *Resolution) redirect(p _Parent, url string, code int) bool {
+	var reasons = res.reasons
+	if len(reasons) == 0 {
+		reasons = []string{reasonRedirect}
+	}
+
+	res.setResponder(p)
+	res.NotFound, res.reasons = nil, nil
+	res.Redirect = &ResolvedRedirect{Reasons: reasons, URL: url, Code: code}
+	return true
+}
+
+// handle records the request handler of the responder that would handle the
+// request and the HTTP method handler it would call.
+func (res *Resolution) handle(
+	rb *_ResponderBase,
+	r *http.Request,
+	args *Args,
+) bool {
+	var rhb = rb._RequestHandlerBase
+	if rhb == nil || len(rhb.mhPairs) == 0 {
+		return res.notFound(rb.derived, "", reasonNoHandler, nil)
+	}
+
+	res.setResponder(rb.derived)
+	res.NotFound = nil
+	res.Handler = rb.requestHandler
+
+	var m, h = rhb.methodHandlerFor(r, args)
+	if h != nil {
+		res.HandlerMethod = m
+		return true
+	}
+
+	res.MethodNotAllowed = true
+	return true
+}
+
+// --------------------------------------------------
+
+// Resolve explains how the request with the method and URL would be
+// matched, without calling any handler. The request goes through the same
+// matching as when the router serves it, but the decisions are recorded
+// instead of being acted upon. The resolution reports the hosts and resources
+// the request would be passed to, the values captured from the URL, the
+// handler that would be selected, and the redirect that would be issued. When
+// the request would not be found, the resolution tells which segment couldn't
+// be matched and which templates were tried.
+//
+// The middlewares of the request passers are not called, so the changes they
+// would make to the routing are not reflected in the resolution. A URL with
+// the https scheme is resolved as a request received over TLS.
+func (ro *Router) Resolve(method, urlStr string) (*Resolution, error) {
+	var r, err = resolutionRequest(method, urlStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var current = ro.current()
+	var args, res = resolutionArgs(r, nil)
+	if !current.passRequest(nil, r, args) {
+		handleNotFound(current, nil, r, args)
+	}
+
+	return res.finish(args)
+}
+
+// Resolve explains how the request with the method and URL would be matched
+// when the host is used directly, without calling any handler. See the
+// Router's Resolve method for details.
+func (hb *Host) Resolve(method, urlStr string) (*Resolution, error) {
+	var r, err = resolutionRequest(method, urlStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var args, res = resolutionArgs(r, hb.derived)
+	hb.serve(nil, r, args)
+	return res.finish(args)
+}
+
+// Resolve explains how the request with the method and URL would be matched
+// when the resource is used directly, without calling any handler. See the
+// Router's Resolve method for details.
+func (rb *Resource) Resolve(method, urlStr string) (*Resolution, error) {
+	var r, err = resolutionRequest(method, urlStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var args, res = resolutionArgs(r, rb.derived)
+	rb.serve(nil, r, args)
+	return res.finish(args)
+}
+
+// resolutionArgs returns the args that make the responders record their
+// decisions in the returned resolution instead of responding to the request.
+func resolutionArgs(r *http.Request, _r _Responder) (*Args, *Resolution) {
+	var args = getArgs(r.URL, _r)
+	args.resolution = &Resolution{}
+	return args, args.resolution
+}
+
+// finish copies the captured values to the resolution and puts the args
+// back in the pool.
+func (res *Resolution) finish(args *Args) (*Resolution, error) {
+	defer putArgsInThePool(args)
+	if res.badSegment {
+		return nil, newErr("%w: invalid path segment", errInvalidArgument)
+	}
+
+	res.HostPathValues = append(HostPathValues(nil), args.hostPathValues...)
+	return res, nil
+}
+
+// resolutionRequest returns the request to be resolved.
+func resolutionRequest(method, urlStr string) (*http.Request, error) {
+	if method == "" {
+		return nil, errNoHTTPMethod
+	}
+
+	var u, err = url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var r = &http.Request{
+		Method: strings.ToUpper(method),
+		URL:    u,
+		Host:   u.Host,
+		Header: http.Header{},
+	}
+
+	if u.Scheme == "https" {
+		r.TLS = &tls.ConnectionState{}
+	}
+
+	return r, nil
+}
+
+// --------------------------------------------------
+
+// explainNotFound records the segment that couldn't be matched and the
+// reason the request is not found, when the request is being resolved. The
+// tried function, if it's not nil, returns the templates that were tried to
+// match the segment. The responder is recorded by the handleNotFound
+// function.
+func (args *Args) explainNotFound(
+	segment, reason string,
+	tried func() []string,
+) {
+	if args == nil || args.resolution == nil {
+		return
+	}
+
+	var nf = &ResolvedNotFound{Segment: segment, Reason: reason}
+	if tried != nil {
+		nf.Tried = tried()
+	}
+
+	args.resolution.NotFound = nf
+	args.resolution.reasons = nil
+}
+
+// explainRedirect records the reason of the redirect, when the request is
+// being resolved.
+func (args *Args) explainRedirect(reason string) {
+	if args != nil && args.resolution != nil {
+		args.resolution.reasons = append(args.resolution.reasons, reason)
+	}
+}
+
+// -------------------------
+
+// hostTemplates returns the templates of the router's hosts in their
+// matching order.
+func (ro *Router) hostTemplates() []string {
+	var tmplStrs = make([]string, 0, len(ro.staticHosts)+len(ro.patternHosts))
+	for _, h := range ro.staticHosts {
+		tmplStrs = append(tmplStrs, h.Template().String())
+	}
+
+	sort.Strings(tmplStrs)
+	for _, h := range ro.patternHosts {
+		tmplStrs = append(tmplStrs, h.Template().String())
+	}
+
+	return tmplStrs
+}
+
+// childTemplates returns the templates of the responder's child resources
+// in their matching order.
+func (rb *_ResponderBase) childTemplates() []string {
+	var tmplStrs = make([]string, 0, len(rb.staticResources)+
+		len(rb.patternResources)+1)
+
+	for _, r := range rb.staticResources {
+		tmplStrs = append(tmplStrs, r.Template().String())
+	}
+
+	sort.Strings(tmplStrs)
+	for _, r := range rb.patternResources {
+		tmplStrs = append(tmplStrs, r.Template().String())
+	}
+
+	if rb.wildcardResource != nil {
+		tmplStrs = append(tmplStrs, rb.wildcardResource.Template().String())
+	}
+
+	return tmplStrs
+}
+
+// ownTemplates returns the responder's template in a slice. They are the
+// templates tried when the responder is used directly.
+func (rb *_ResponderBase) ownTemplates() []string {
+	return []string{rb.Template().String()}
+}
@@ -0,0 +1,355 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// --------------------------------------------------
+
+func TestRouter_Resolve(t *testing.T) {
+	var writer = func(body string) Handler {
+		return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			w.Write([]byte(body))
+			return true
+		}
+	}
+
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("GET", "http://example.com", writer("host"))
+	ro.SetURLHandlerFor(
+		"GET PUT",
+		"http://example.com/users/{id:int}",
+		writer("user"),
+	)
+
+	ro.SetURLHandlerFor("GET", "http://example.com/users/new", writer("new"))
+	ro.SetURLHandlerFor(
+		"GET",
+		"http://example.com/files/{path...}",
+		writer("file"),
+	)
+
+	ro.SetURLHandlerFor("GET", "https://example.com/secure", writer("secure"))
+	ro.SetURLHandlerFor("GET", "http://example.com/dir/", writer("dir"))
+	ro.SetURLHandlerFor("GET", "http://{sub}.example.org/", writer("sub"))
+	ro.SetURLHandlerFor("GET", "/root/{lang?=en}/page", writer("page"))
+	ro.SetURLHandlerFor(
+		"GET",
+		"http://opt.example.com/{lang?=en}",
+		writer("lang"),
+	)
+
+	ro.SetURLHandlerFor("GET", "/", writer("root"))
+
+	ro.ResourceUsingConfig(
+		"http://example.com/Docs",
+		Config{CaseInsensitive: true, RedirectsToCanonicalCase: true},
+	).SetHandlerFor("GET", writer("docs"))
+
+	ro.SetConfigurationAt(
+		"https://example.com/secure",
+		Config{RedirectsInsecureRequest: true},
+	)
+
+	ro.SetConfigurationAt(
+		"http://example.com/tree",
+		Config{SubtreeHandler: true},
+	)
+
+	ro.SetURLHandlerFor("GET", "http://example.com/tree", writer("tree"))
+	ro.SetURLHandlerFor("GET", "http://example.com/tree/leaf", writer("leaf"))
+
+	ro.RedirectAnyRequestAt(
+		"http://example.com/old",
+		"http://example.com/new",
+		http.StatusPermanentRedirect,
+	)
+
+	// The resolved handler is the request handler with its middlewares.
+	ro.WrapAllRequestHandlers(func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			var handled = next(w, r, args)
+			w.Write([]byte("-mw"))
+			return handled
+		}
+	})
+
+	var cases = []struct {
+		method, url      string
+		wantSteps        []string
+		wantStatus       int
+		wantValues       map[string]string
+		wantReasons      []string
+		wantNotFoundSeg  string
+		wantNotFoundWhy  string
+		wantTriedContain string
+	}{
+		{
+			method:     "GET",
+			url:        "http://example.com",
+			wantSteps:  []string{"example.com"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			method:     "GET",
+			url:        "http://example.com/users/12",
+			wantSteps:  []string{"example.com", "users", "12"},
+			wantStatus: http.StatusOK,
+			wantValues: map[string]string{"id": "12"},
+		},
+		{
+			method:     "GET",
+			url:        "http://example.com/users/new",
+			wantSteps:  []string{"example.com", "users", "new"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			method:     "DELETE",
+			url:        "http://example.com/users/12",
+			wantSteps:  []string{"example.com", "users", "12"},
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			method:     "GET",
+			url:        "http://example.com/files/a/b",
+			wantSteps:  []string{"example.com", "files", "a/b"},
+			wantStatus: http.StatusOK,
+			wantValues: map[string]string{"path": "a/b"},
+		},
+		{
+			method:      "GET",
+			url:         "http://example.com/secure",
+			wantSteps:   []string{"example.com", "secure"},
+			wantStatus:  http.StatusPermanentRedirect,
+			wantReasons: []string{reasonInsecureRequest},
+		},
+		{
+			method:     "GET",
+			url:        "https://example.com/secure",
+			wantSteps:  []string{"example.com", "secure"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			method:      "GET",
+			url:         "http://example.com/dir",
+			wantSteps:   []string{"example.com", "dir"},
+			wantStatus:  http.StatusPermanentRedirect,
+			wantReasons: []string{reasonTrailingSlash},
+		},
+		{
+			method:      "GET",
+			url:         "http://example.com/users/../dir/",
+			wantSteps:   []string{"example.com", "dir"},
+			wantStatus:  http.StatusPermanentRedirect,
+			wantReasons: []string{reasonUncleanPath},
+		},
+		{
+			method:      "GET",
+			url:         "http://example.com/docs",
+			wantSteps:   []string{"example.com", "docs"},
+			wantStatus:  http.StatusPermanentRedirect,
+			wantReasons: []string{reasonCanonicalCase},
+		},
+		{
+			method:      "GET",
+			url:         "http://example.com/old/a",
+			wantSteps:   []string{"example.com", "old"},
+			wantStatus:  http.StatusPermanentRedirect,
+			wantReasons: []string{reasonRedirect},
+		},
+		{
+			method:     "GET",
+			url:        "http://example.com/tree/leaf",
+			wantSteps:  []string{"example.com", "tree", "leaf"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			method:     "GET",
+			url:        "http://example.com/tree/a/b",
+			wantSteps:  []string{"example.com", "tree"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			method:           "GET",
+			url:              "http://example.com/users/abc",
+			wantSteps:        []string{"example.com", "users"},
+			wantStatus:       http.StatusNotFound,
+			wantNotFoundSeg:  "abc",
+			wantNotFoundWhy:  reasonNoMatchingResource,
+			wantTriedContain: "new",
+		},
+		{
+			method:     "GET",
+			url:        "http://a.example.org/",
+			wantSteps:  []string{"a.example.org"},
+			wantStatus: http.StatusOK,
+			wantValues: map[string]string{"sub": "a"},
+		},
+		{
+			method:     "GET",
+			url:        "http://localhost/root/page",
+			wantSteps:  []string{"/", "root", "", "page"},
+			wantStatus: http.StatusOK,
+			wantValues: map[string]string{"lang": "en"},
+		},
+		{
+			method:           "GET",
+			url:              "http://localhost/unknown",
+			wantSteps:        []string{"/"},
+			wantStatus:       http.StatusNotFound,
+			wantNotFoundSeg:  "unknown",
+			wantNotFoundWhy:  reasonNoMatchingResource,
+			wantTriedContain: "root",
+		},
+		{
+			method:     "GET",
+			url:        "http://opt.example.com",
+			wantSteps:  []string{"opt.example.com", ""},
+			wantStatus: http.StatusOK,
+			wantValues: map[string]string{"lang": "en"},
+		},
+		{
+			method:     "HEAD",
+			url:        "http://example.com/users/new",
+			wantSteps:  []string{"example.com", "users", "new"},
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.method+" "+c.url, func(t *testing.T) {
+			var res, err = ro.Resolve(c.method, c.url)
+			checkErr(t, err, false)
+
+			var segments []string
+			for _, s := range res.Steps {
+				segments = append(segments, s.Segment)
+			}
+
+			checkValue(t, segments, c.wantSteps)
+			for k, v := range c.wantValues {
+				checkValue(t, res.HostPathValues.Get(k), v)
+			}
+
+			// The resolution must agree with the served request.
+			var w = httptest.NewRecorder()
+			ro.ServeHTTP(w, httptest.NewRequest(c.method, c.url, nil))
+			checkValue(t, w.Code, c.wantStatus)
+
+			switch c.wantStatus {
+			case http.StatusOK:
+				checkValue(t, res.Redirect, (*ResolvedRedirect)(nil))
+				checkValue(t, res.NotFound, (*ResolvedNotFound)(nil))
+				checkValue(t, res.MethodNotAllowed, false)
+
+				var rw = httptest.NewRecorder()
+				res.Handler(rw, httptest.NewRequest(c.method, c.url, nil), nil)
+				checkValue(t, rw.Body.String(), w.Body.String())
+				if !strings.HasSuffix(rw.Body.String(), "-mw") {
+					t.Fatalf("Resolve: handler without middleware")
+				}
+			case http.StatusMethodNotAllowed:
+				checkValue(t, res.MethodNotAllowed, true)
+				if res.Handler == nil {
+					t.Fatalf("Resolve: missing not allowed method handler")
+				}
+			case http.StatusPermanentRedirect:
+				if res.Redirect == nil {
+					t.Fatalf("Resolve: missing redirect")
+				}
+
+				checkValue(t, res.Redirect.Code, w.Code)
+				checkValue(t, res.Redirect.URL, w.Header().Get("Location"))
+				checkValue(t, res.Redirect.Reasons, c.wantReasons)
+			case http.StatusNotFound:
+				if res.NotFound == nil {
+					t.Fatalf("Resolve: missing not found")
+				}
+
+				checkValue(t, res.NotFound.Segment, c.wantNotFoundSeg)
+				checkValue(t, res.NotFound.Reason, c.wantNotFoundWhy)
+				var tried = strings.Join(res.NotFound.Tried, " ")
+				if !strings.Contains(tried, c.wantTriedContain) {
+					t.Fatalf("Resolve: tried = %v", res.NotFound.Tried)
+				}
+			}
+
+			if res.String() == "" {
+				t.Fatalf("Resolve: empty explanation")
+			}
+		})
+	}
+
+	var res, err = ro.Resolve("GET", "http://example.net/a")
+	checkErr(t, err, false)
+	checkValue(t, res.NotFound.Reason, reasonNoMatchingResource)
+
+	ro = NewRouter()
+	ro.SetURLHandlerFor("GET", "http://example.com/a", writer("a"))
+	res, err = ro.Resolve("GET", "http://example.net/a")
+	checkErr(t, err, false)
+	checkValue(t, res.NotFound.Reason, reasonNoMatchingHost)
+	checkValue(t, res.NotFound.Segment, "example.net")
+	checkValue(t, res.NotFound.Tried, []string{"example.com"})
+	if !strings.Contains(res.String(), `segment "example.net"`) {
+		t.Fatalf("Resolve: explanation = %s", res.String())
+	}
+
+	_, err = ro.Resolve("", "http://example.com/a")
+	checkErr(t, err, true)
+
+	_, err = ro.Resolve("GET", "http://example.com/%zz")
+	checkErr(t, err, true)
+
+	// The resolution reports the responders registered in the router after
+	// an update.
+	err = ro.Update(func(tx *RouterTx) error { return nil })
+	checkErr(t, err, false)
+	res, err = ro.Resolve("GET", "http://example.com/a")
+	checkErr(t, err, false)
+	checkValue(t, res.Resource, ro.RegisteredResource("http://example.com/a"))
+	checkValue(t, res.Host, ro.RegisteredHost("http://example.com"))
+}
+
+func TestHostAndResource_Resolve(t *testing.T) {
+	var h = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		return true
+	}
+
+	var host = NewDormantHost("{sub}.example.com")
+	host.SetPathHandlerFor("GET", "/a/{b}", h)
+
+	var res, err = host.Resolve("GET", "http://x.example.com/a/c")
+	checkErr(t, err, false)
+	checkValue(t, len(res.Steps), 3)
+	checkValue(t, res.HandlerMethod, "GET")
+	checkValue(t, res.HostPathValues.Get("sub"), "x")
+	checkValue(t, res.HostPathValues.Get("b"), "c")
+	if res.Resource == nil || res.Host != host {
+		t.Fatalf("Host.Resolve: responder = %v, %v", res.Host, res.Resource)
+	}
+
+	res, err = host.Resolve("GET", "http://example.org/a/c")
+	checkErr(t, err, false)
+	checkValue(t, res.NotFound.Reason, reasonNoMatchingHost)
+
+	var r = NewDormantResource("{name}")
+	r.SetHandlerFor("POST", h)
+
+	res, err = r.Resolve("POST", "http://example.com/x")
+	checkErr(t, err, false)
+	checkValue(t, res.Resource, r)
+	checkValue(t, res.HandlerMethod, "POST")
+	checkValue(t, res.HostPathValues.Get("name"), "x")
+
+	res, err = r.Resolve("POST", "http://example.com/x/y")
+	checkErr(t, err, false)
+	checkValue(t, res.NotFound.Segment, "y")
+	checkValue(t, res.NotFound.Reason, reasonNoMatchingResource)
+}
@@ -5,7 +5,6 @@ package nanomux
 
 import (
 	"net/http"
-	"net/url"
 )
 
 // --------------------------------------------------
@@ -19,7 +18,7 @@ type Resource struct {
 // createDormantResource creates a dormant, not configured resource.
 func createDormantResource(tmpl *Template) (*Resource, error) {
 	if tmpl == nil {
-		return nil, newErr("%w", errNilArgument)
+		return nil, newErr("%w", ErrNilArgument)
 	}
 
 	var r = &Resource{}
@@ -46,7 +45,12 @@ func createResource(
 	if rTmplStr == "/" {
 		if hTmplStr != "" {
 			// Unreachable.
-			return nil, newErr("%w", errNonRouterParent)
+			return nil, newRegistrationErr(
+				"register",
+				tmplStr,
+				nil,
+				ErrNonRouterParent,
+			)
 		}
 
 		tmpl = rootTmpl
@@ -64,7 +68,12 @@ func createResource(
 	if config != nil {
 		config.Secure, config.HasTrailingSlash = secure, tslash
 		if config.RedirectsInsecureRequest && !secure {
-			return nil, newErr("%w", errConflictingSecurity)
+			return nil, newRegistrationErr(
+				"configure",
+				tmplStr,
+				nil,
+				ErrConflictingSecurity,
+			)
 		}
 
 		if tmpl == rootTmpl {
@@ -130,7 +139,7 @@ func createResource(
 // that come below the registering resource, they show where in the tree
 // the resource must be placed under the registering resource.
 func NewDormantResource(urlTmplStr string) *Resource {
-	var r, err = createResource(urlTmplStr, nil, nil)
+	var r, err = TryToNewDormantResource(urlTmplStr)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
@@ -138,6 +147,17 @@ func NewDormantResource(urlTmplStr string) *Resource {
 	return r
 }
 
+// TryToNewDormantResource is like NewDormantResource but returns an error
+// instead of panicking.
+func TryToNewDormantResource(urlTmplStr string) (*Resource, error) {
+	var r, err = createResource(urlTmplStr, nil, nil)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return r, nil
+}
+
 // NewDormantResourceUsingConfig returns a new dormant resource (without HTTP
 // method handlers).
 //
@@ -159,7 +179,7 @@ func NewDormantResource(urlTmplStr string) *Resource {
 // that come below the registering resource, they show where in the tree
 // the resource must be placed under the registering resource.
 func NewDormantResourceUsingConfig(urlTmplStr string, config Config) *Resource {
-	var r, err = createResource(urlTmplStr, nil, &config)
+	var r, err = TryToNewDormantResourceUsingConfig(urlTmplStr, config)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
@@ -167,6 +187,20 @@ func NewDormantResourceUsingConfig(urlTmplStr string, config Config) *Resource {
 	return r
 }
 
+// TryToNewDormantResourceUsingConfig is like NewDormantResourceUsingConfig but
+// returns an error instead of panicking.
+func TryToNewDormantResourceUsingConfig(
+	urlTmplStr string,
+	config Config,
+) (*Resource, error) {
+	var r, err = createResource(urlTmplStr, nil, &config)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return r, nil
+}
+
 // NewResource returns a new resource.
 //
 // The argument URL template's scheme and trailing slash property values
@@ -212,16 +246,27 @@ func NewDormantResourceUsingConfig(urlTmplStr string, config Config) *Resource {
 // that come below the registering resource, they show where in the tree
 // the resource must be placed under the registering resource.
 func NewResource(urlTmplStr string, impl Impl) *Resource {
+	var r, err = TryToNewResource(urlTmplStr, impl)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return r
+}
+
+// TryToNewResource is like NewResource but returns an error instead of
+// panicking.
+func TryToNewResource(urlTmplStr string, impl Impl) (*Resource, error) {
 	if impl == nil {
-		panicWithErr("%w", errNilArgument)
+		return nil, newErr("%w", ErrNilArgument)
 	}
 
 	var r, err = createResource(urlTmplStr, impl, nil)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
-	return r
+	return r, nil
 }
 
 // NewResourceUsingConfig returns a new resource.
@@ -276,16 +321,31 @@ func NewResourceUsingConfig(
 	impl Impl,
 	config Config,
 ) *Resource {
+	var r, err = TryToNewResourceUsingConfig(urlTmplStr, impl, config)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return r
+}
+
+// TryToNewResourceUsingConfig is like NewResourceUsingConfig but returns an
+// error instead of panicking.
+func TryToNewResourceUsingConfig(
+	urlTmplStr string,
+	impl Impl,
+	config Config,
+) (*Resource, error) {
 	if impl == nil {
-		panicWithErr("%w", errNilArgument)
+		return nil, newErr("%w", ErrNilArgument)
 	}
 
 	var r, err = createResource(urlTmplStr, impl, &config)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
-	return r
+	return r, nil
 }
 
 // newDormantResource creates a dormant instance of the Resource from the tmpl.
@@ -349,43 +409,56 @@ func (rb *Resource) isRoot() bool {
 // It is called when the resource is used directly.
 func (rb *Resource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var args = getArgs(r.URL, rb.derived)
+	rb.serve(w, r, args)
+	putArgsInThePool(args)
+}
+
+// serve passes the request to the resource's request receiver if the
+// resource's template matches the first path segment of the request's URL.
+// Otherwise, the not-found handler is called. It's used by the ServeHTTP
+// and Resolve methods.
+func (rb *Resource) serve(w http.ResponseWriter, r *http.Request, args *Args) {
 	args.nextPathSegment() // First call returns '/'.
 	if rb.tmpl == rootTmpl {
+		args.matchedResponder(r, "/")
 		if !rb.requestReceiver(w, r, args) {
-			notFoundResourceHandler(w, r, args)
+			handleNotFound(rb.derived, w, r, args)
 		}
 
-		putArgsInThePool(args)
 		return
 	}
 
+	var currentPathSegmentIdx = args.currentPathSegmentIdx
 	var ps, err = args.nextPathSegment()
-	if err != nil {
-		http.Error(
-			w,
-			http.StatusText(http.StatusBadRequest),
-			http.StatusBadRequest,
-		)
+	if err == nil && len(ps) > 0 && rb.tmpl.IsCatchAll() {
+		ps, err = args.pathSegmentsFrom(currentPathSegmentIdx)
+	}
 
-		putArgsInThePool(args)
+	if err != nil {
+		handleBadRequest(w, r, args, err)
 		return
 	}
 
 	if len(ps) > 0 {
 		var matched bool
-		matched, args.hostPathValues = rb.tmpl.Match(ps, args.hostPathValues)
+		matched, args.hostPathValues = rb.tmpl.match(
+			ps,
+			args.hostPathValues,
+			rb.IsCaseInsensitive(),
+		)
+
 		if matched {
+			args.matchedResponder(r, ps)
 			if !rb.requestReceiver(w, r, args) {
-				notFoundResourceHandler(w, r, args)
+				handleNotFound(rb.derived, w, r, args)
 			}
 
-			putArgsInThePool(args)
 			return
 		}
 	}
 
-	notFoundResourceHandler(w, r, args)
-	putArgsInThePool(args)
+	args.explainNotFound(ps, reasonNoMatchingResource, rb.ownTemplates)
+	handleNotFound(rb.derived, w, r, args)
 }
 
 // handleOrPassRequest handles the request if the resource corresponds to the
@@ -413,7 +486,7 @@ func (rb *Resource) handleOrPassRequest(
 		// If there is no resource in the tree below that matches the
 		// request's path, this resource handles the request.
 		// subtreeExists indicates this to the resources below in the tree,
-		// so the notFoundResourceHandler is not called.
+		// so the not-found handler is not called.
 		args.subtreeExists = true
 	}
 
@@ -421,7 +494,7 @@ func (rb *Resource) handleOrPassRequest(
 	if !args.reachedTheLastPathSegment() {
 		lastSegment = false
 
-		if rb.requestPasser(w, r, args) {
+		if rb.requestPasserOf(args)(w, r, args) {
 			return true
 		}
 
@@ -433,78 +506,34 @@ func (rb *Resource) handleOrPassRequest(
 	}
 
 	if !rb.canHandleRequest() && rb.requestRedirector == nil {
+		// When the request's URL ends at the resource, the trailing optional
+		// child resource handles the request with its default value.
+		if lastSegment {
+			if rb.passRequestToOptionalResource(w, r, args) {
+				return true
+			}
+
+			args._r = rb.derived
+		}
+
 		// If rb is a subtree handler that cannot handle a request, this
 		// prevents other subtree handlers above the tree from handling
 		// the request.
-		return notFoundResourceHandler(w, r, args)
+		args.explainNotFound("", reasonNoHandler, nil)
+		return handleNotFound(rb.derived, w, r, args)
 	}
 
-	var newURL *url.URL
-	if r.TLS == nil && rb.IsSecure() {
-		if !rb.RedirectsInsecureRequest() {
-			return notFoundResourceHandler(w, r, args)
-		}
-
-		newURL = cloneRequestURL(r)
-		newURL.Scheme = "https"
-	}
-
-	if args.cleanPath && !rb.IsLenientOnUncleanPath() {
-		if newURL == nil {
-			newURL = cloneRequestURL(r)
-		}
-
-		newURL.Path = args.path
+	var newURL, ok = rb.commonRedirectURL(r, args)
+	if !ok {
+		return handleNotFound(rb.derived, w, r, args)
 	}
 
 	if lastSegment && !rb.IsLenientOnTrailingSlash() {
-		if rb.HasTrailingSlash() && !args.pathHasTrailingSlash() {
-			if rb.IsStrictOnTrailingSlash() {
-				return notFoundResourceHandler(w, r, args)
-			}
-
-			if newURL == nil {
-				newURL = cloneRequestURL(r)
-			}
-
-			newURL.Path += "/"
-		} else if !rb.HasTrailingSlash() && args.pathHasTrailingSlash() {
-			if rb.IsStrictOnTrailingSlash() {
-				return notFoundResourceHandler(w, r, args)
-			}
-
-			if newURL == nil {
-				newURL = cloneRequestURL(r)
-			}
-
-			newURL.Path = newURL.Path[:len(newURL.Path)-1]
+		newURL, ok = rb.trailingSlashRedirectURL(newURL, r, args)
+		if !ok {
+			return handleNotFound(rb.derived, w, r, args)
 		}
 	}
 
-	if newURL != nil {
-		if newURL.Scheme == "" {
-			if r.TLS == nil {
-				newURL.Scheme = "http"
-			} else {
-				newURL.Scheme = "https"
-			}
-		}
-
-		var prc = permanentRedirectCode
-		if rb.permanentRedirectCode > 0 {
-			prc = rb.permanentRedirectCode
-		}
-
-		if rb.redirectHandler != nil {
-			return rb.redirectHandler(w, r, newURL.String(), prc, args)
-		}
-
-		return commonRedirectHandler(w, r, newURL.String(), prc, args)
-	}
-
-	if rb.requestRedirector != nil {
-		return rb.requestRedirector(w, r, args)
-	}
-
-	return rb.requestHandler(w, r, args)
+	return rb.redirectOrHandle(newURL, w, r, args)
 }
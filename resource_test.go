@@ -140,6 +140,21 @@ func TestConfig_asFlags(t *testing.T) {
 			Config{SubtreeHandler: true, HandlesThePathAsIs: true},
 			flagSubtreeHandler | flagLenientOnTrailingSlash | flagLenientOnUncleanPath,
 		},
+		{
+			"config 3",
+			Config{CaseInsensitive: true, RedirectsToCanonicalCase: true},
+			flagCaseInsensitive | flagRedirectsToCanonicalCase,
+		},
+		{
+			"config 4",
+			Config{RedirectsToCanonicalCase: true},
+			flagCaseInsensitive | flagRedirectsToCanonicalCase,
+		},
+		{
+			"config 5",
+			Config{HandlesHeadWithGet: true},
+			flagHandlesHeadWithGet,
+		},
 	}
 
 	for _, c := range cases {
@@ -699,6 +714,110 @@ func TestResourceBase_HandlesThePathAsIs(t *testing.T) {
 	}
 }
 
+func TestResourceBase_IsCaseInsensitive(t *testing.T) {
+	var (
+		r1 = NewDormantResourceUsingConfig("r1", Config{
+			CaseInsensitive: true,
+		})
+
+		r2 = NewDormantResourceUsingConfig("r2", Config{
+			RedirectsToCanonicalCase: true,
+		})
+
+		r3 = NewDormantResource("r3")
+	)
+
+	checkValue(t, r1.IsCaseInsensitive(), true)
+	checkValue(t, r1.RedirectsToCanonicalCase(), false)
+	checkValue(t, r2.IsCaseInsensitive(), true)
+	checkValue(t, r2.RedirectsToCanonicalCase(), true)
+	checkValue(t, r3.IsCaseInsensitive(), false)
+	checkValue(t, r3.RedirectsToCanonicalCase(), false)
+	checkValue(
+		t,
+		r2.Configuration(),
+		Config{CaseInsensitive: true, RedirectsToCanonicalCase: true},
+	)
+}
+
+func TestResourceBase_HandlesHeadWithGet(t *testing.T) {
+	var r = NewDormantResourceUsingConfig("r", Config{
+		HandlesHeadWithGet: true,
+	})
+
+	checkValue(t, r.HandlesHeadWithGet(), true)
+	checkValue(t, r.AllowedHTTPMethods() == nil, true)
+
+	r.SetHandlerFor(
+		"GET",
+		func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			w.Header().Set("X-Handler", "get")
+			w.Write([]byte("Hello, World!"))
+			return true
+		},
+	)
+
+	r.SetHandlerFor(
+		"PUT",
+		func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			return true
+		},
+	)
+
+	checkValue(t, r.AllowedHTTPMethods(), []string{"GET", "HEAD", "OPTIONS", "PUT"})
+
+	var w = httptest.NewRecorder()
+	var req = httptest.NewRequest("HEAD", "http://example.com/r", nil)
+	r.ServeHTTP(w, req)
+	checkValue(t, w.Code, http.StatusOK)
+	checkValue(t, w.Header().Get("X-Handler"), "get")
+	checkValue(t, w.Header().Get("Content-Length"), "13")
+	checkValue(t, w.Header().Get("Content-Type"), "text/plain; charset=utf-8")
+	checkValue(t, w.Body.Len(), 0)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("OPTIONS", "http://example.com/r", nil)
+	r.ServeHTTP(w, req)
+	checkValue(t, w.Header().Get("Allow"), "GET, HEAD, OPTIONS, PUT")
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "http://example.com/r", nil)
+	r.ServeHTTP(w, req)
+	checkValue(t, w.Code, http.StatusMethodNotAllowed)
+	checkValue(t, w.Header().Get("Allow"), "GET, HEAD, OPTIONS, PUT")
+
+	r.SetHandlerFor(
+		"HEAD",
+		func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			w.Header().Set("X-Handler", "head")
+			return true
+		},
+	)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("HEAD", "http://example.com/r", nil)
+	r.ServeHTTP(w, req)
+	checkValue(t, w.Header().Get("X-Handler"), "head")
+	checkValue(t, w.Header().Get("Content-Length"), "")
+
+	r = NewDormantResource("r")
+	r.SetImplementation(&_TestImplWithHandlers{})
+	checkValue(t, r.AllowedHTTPMethods(), []string{"GET", "OPTIONS", "POST"})
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("HEAD", "http://example.com/r", nil)
+	r.ServeHTTP(w, req)
+	checkValue(t, w.Code, http.StatusMethodNotAllowed)
+
+	r.SetConfiguration(Config{HandlesHeadWithGet: true})
+	checkValue(t, r.AllowedHTTPMethods(), []string{"GET", "HEAD", "OPTIONS", "POST"})
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("HEAD", "http://example.com/r", nil)
+	r.ServeHTTP(w, req)
+	checkValue(t, w.Code, http.StatusOK)
+}
+
 func TestResourceBase_canHandleRequest(t *testing.T) {
 	var r = NewDormantResource("index")
 	if r.canHandleRequest() {
@@ -2871,6 +2990,109 @@ func TestResourceBase_RegisteredResource(t *testing.T) {
 	}
 }
 
+func TestResourceBase_unregisterResource(t *testing.T) {
+	var (
+		root      = NewDormantResource("/")
+		static    = root.Resource("static")
+		pattern1  = root.Resource("{patternR1:pattern1}")
+		pattern2  = root.Resource("{patternR2:pattern2}")
+		pattern3  = root.Resource("{patternR3:pattern3}")
+		wildcard  = root.Resource("{wildcard}")
+		child     = wildcard.Resource("child")
+		unrelated = NewDormantResource("unrelated")
+	)
+
+	checkErr(t, root.unregisterResource(static), false)
+	checkValue(t, root.staticResources, map[string]*Resource(nil))
+	checkValue(t, static.parent(), nil)
+
+	checkErr(t, root.unregisterResource(pattern2), false)
+	checkValue(t, root.patternResources, []*Resource{pattern1, pattern3})
+	checkValue(t, pattern2.parent(), nil)
+
+	checkErr(t, root.unregisterResource(pattern1), false)
+	checkErr(t, root.unregisterResource(pattern3), false)
+	checkValue(t, root.patternResources, []*Resource(nil))
+
+	checkErr(t, root.unregisterResource(wildcard), false)
+	checkValue(t, root.wildcardResource, (*Resource)(nil))
+	checkValue(t, wildcard.parent(), nil)
+	checkValue(t, child.parent(), _Parent(wildcard))
+
+	checkErr(t, root.unregisterResource(static), true)
+	checkErr(t, root.unregisterResource(child), true)
+	checkErr(t, root.unregisterResource(unrelated), true)
+}
+
+func TestResourceBase_UnregisterResource(t *testing.T) {
+	var (
+		root       = NewDormantResource("/")
+		static     = root.Resource("static")
+		grandChild = root.Resource("static/{child}/grandChild")
+		other      = NewDormantResource("other")
+	)
+
+	testPanicker(t, false, func() { root.UnregisterResource(grandChild) })
+	checkValue(t, grandChild.parent(), nil)
+	checkValue(
+		t,
+		root.RegisteredResource("static/{child}/grandChild"),
+		(*Resource)(nil),
+	)
+
+	testPanicker(t, false, func() { root.UnregisterResource(static) })
+	checkValue(t, root.HasAnyChildResources(), false)
+
+	testPanicker(t, true, func() { root.UnregisterResource(static) })
+	testPanicker(t, true, func() { root.UnregisterResource(other) })
+	testPanicker(t, true, func() { root.UnregisterResource(nil) })
+
+	testPanicker(t, false, func() { root.RegisterResource(static) })
+	checkValue(t, static.parent(), _Parent(root))
+}
+
+func TestResourceBase_UnregisterResourceAt(t *testing.T) {
+	var (
+		root    = NewDormantResource("/")
+		r0      = root.Resource("$r0:r0")
+		r00     = root.Resource("$r0:r0/{r00}/")
+		r000    = root.Resource("$r0:r0/{r00}/$r000:r000")
+		secure  = root.Resource("https:///secure")
+		pattern = root.Resource("{pattern:\\d+}")
+	)
+
+	testPanickerValue(t, false, r000, func() interface{} {
+		return root.UnregisterResourceAt("$r0/{r00}/$r000")
+	})
+
+	checkValue(t, r000.parent(), nil)
+	checkValue(t, r00.HasAnyChildResources(), false)
+
+	testPanickerValue(t, false, r00, func() interface{} {
+		return root.UnregisterResourceAt("$r0/{r00}/")
+	})
+
+	checkValue(t, r0.HasAnyChildResources(), false)
+
+	testPanickerValue(t, false, pattern, func() interface{} {
+		return root.UnregisterResourceAt("{pattern:\\d+}")
+	})
+
+	testPanicker(t, true, func() { root.UnregisterResourceAt("secure") })
+	testPanickerValue(t, false, secure, func() interface{} {
+		return root.UnregisterResourceAt("https:///secure")
+	})
+
+	testPanicker(t, true, func() { root.UnregisterResourceAt("$r0/{r00}") })
+	testPanicker(t, true, func() { root.UnregisterResourceAt("nonExistent") })
+	testPanicker(t, true, func() { root.UnregisterResourceAt("/") })
+	testPanicker(t, true, func() {
+		root.UnregisterResourceAt("http://example.com/r0")
+	})
+
+	checkValue(t, root.ChildResources(), []*Resource{r0})
+}
+
 func TestResourceBase_ChildResourceNamed(t *testing.T) {
 	var parent = NewDormantResource("resource")
 
@@ -4384,6 +4606,118 @@ func TestResourceBase_RedirectAnyRequestTo(t *testing.T) {
 	)
 }
 
+func TestResourceBase_SetHandlerForNotFound(t *testing.T) {
+	var ro = NewRouter()
+	var host = ro.Host("example.com")
+	host.SetHandlerFor("get", textHandler("host"))
+
+	var api = host.Resource("api")
+	api.SetPathHandlerFor("get", "users", textHandler("users"))
+	host.SetPathHandlerFor("get", "page", textHandler("page"))
+
+	testPanicker(t, true, func() { api.SetHandlerForNotFound(nil) })
+	testPanicker(t, false, func() {
+		api.SetHandlerForNotFound(textHandler("api not found"))
+	})
+
+	var w = serve(ro, "GET", "http://example.com/api/users")
+	checkValue(t, w.Body.String(), "users")
+
+	w = serve(ro, "GET", "http://example.com/api/posts")
+	checkValue(t, w.Body.String(), "api not found")
+
+	w = serve(ro, "GET", "http://example.com/api/users/1")
+	checkValue(t, w.Body.String(), "api not found")
+
+	w = serve(ro, "GET", "http://example.com/api")
+	checkValue(t, w.Body.String(), "api not found")
+
+	w = serve(ro, "GET", "http://example.com/posts")
+	checkValue(t, w.Body.String() != "api not found", true)
+
+	ro.SetHandlerForNotFound(textHandler("router not found"))
+	w = serve(ro, "GET", "http://example.com/posts")
+	checkValue(t, w.Body.String(), "router not found")
+
+	w = serve(ro, "GET", "http://example.com/api/posts")
+	checkValue(t, w.Body.String(), "api not found")
+
+	host.SetHandlerForNotFound(textHandler("host not found"))
+	w = serve(ro, "GET", "http://example.com/page/1")
+	checkValue(t, w.Body.String(), "host not found")
+
+	w = serve(ro, "GET", "http://other.com/page")
+	checkValue(t, w.Body.String(), "router not found")
+
+	// Standalone resource.
+	var r = NewDormantResource("r")
+	r.SetHandlerForNotFound(textHandler("r not found"))
+
+	var rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/r/a", nil))
+	checkValue(t, rec.Body.String(), "r not found")
+}
+
+func TestResourceBase_HandlerOfNotFound(t *testing.T) {
+	var ro = NewRouter()
+	var r = ro.Resource("/a/b")
+	var a = r.Parent()
+
+	var call = func(h Handler) string {
+		var w = httptest.NewRecorder()
+		h(w, httptest.NewRequest("GET", "/", nil), nil)
+		return w.Body.String()
+	}
+
+	checkValue(t, call(r.HandlerOfNotFound()), call(HandlerOfNotFound()))
+
+	// The returned handler marks the request as not found.
+	var args = &Args{}
+	r.HandlerOfNotFound()(
+		httptest.NewRecorder(),
+		httptest.NewRequest("GET", "/", nil),
+		args,
+	)
+
+	checkValue(t, args.NotFound(), true)
+
+	ro.SetHandlerForNotFound(textHandler("router"))
+	checkValue(t, call(r.HandlerOfNotFound()), "router")
+	checkValue(t, call(ro.HandlerOfNotFound()), "router")
+
+	a.SetHandlerForNotFound(textHandler("a"))
+	checkValue(t, call(r.HandlerOfNotFound()), "a")
+
+	r.SetHandlerForNotFound(textHandler("r"))
+	checkValue(t, call(r.HandlerOfNotFound()), "r")
+	checkValue(t, call(a.HandlerOfNotFound()), "a")
+	checkValue(t, call(a.Parent().HandlerOfNotFound()), "router")
+}
+
+func TestResourceBase_WrapHandlerOfNotFound(t *testing.T) {
+	var ro = NewRouter()
+	var host = ro.Host("example.com")
+	var api = host.Resource("api")
+	api.SetHandlerFor("get", textHandler("api"))
+
+	testPanicker(t, true, func() { api.WrapHandlerOfNotFound() })
+	testPanicker(t, true, func() { api.WrapHandlerOfNotFound(nil) })
+
+	host.SetHandlerForNotFound(textHandler("not found"))
+	testPanicker(t, false, func() {
+		api.WrapHandlerOfNotFound(
+			textMiddleware("mw1-"),
+			textMiddleware("mw2-"),
+		)
+	})
+
+	var w = serve(ro, "GET", "http://example.com/api/x")
+	checkValue(t, w.Body.String(), "mw2-mw1-not found")
+
+	w = serve(ro, "GET", "http://example.com/x")
+	checkValue(t, w.Body.String(), "not found")
+}
+
 func TestResourceBase_SetGetSharedDataAt(t *testing.T) {
 	var root = NewDormantResource("/")
 	var r00 = root.Resource("r00")
@@ -5243,7 +5577,7 @@ func TestResourceBase_SetSharedDataForSubtree(t *testing.T) {
 	{
 		var r = root.RegisteredResource("https:///{r01}/r12")
 		if r == nil {
-			t.Fatal(errNonExistentResource)
+			t.Fatal(ErrNonExistentResource)
 		}
 
 		var retrievedData = r.SharedData()
@@ -5260,7 +5594,7 @@ func TestResourceBase_SetSharedDataForSubtree(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			var r = root.RegisteredResource(c.path)
 			if r == nil {
-				t.Fatal(errNonExistentResource)
+				t.Fatal(ErrNonExistentResource)
 			}
 
 			if r.SharedData() != data {
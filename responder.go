@@ -31,7 +31,7 @@ type _Responder interface {
 	setConfigFlags(flag _ConfigFlags)
 	resetConfigFlags(cfs _ConfigFlags)
 	configFlags() _ConfigFlags
-	configure(secure, tslash bool, cfs *_ConfigFlags)
+	configure(secure, tslash bool, cfs *_ConfigFlags) error
 	checkForConfigCompatibility(secure, tslash bool, cfs *_ConfigFlags) error
 
 	// -------------------------
@@ -44,6 +44,9 @@ type _Responder interface {
 	IsLenientOnTrailingSlash() bool
 	IsLenientOnUncleanPath() bool
 	HandlesThePathAsIs() bool
+	IsCaseInsensitive() bool
+	RedirectsToCanonicalCase() bool
+	HandlesHeadWithGet() bool
 
 	// -------------------------
 
@@ -60,6 +63,7 @@ type _Responder interface {
 	resourceWithTemplate(tmpl *Template) (*Resource, error)
 	registeredResource(pathTmplStr string) (r *Resource, tslash bool, err error)
 	passChildResourcesTo(r _Responder) error
+	unregisterResource(r *Resource) error
 	registerResource(r *Resource) error
 	segmentResources(pathSegments []string) (
 		oldLast _Responder,
@@ -80,10 +84,19 @@ type _Responder interface {
 	// -------------------------
 
 	Resource(pathTmplStr string) *Resource
+	TryToResource(pathTmplStr string) (*Resource, error)
 	ResourceUsingConfig(pathTmplStr string, config Config) *Resource
+	TryToResourceUsingConfig(pathTmplStr string, config Config) (*Resource, error)
 	RegisterResource(r *Resource)
+	TryToRegisterResource(r *Resource) error
 	RegisterResourceUnder(prefixPath string, r *Resource)
+	TryToRegisterResourceUnder(prefixPath string, r *Resource) error
 	RegisteredResource(pathTmplStr string) *Resource
+	TryToRegisteredResource(pathTmplStr string) (*Resource, error)
+	UnregisterResource(r *Resource)
+	TryToUnregisterResource(r *Resource) error
+	UnregisterResourceAt(pathTmplStr string) *Resource
+	TryToUnregisterResourceAt(pathTmplStr string) (*Resource, error)
 
 	ChildResourceNamed(name string) *Resource
 	ChildResources() []*Resource
@@ -97,65 +110,134 @@ type _Responder interface {
 	SharedData() interface{}
 
 	SetConfiguration(config Config)
+	TryToSetConfiguration(config Config) error
 	Configuration() Config
 
 	SetImplementation(impl Impl)
+	TryToSetImplementation(impl Impl) error
 	Implementation() Impl
 
-	SetHandlerFor(methods string, handler Handler)
+	SetHandlerFor(methods string, handler Handler, opts ...HandlerOption)
+	TryToSetHandlerFor(
+		methods string,
+		handler Handler,
+		opts ...HandlerOption,
+	) error
 	HandlerOf(method string) Handler
 
 	WrapRequestPasser(mws ...Middleware)
+	TryToWrapRequestPasser(mws ...Middleware) error
 	WrapRequestHandler(mws ...Middleware)
+	TryToWrapRequestHandler(mws ...Middleware) error
 	WrapHandlerOf(methods string, mws ...Middleware)
+	TryToWrapHandlerOf(methods string, mws ...Middleware) error
 
 	SetPermanentRedirectCode(code int)
+	TryToSetPermanentRedirectCode(code int) error
 	PermanentRedirectCode() int
 	SetRedirectHandler(handler RedirectHandler)
+	TryToSetRedirectHandler(handler RedirectHandler) error
 	RedirectHandler() RedirectHandler
 	WrapRedirectHandler(mws ...func(RedirectHandler) RedirectHandler)
+	TryToWrapRedirectHandler(
+		mws ...func(RedirectHandler) RedirectHandler,
+	) error
 
 	RedirectRequestTo(url string, redirectCode int)
+	TryToRedirectRequestTo(url string, redirectCode int) error
 	RedirectAnyRequestTo(url string, redirectCode int)
+	TryToRedirectAnyRequestTo(url string, redirectCode int) error
+
+	SetHandlerForNotFound(handler Handler)
+	TryToSetHandlerForNotFound(handler Handler) error
+	HandlerOfNotFound() Handler
+	WrapHandlerOfNotFound(mws ...Middleware)
+	TryToWrapHandlerOfNotFound(mws ...Middleware) error
+
+	SetCORSPolicy(policy CORSPolicy)
+	TryToSetCORSPolicy(policy CORSPolicy) error
+	CORSPolicy() *CORSPolicy
+
+	SetErrorRenderer(renderer ErrorRenderer)
+	TryToSetErrorRenderer(renderer ErrorRenderer) error
+	ErrorRenderer() ErrorRenderer
 
 	// -------------------------
 
 	SetSharedDataAt(pathTmplStr string, data interface{})
+	TryToSetSharedDataAt(pathTmplStr string, data interface{}) error
 	SharedDataAt(pathTmplStr string) interface{}
 
 	SetConfigurationAt(pathTmplStr string, config Config)
+	TryToSetConfigurationAt(pathTmplStr string, config Config) error
 	ConfigurationAt(pathTmplStr string) Config
 
 	SetImplementationAt(pathTmplStr string, impl Impl)
+	TryToSetImplementationAt(pathTmplStr string, impl Impl) error
 	ImplementationAt(pathTmplStr string) Impl
 
-	SetPathHandlerFor(methods, pathTmplStr string, handler Handler)
+	SetPathHandlerFor(
+		methods, pathTmplStr string,
+		handler Handler,
+		opts ...HandlerOption,
+	)
+	TryToSetPathHandlerFor(
+		methods, pathTmplStr string,
+		handler Handler,
+		opts ...HandlerOption,
+	) error
 	PathHandlerOf(method, pathTmplStr string) Handler
 
 	WrapRequestPasserAt(pathTmplStr string, mws ...Middleware)
+	TryToWrapRequestPasserAt(pathTmplStr string, mws ...Middleware) error
 	WrapRequestHandlerAt(pathTmplStr string, mws ...Middleware)
+	TryToWrapRequestHandlerAt(pathTmplStr string, mws ...Middleware) error
 	WrapPathHandlerOf(methods, pathTmplStr string, mws ...Middleware)
+	TryToWrapPathHandlerOf(
+		methods, pathTmplStr string,
+		mws ...Middleware,
+	) error
 
 	SetPermanentRedirectCodeAt(pathTmplStr string, code int)
+	TryToSetPermanentRedirectCodeAt(pathTmplStr string, code int) error
 	PermanentRedirectCodeAt(pathTmplStr string) int
 	SetRedirectHandlerAt(pathTmplStr string, handler RedirectHandler)
+	TryToSetRedirectHandlerAt(
+		pathTmplStr string,
+		handler RedirectHandler,
+	) error
 	RedirectHandlerAt(pathTmplStr string) RedirectHandler
 	WrapRedirectHandlerAt(
 		pathTmplStr string,
 		mws ...func(RedirectHandler) RedirectHandler,
 	)
+	TryToWrapRedirectHandlerAt(
+		pathTmplStr string,
+		mws ...func(RedirectHandler) RedirectHandler,
+	) error
 
 	RedirectRequestAt(pathTmplStr, url string, redirectCode int)
+	TryToRedirectRequestAt(pathTmplStr, url string, redirectCode int) error
 	RedirectAnyRequestAt(pathTmplStr, url string, redirectCode int)
+	TryToRedirectAnyRequestAt(pathTmplStr, url string, redirectCode int) error
 
 	// -------------------------
 
 	SetSharedDataForSubtree(data interface{})
 	SetConfigurationForSubtree(config Config)
+	TryToSetConfigurationForSubtree(config Config) error
 
 	WrapSubtreeRequestPassers(mws ...Middleware)
+	TryToWrapSubtreeRequestPassers(mws ...Middleware) error
 	WrapSubtreeRequestHandlers(mws ...Middleware)
+	TryToWrapSubtreeRequestHandlers(mws ...Middleware) error
 	WrapSubtreeHandlersOf(methods string, mws ...Middleware)
+	TryToWrapSubtreeHandlersOf(methods string, mws ...Middleware) error
+
+	// -------------------------
+
+	Routes() []Route
+	appendRoutesTo(routes *[]Route)
 
 	// -------------------------
 
@@ -180,14 +262,28 @@ type _ResponderBase struct {
 	patternResources []*Resource
 	wildcardResource *Resource
 
+	// caseInsensitiveResources keeps the case-insensitive static child
+	// resources by their lowercase templates.
+	caseInsensitiveResources map[string]*Resource
+
 	*_RequestHandlerBase
 	requestReceiver   Handler
 	requestPasser     Handler
 	requestHandler    Handler
 	requestRedirector Handler
 
+	// Middlewares and redirect arguments are kept to rebuild the handlers
+	// bound to the responder when it's cloned.
+	passerMws              []Middleware
+	handlerMws             []Middleware
+	requestRedirectArgs    *_RedirectArgs
+	anyRequestRedirectArgs *_RedirectArgs
+
 	permanentRedirectCode int
 	redirectHandler       RedirectHandler
+	notFoundHandler       Handler
+	corsPolicy            *CORSPolicy
+	errorRenderer         ErrorRenderer
 
 	cfs        _ConfigFlags
 	sharedData interface{}
@@ -219,6 +315,11 @@ func (rb *_ResponderBase) URL(values HostPathValues) (*url.URL, error) {
 func (rb *_ResponderBase) Router() *Router {
 	for p := rb.papa; p != nil; p = p.parent() {
 		if ro, ok := p.(*Router); ok {
+			if ro.origin != nil {
+				// The responder belongs to a copy made by an update.
+				return ro.origin
+			}
+
 			return ro
 		}
 	}
@@ -237,15 +338,25 @@ func (rb *_ResponderBase) setParent(p _Parent) error {
 
 	if _, ok := rb.derived.(*Host); ok {
 		// Only a router can be set as a parent for a host.
-		if _, ok := p.(*Router); !ok {
-			return newErr("%w", errNonRouterParent)
+		if _r, ok := p.(_Responder); ok {
+			return newRegistrationErr(
+				"register",
+				rb.Template().String(),
+				_r,
+				ErrNonRouterParent,
+			)
 		}
 	}
 
 	if rb.Template().UnescapedContent() == "/" {
 		// Only a router can be set as a parent for a root.
-		if _, ok := p.(*Router); !ok {
-			return newErr("%w", errNonRouterParent)
+		if _r, ok := p.(_Responder); ok {
+			return newRegistrationErr(
+				"register",
+				rb.Template().String(),
+				_r,
+				ErrNonRouterParent,
+			)
 		}
 	}
 
@@ -295,7 +406,15 @@ func (rb *_ResponderBase) configFlags() _ConfigFlags {
 	return rb.cfs
 }
 
-func (rb *_ResponderBase) configure(secure, tslash bool, cfs *_ConfigFlags) {
+// configure configures the responder. If the responder is a static child of
+// its parent, and the config flags make it collide with one of its siblings
+// regardless of case, the responder's previous config flags are restored and
+// an error is returned.
+func (rb *_ResponderBase) configure(
+	secure, tslash bool,
+	cfs *_ConfigFlags,
+) error {
+	var oldCfs = rb.cfs
 	rb.setConfigFlags(flagActive)
 
 	if secure {
@@ -308,7 +427,33 @@ func (rb *_ResponderBase) configure(secure, tslash bool, cfs *_ConfigFlags) {
 
 	if cfs != nil {
 		rb.setConfigFlags(*cfs)
+		if err := rb.reindexInParent(); err != nil {
+			rb.resetConfigFlags(oldCfs)
+			rb.reindexInParent()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reindexInParent rebuilds the parent's index of the case-insensitive static
+// children when the responder's case sensitivity may have been changed.
+func (rb *_ResponderBase) reindexInParent() error {
+	if rb.papa == nil || !rb.tmpl.IsStatic() {
+		return nil
 	}
+
+	switch p := rb.papa.(type) {
+	case *Router:
+		return p.indexCaseInsensitiveHosts()
+	case *Host:
+		return p.indexCaseInsensitiveResources()
+	case *Resource:
+		return p.indexCaseInsensitiveResources()
+	}
+
+	return nil
 }
 
 // checkForConfigCompatibility checks the configured responder's properties
@@ -320,26 +465,42 @@ func (rb *_ResponderBase) checkForConfigCompatibility(
 ) error {
 	var rbcfs = rb.configFlags()
 	if rbcfs.has(flagActive) {
+		var tmplStr = rb.Template().String()
 		if rbcfs.has(flagSecure) != secure {
-			return newErr("%w", errConflictingSecurity)
+			return newRegistrationErr(
+				"configure",
+				tmplStr,
+				rb.derived,
+				ErrConflictingSecurity,
+			)
 		}
 
 		if !rbcfs.has(flagLenientOnTrailingSlash) &&
 			rbcfs.has(flagTrailingSlash) != tslash {
-			return newErr("%w", errConflictingTrailingSlash)
+			return newRegistrationErr(
+				"configure",
+				tmplStr,
+				rb.derived,
+				ErrConflictingTrailingSlash,
+			)
 		}
 
 		if cfs != nil {
 			if !rbcfs.has(*cfs) {
-				return newErr("%w", errConflictingConfig)
+				return newRegistrationErr(
+					"configure",
+					tmplStr,
+					rb.derived,
+					ErrConflictingConfig,
+				)
 			}
 		}
 	} else {
 		switch rb.derived.(type) {
 		case *Host:
-			return newErr("%w", errDormantHost)
+			return newErr("%w", ErrDormantHost)
 		case *Resource:
-			return newErr("%w", errDormantResource)
+			return newErr("%w", ErrDormantResource)
 		}
 	}
 
@@ -404,6 +565,32 @@ func (rb *_ResponderBase) HandlesThePathAsIs() bool {
 	return rb.cfs.has(flagHandlesThePathAsIs)
 }
 
+// IsCaseInsensitive returns true if the responder was configured to match
+// the static parts of its template regardless of case.
+func (rb *_ResponderBase) IsCaseInsensitive() bool {
+	return rb.cfs.has(flagCaseInsensitive)
+}
+
+// RedirectsToCanonicalCase returns true if the responder was configured to
+// redirect the request to the URL with the path segment in its template's
+// case.
+func (rb *_ResponderBase) RedirectsToCanonicalCase() bool {
+	return rb.cfs.has(flagRedirectsToCanonicalCase)
+}
+
+// HandlesHeadWithGet returns true if the responder was configured to handle
+// a HEAD request with its GET handler when it doesn't have a HEAD handler.
+func (rb *_ResponderBase) HandlesHeadWithGet() bool {
+	return rb.cfs.has(flagHandlesHeadWithGet)
+}
+
+// AllowedHTTPMethods returns the HTTP methods in use. If the responder
+// handles HEAD requests with its GET handler, HEAD is also included, the same
+// as in the Allow header and in the methods allowed by the CORS policy.
+func (rb *_ResponderBase) AllowedHTTPMethods() []string {
+	return rb._RequestHandlerBase.allowedMethods(rb.HandlesHeadWithGet())
+}
+
 // canHandleRequest returns true if the responder has at least one HTTP method
 // handler.
 func (rb *_ResponderBase) canHandleRequest() bool {
@@ -421,14 +608,24 @@ func (rb *_ResponderBase) checkNamesAreUniqueInTheURL(tmpl *Template) error {
 		return nil
 	}
 
-	for p := _Parent(rb); p != nil; p = p.parent() {
+	for p := _Parent(rb.derived); p != nil; p = p.parent() {
 		if r, ok := p.(_Responder); ok {
 			if tmpl.name != "" && r.Name() == tmpl.name {
-				return errDuplicateNameInTheURL
+				return newRegistrationErr(
+					"register",
+					tmpl.String(),
+					r,
+					ErrDuplicateNameInTheURL,
+				)
 			}
 
 			if r.Template().HasValueName(tmplValueNames...) {
-				return errDuplicateValueNameInTheURL
+				return newRegistrationErr(
+					"register",
+					tmpl.String(),
+					r,
+					ErrDuplicateValueNameInTheURL,
+				)
 			}
 		} else {
 			break
@@ -460,10 +657,20 @@ func (rb *_ResponderBase) checkChildResourceNamesAreUniqueInURL(
 }
 
 // validate checks whether the argument template pointer is nil and if its name
-// is unique in the responder's URL.
+// is unique in the responder's URL. It also checks that the responder can have
+// child resources.
 func (rb *_ResponderBase) validate(tmpl *Template) error {
 	if tmpl == nil {
-		return newErr("%w", errNilArgument)
+		return newErr("%w", ErrNilArgument)
+	}
+
+	if rb.tmpl.IsCatchAll() {
+		return newRegistrationErr(
+			"register",
+			tmpl.String(),
+			rb.derived,
+			ErrCatchAllParent,
+		)
 	}
 
 	if err := rb.checkNamesAreUniqueInTheURL(tmpl); err != nil {
@@ -488,7 +695,12 @@ func (rb *_ResponderBase) validateHostTmpl(hostTmplStr string) error {
 		}
 
 		if h == nil {
-			return newErr("%w", errConflictingHost)
+			return newRegistrationErr(
+				"validate",
+				hostTmplStr,
+				rb.derived,
+				ErrConflictingHost,
+			)
 		}
 
 		var tmpl, err = TryToParse(hostTmplStr)
@@ -537,7 +749,12 @@ func (rb *_ResponderBase) validateURL(hostTmplStr string, pathTmplStr string) (
 	for i := 0; i < lresources; i++ {
 		var ps = psi.nextSegment()
 		if ps == "" {
-			return "", newErr("%w", errConflictingPath)
+			return "", newRegistrationErr(
+				"validate",
+				pathTmplStr,
+				resources[i],
+				ErrConflictingPath,
+			)
 		}
 
 		var tmpl, err = TryToParse(ps)
@@ -548,7 +765,12 @@ func (rb *_ResponderBase) validateURL(hostTmplStr string, pathTmplStr string) (
 		var rtmpl = resources[i].Template()
 		var similarity = rtmpl.SimilarityWith(tmpl)
 		if similarity != TheSame {
-			return "", newErr("%w %q", errConflictingPathSegment, ps)
+			return "", newRegistrationErr(
+				"validate",
+				ps,
+				resources[i],
+				ErrConflictingPathSegment,
+			)
 		}
 	}
 
@@ -584,6 +806,9 @@ func (rb *_ResponderBase) resourceWithTemplate(tmpl *Template) (
 			}
 
 			switch sim := wtmpl.SimilarityWith(tmpl); sim {
+			case Different:
+				// One of the templates is a catch-all template.
+				fallthrough
 			case DifferentValueNames:
 				fallthrough
 			case DifferentNames:
@@ -692,6 +917,7 @@ func (rb *_ResponderBase) passChildResourcesTo(r _Responder) error {
 	}
 
 	rb.staticResources = nil
+	rb.caseInsensitiveResources = nil
 	rb.patternResources = nil
 	rb.wildcardResource = nil
 
@@ -706,6 +932,11 @@ func (rb *_ResponderBase) replaceResource(oldR, newR *Resource) error {
 	switch {
 	case tmpl.IsStatic():
 		rb.staticResources[tmpl.UnescapedContent()] = newR
+		if err := rb.indexCaseInsensitiveResources(); err != nil {
+			rb.staticResources[tmpl.UnescapedContent()] = oldR
+			rb.indexCaseInsensitiveResources()
+			return err
+		}
 	case tmpl.IsWildcard():
 		rb.wildcardResource = newR
 	default:
@@ -735,10 +966,70 @@ func (rb *_ResponderBase) replaceResource(oldR, newR *Resource) error {
 	return nil
 }
 
+// unregisterResource removes the child resource from the responder and
+// clears its parent. The resource's subtree stays intact.
+func (rb *_ResponderBase) unregisterResource(r *Resource) error {
+	if !rb.HasChildResource(r) {
+		return newErr("%w", ErrNonExistentResource)
+	}
+
+	switch tmpl := r.Template(); {
+	case tmpl.IsStatic():
+		delete(rb.staticResources, tmpl.UnescapedContent())
+		if len(rb.staticResources) == 0 {
+			rb.staticResources = nil
+		}
+
+		if err := rb.indexCaseInsensitiveResources(); err != nil {
+			if rb.staticResources == nil {
+				rb.staticResources = make(map[string]*Resource)
+			}
+
+			rb.staticResources[tmpl.UnescapedContent()] = r
+			rb.indexCaseInsensitiveResources()
+			return err
+		}
+	case tmpl.IsWildcard():
+		rb.wildcardResource = nil
+	default:
+		for i, pr := range rb.patternResources {
+			if pr == r {
+				rb.patternResources = append(
+					rb.patternResources[:i:i],
+					rb.patternResources[i+1:]...,
+				)
+
+				break
+			}
+		}
+
+		if len(rb.patternResources) == 0 {
+			rb.patternResources = nil
+		}
+	}
+
+	var err = r.setParent(nil)
+	if err != nil {
+		// Unreachable.
+		return newErr("%w", err)
+	}
+
+	return nil
+}
+
 // registerResource registers the argument resource and sets the responder
 // as its parent. The method doesn't check if an existing resource with the
 // same template exists or not.
 func (rb *_ResponderBase) registerResource(r *Resource) error {
+	if rb.tmpl.IsCatchAll() {
+		return newRegistrationErr(
+			"register",
+			r.Template().String(),
+			rb.derived,
+			ErrCatchAllParent,
+		)
+	}
+
 	switch tmpl := r.Template(); {
 	case tmpl.IsStatic():
 		if rb.staticResources == nil {
@@ -746,6 +1037,15 @@ func (rb *_ResponderBase) registerResource(r *Resource) error {
 		}
 
 		rb.staticResources[tmpl.UnescapedContent()] = r
+		if err := rb.indexCaseInsensitiveResources(); err != nil {
+			delete(rb.staticResources, tmpl.UnescapedContent())
+			if len(rb.staticResources) == 0 {
+				rb.staticResources = nil
+			}
+
+			rb.indexCaseInsensitiveResources()
+			return err
+		}
 	case tmpl.IsWildcard():
 		rb.wildcardResource = r
 	default:
@@ -811,7 +1111,13 @@ func (rb *_ResponderBase) segmentResources(pathSegments []string) (
 			} else {
 				if name := tmpl.Name(); name != "" {
 					if chr := oldLast.ChildResourceNamed(name); chr != nil {
-						err = newErr("%w", errDuplicateNameAmongSiblings)
+						err = newRegistrationErr(
+							"register",
+							tmpl.String(),
+							chr,
+							ErrDuplicateNameAmongSiblings,
+						)
+
 						return
 					}
 				}
@@ -850,7 +1156,12 @@ func (rb *_ResponderBase) pathSegmentResources(pathTmplStr string) (
 			return
 		}
 
-		err = newErr("%w", errNonRouterParent)
+		err = newRegistrationErr(
+			"register",
+			pathTmplStr,
+			rb.derived,
+			ErrNonRouterParent,
+		)
 		return
 	}
 
@@ -903,7 +1214,6 @@ func (rb *_ResponderBase) registerResourceUnder(
 		}
 
 		if err = oldLast.registerResource(newFirst); err != nil {
-			// Unreachable.
 			return newErr("%w", err)
 		}
 
@@ -941,7 +1251,12 @@ func (rb *_ResponderBase) keepResourceOrItsChildResources(r *Resource) error {
 
 	if rwt == nil {
 		if nr := rb.ChildResourceNamed(r.Name()); nr != nil {
-			return newErr("%w", errDuplicateNameAmongSiblings)
+			return newRegistrationErr(
+				"register",
+				r.Template().String(),
+				nr,
+				ErrDuplicateNameAmongSiblings,
+			)
 		}
 
 		if err = rb.registerResource(r); err != nil {
@@ -958,7 +1273,7 @@ func (rb *_ResponderBase) keepResourceOrItsChildResources(r *Resource) error {
 		&rcfs,
 	)
 
-	if err != nil && !errors.Is(err, errDormantResource) {
+	if err != nil && !errors.Is(err, ErrDormantResource) {
 		return newErr("%w", err)
 	}
 
@@ -979,17 +1294,17 @@ func (rb *_ResponderBase) keepResourceOrItsChildResources(r *Resource) error {
 
 		err = rb.replaceResource(rwt, r)
 		if err != nil {
-			// Unreachable.
 			return newErr("%w", err)
 		}
 
 		return nil
 	}
 
-	return newErr(
-		"%w %s",
-		errDuplicateResourceTemplate,
-		rwt.Template().String(),
+	return newRegistrationErr(
+		"register",
+		r.Template().String(),
+		rwt,
+		ErrDuplicateResourceTemplate,
 	)
 }
 
@@ -1008,6 +1323,20 @@ func (rb *_ResponderBase) keepResourceOrItsChildResources(r *Resource) error {
 // The names given to the path segment resources must be unique in the path and
 // among their respective siblings.
 func (rb *_ResponderBase) Resource(pathTmplStr string) *Resource {
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return r
+}
+
+// TryToResource is like Resource but returns an error instead of panicking.
+func (rb *_ResponderBase) TryToResource(pathTmplStr string) (*Resource, error) {
+	if err := rb.checkChangeable(); err != nil {
+		return nil, newErr("%w", err)
+	}
+
 	var (
 		hTmplStr       string
 		secure, tslash bool
@@ -1016,16 +1345,21 @@ func (rb *_ResponderBase) Resource(pathTmplStr string) *Resource {
 
 	hTmplStr, pathTmplStr, secure, tslash, err = splitHostAndPath(pathTmplStr)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
 	if hTmplStr != "" || pathTmplStr == "/" {
-		panicWithErr("%w", errNonRouterParent)
+		return nil, newRegistrationErr(
+			"register",
+			hTmplStr + pathTmplStr,
+			rb.derived,
+			ErrNonRouterParent,
+		)
 	}
 
 	if pathTmplStr == "" {
 		// Unreachable.
-		panicWithErr("%w", errEmptyPathTemplate)
+		return nil, newErr("%w", errEmptyPathTemplate)
 	}
 
 	if pathTmplStr[0] != '/' {
@@ -1036,30 +1370,29 @@ func (rb *_ResponderBase) Resource(pathTmplStr string) *Resource {
 	var newFirst, newLast *Resource
 	oldLast, newFirst, newLast, _, err = rb.pathSegmentResources(pathTmplStr)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
 	if newFirst != nil {
 		newLast.configure(secure, tslash, nil)
 		if err = oldLast.registerResource(newFirst); err != nil {
-			// Unreachable.
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 
-		return newLast
+		return newLast, nil
 	}
 
 	err = oldLast.checkForConfigCompatibility(secure, tslash, nil)
 	if err != nil {
-		if errors.Is(err, errDormantHost) ||
-			errors.Is(err, errDormantResource) {
+		if errors.Is(err, ErrDormantHost) ||
+			errors.Is(err, ErrDormantResource) {
 			oldLast.configure(secure, tslash, nil)
 		} else {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	}
 
-	return oldLast.(*Resource)
+	return oldLast.(*Resource), nil
 }
 
 // ResourceUsingConfig uses the path template and config to find an existing
@@ -1079,6 +1412,24 @@ func (rb *_ResponderBase) ResourceUsingConfig(
 	pathTmplStr string,
 	config Config,
 ) *Resource {
+	var r, err = rb.TryToResourceUsingConfig(pathTmplStr, config)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return r
+}
+
+// TryToResourceUsingConfig is like ResourceUsingConfig but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToResourceUsingConfig(
+	pathTmplStr string,
+	config Config,
+) (*Resource, error) {
+	if err := rb.checkChangeable(); err != nil {
+		return nil, newErr("%w", err)
+	}
+
 	var (
 		hTmplStr       string
 		secure, tslash bool
@@ -1087,20 +1438,30 @@ func (rb *_ResponderBase) ResourceUsingConfig(
 
 	hTmplStr, pathTmplStr, secure, tslash, err = splitHostAndPath(pathTmplStr)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
 	if hTmplStr != "" || pathTmplStr == "/" {
-		panicWithErr("%w", errNonRouterParent)
+		return nil, newRegistrationErr(
+			"register",
+			hTmplStr + pathTmplStr,
+			rb.derived,
+			ErrNonRouterParent,
+		)
 	}
 
 	if pathTmplStr == "" {
 		// Unreachable.
-		panicWithErr("%w", errEmptyPathTemplate)
+		return nil, newErr("%w", errEmptyPathTemplate)
 	}
 
 	if config.RedirectsInsecureRequest && !secure {
-		panicWithErr("%w", errConflictingSecurity)
+		return nil, newRegistrationErr(
+			"configure",
+			hTmplStr + pathTmplStr,
+			nil,
+			ErrConflictingSecurity,
+		)
 	}
 
 	if pathTmplStr[0] != '/' {
@@ -1111,31 +1472,33 @@ func (rb *_ResponderBase) ResourceUsingConfig(
 	var newFirst, newLast *Resource
 	oldLast, newFirst, newLast, _, err = rb.pathSegmentResources(pathTmplStr)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
 	var cfs = config.asFlags()
 	if newFirst != nil {
 		newLast.configure(secure, tslash, &cfs)
 		if err = oldLast.registerResource(newFirst); err != nil {
-			// Unreachable.
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 
-		return newLast
+		return newLast, nil
 	}
 
 	err = oldLast.checkForConfigCompatibility(secure, tslash, &cfs)
 	if err != nil {
-		if errors.Is(err, errDormantHost) ||
-			errors.Is(err, errDormantResource) {
-			oldLast.configure(secure, tslash, &cfs)
+		if errors.Is(err, ErrDormantHost) ||
+			errors.Is(err, ErrDormantResource) {
+			err = oldLast.configure(secure, tslash, &cfs)
+			if err != nil {
+				return nil, newErr("%w", err)
+			}
 		} else {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	}
 
-	return oldLast.(*Resource)
+	return oldLast.(*Resource), nil
 }
 
 // RegisterResource registers the argument resource below in the tree of
@@ -1154,45 +1517,70 @@ func (rb *_ResponderBase) ResourceUsingConfig(
 // set and passes the other one's child resources to it. If both can handle a
 // request, the method panics. Child resources are also checked recursively.
 func (rb *_ResponderBase) RegisterResource(r *Resource) {
+	var err = rb.TryToRegisterResource(r)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToRegisterResource is like RegisterResource but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToRegisterResource(r *Resource) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if r == nil {
-		panicWithErr("%w", errNilArgument)
+		return newErr("%w", ErrNilArgument)
 	}
 
 	if r.isRoot() {
-		panicWithErr("%w", errNonRouterParent)
+		return newRegistrationErr(
+			"register",
+			r.Template().String(),
+			rb.derived,
+			ErrNonRouterParent,
+		)
 	}
 
 	if r.parent() != nil {
-		panicWithErr("%w", errRegisteredResource)
+		return newRegistrationErr(
+			"register",
+			r.Template().String(),
+			r,
+			ErrRegisteredResource,
+		)
 	}
 
 	if err := rb.validate(r.Template()); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if err := rb.checkChildResourceNamesAreUniqueInURL(r); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if urlt := r.urlTmpl(); urlt != nil {
 		var rppss, err = rb.validateURL(urlt.Host, urlt.PrefixPath)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 
 		if len(rppss) > 0 {
 			err = rb.registerResourceUnder(rppss, r)
 			if err != nil {
-				panicWithErr("%w", err)
+				return newErr("%w", err)
 			}
 
-			return
+			return nil
 		}
 	}
 
 	if err := rb.keepResourceOrItsChildResources(r); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
+
+	return nil
 }
 
 // RegisterResourceUnder registers the argument resource below the responder
@@ -1216,31 +1604,62 @@ func (rb *_ResponderBase) RegisterResourceUnder(
 	prefixPath string,
 	r *Resource,
 ) {
+	var err = rb.TryToRegisterResourceUnder(prefixPath, r)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToRegisterResourceUnder is like RegisterResourceUnder but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToRegisterResourceUnder(
+	prefixPath string,
+	r *Resource,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if r == nil {
-		panicWithErr("%w", errNilArgument)
+		return newErr("%w", ErrNilArgument)
 	}
 
 	if r.isRoot() {
-		panicWithErr("%w", errNonRouterParent)
+		return newRegistrationErr(
+			"register",
+			r.Template().String(),
+			rb.derived,
+			ErrNonRouterParent,
+		)
 	}
 
 	if r.parent() != nil {
-		panicWithErr("%w", errRegisteredResource)
+		return newRegistrationErr(
+			"register",
+			r.Template().String(),
+			r,
+			ErrRegisteredResource,
+		)
 	}
 
 	if err := rb.validate(r.Template()); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if err := rb.checkChildResourceNamesAreUniqueInURL(r); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if prefixPath == "/" {
 		if _, ok := rb.derived.(*Host); ok {
 			prefixPath = ""
 		} else {
-			panicWithErr("%w", errNonRouterParent)
+			return newRegistrationErr(
+				"register",
+				prefixPath,
+				rb.derived,
+				ErrNonRouterParent,
+			)
 		}
 	}
 
@@ -1258,7 +1677,12 @@ func (rb *_ResponderBase) RegisterResourceUnder(
 		}
 
 		if lpp > lurltPp {
-			panicWithErr("%w", errConflictingPath)
+			return newRegistrationErr(
+				"validate",
+				prefixPath,
+				r,
+				ErrConflictingPath,
+			)
 		}
 
 		var pp = urlt.PrefixPath
@@ -1268,26 +1692,33 @@ func (rb *_ResponderBase) RegisterResourceUnder(
 
 		var rppss, err = rb.validateURL(urlt.Host, pp)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 
 		if len(rppss) > 0 {
-			panicWithErr("%w", errConflictingPath)
+			return newRegistrationErr(
+				"validate",
+				prefixPath,
+				r,
+				ErrConflictingPath,
+			)
 		}
 	}
 
 	if prefixPath != "" {
 		var err = rb.registerResourceUnder(prefixPath, r)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 
-		return
+		return nil
 	}
 
 	if err := rb.keepResourceOrItsChildResources(r); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
+
+	return nil
 }
 
 // RegisteredResource returns the resource in the tree below the responder
@@ -1301,6 +1732,19 @@ func (rb *_ResponderBase) RegisterResourceUnder(
 // The scheme and trailing slash properties must be compatible with the
 // resource's.
 func (rb *_ResponderBase) RegisteredResource(pathTmplStr string) *Resource {
+	var r, err = rb.TryToRegisteredResource(pathTmplStr)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return r
+}
+
+// TryToRegisteredResource is like RegisteredResource but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToRegisteredResource(
+	pathTmplStr string,
+) (*Resource, error) {
 	var (
 		hTmplStr       string
 		secure, tslash bool
@@ -1309,40 +1753,132 @@ func (rb *_ResponderBase) RegisteredResource(pathTmplStr string) *Resource {
 
 	hTmplStr, pathTmplStr, secure, tslash, err = splitHostAndPath(pathTmplStr)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
 	if hTmplStr != "" || pathTmplStr == "/" {
-		panicWithErr("%w", errNonRouterParent)
+		return nil, newRegistrationErr(
+			"validate",
+			hTmplStr + pathTmplStr,
+			rb.derived,
+			ErrNonRouterParent,
+		)
 	}
 
 	if pathTmplStr == "" {
 		// Unreachable.
-		panicWithErr("%w", errEmptyPathTemplate)
+		return nil, newErr("%w", errEmptyPathTemplate)
 	}
 
 	var r *Resource
 	r, _, err = rb.registeredResource(pathTmplStr)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
 	if r != nil {
 		err = r.checkForConfigCompatibility(secure, tslash, nil)
 		if err != nil {
-			if errors.Is(err, errDormantResource) {
+			if errors.Is(err, ErrDormantResource) {
 				r.configure(secure, tslash, nil)
 			} else {
-				panicWithErr("%w", err)
+				return nil, newErr("%w", err)
 			}
 		}
 
-		return r
+		return r, nil
+	}
+
+	return nil, nil
+}
+
+// UnregisterResource detaches the resource and its subtree from the tree.
+// The resource must be in the responder's subtree, otherwise the method
+// panics. After unregistration, the resource doesn't have a parent and can be
+// registered again.
+func (rb *_ResponderBase) UnregisterResource(r *Resource) {
+	var err = rb.TryToUnregisterResource(r)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToUnregisterResource is like UnregisterResource but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToUnregisterResource(r *Resource) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if r == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	var p _Parent
+	for p = r.parent(); p != nil; p = p.parent() {
+		if p == rb.derived {
+			break
+		}
+	}
+
+	if p == nil {
+		return newErr("%w", ErrNonExistentResource)
+	}
+
+	var err = r.parent().(_Responder).unregisterResource(r)
+	if err != nil {
+		return newErr("%w", err)
 	}
 
 	return nil
 }
 
+// UnregisterResourceAt detaches the resource at the path from the tree and
+// returns it. The resource's subtree is detached along with it. In the path
+// template, names can be used instead of the complete segment templates.
+// If the resource doesn't exist, the method panics.
+//
+// For example,
+// 		/childResourceTemplate/$someName/anotherResourceTemplate/,
+//		https:///$childResourceName/$grandChildResourceName
+//
+// The scheme and trailing slash properties must be compatible with the
+// resource's.
+func (rb *_ResponderBase) UnregisterResourceAt(pathTmplStr string) *Resource {
+	var r, err = rb.TryToUnregisterResourceAt(pathTmplStr)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return r
+}
+
+// TryToUnregisterResourceAt is like UnregisterResourceAt but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToUnregisterResourceAt(
+	pathTmplStr string,
+) (*Resource, error) {
+	if err := rb.checkChangeable(); err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var r, err = rb.TryToRegisteredResource(pathTmplStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	if r == nil {
+		return nil, newErr("%w", ErrNonExistentResource)
+	}
+
+	err = r.parent().(_Responder).unregisterResource(r)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return r, nil
+}
+
 // ChildResourceNamed returns the named resource if it exists, otherwise
 // it returns nil. Only the direct child resources of the responder will
 // be looked at.
@@ -1454,6 +1990,10 @@ func (rb *_ResponderBase) HasAnyChildResources() bool {
 //		...
 // 	}
 func (rb *_ResponderBase) SetSharedData(data interface{}) {
+	if err := rb.checkChangeable(); err != nil {
+		panicWithErr("%w", err)
+	}
+
 	rb.sharedData = data
 }
 
@@ -1475,7 +2015,22 @@ func (rb *_ResponderBase) SharedData() interface{} {
 // config's RedirectsInsecureRequest field is set to true, the responder will
 // also be configured to be secure, even if it wasn't before. The secure
 // responders only respond when used over HTTPS.
+//
+// If the responder's static template collides with the template of one of
+// its siblings regardless of case after the change, the method panics.
 func (rb *_ResponderBase) SetConfiguration(config Config) {
+	if err := rb.TryToSetConfiguration(config); err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetConfiguration is like SetConfiguration but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToSetConfiguration(config Config) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if rb.Template().Content() == "/" {
 		config.HasTrailingSlash = false
 		config.LenientOnTrailingSlash = false
@@ -1490,7 +2045,15 @@ func (rb *_ResponderBase) SetConfiguration(config Config) {
 	var secure = rb.cfs & flagSecure
 	var tslash = rb.cfs & flagTrailingSlash
 
+	var oldCfs = rb.cfs
 	rb.resetConfigFlags(flagActive | secure | tslash | config.asFlags())
+	if err := rb.reindexInParent(); err != nil {
+		rb.resetConfigFlags(oldCfs)
+		rb.reindexInParent()
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // Configuration returns the configuration of responder.
@@ -1504,14 +2067,27 @@ func (rb *_ResponderBase) Configuration() Config {
 // The impl is also kept for future retrieval. All existing handlers
 // are discarded.
 func (rb *_ResponderBase) SetImplementation(impl Impl) {
-	if impl == nil {
-		panicWithErr("%w", errNilArgument)
+	var err = rb.TryToSetImplementation(impl)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetImplementation is like SetImplementation but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToSetImplementation(impl Impl) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if impl == nil {
+		return newErr("%w", ErrNilArgument)
 	}
 
 	var rhb, err = detectHTTPMethodHandlersOf(impl)
 	if err != nil {
 		// Unreachable.
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	rb.impl = impl
@@ -1519,6 +2095,8 @@ func (rb *_ResponderBase) SetImplementation(impl Impl) {
 	if rhb != nil {
 		rb.setRequestHandlerBase(rhb)
 	}
+
+	return nil
 }
 
 // Implementation returns the implementation of the responder. If the responder
@@ -1537,15 +2115,41 @@ func (rb *_ResponderBase) Implementation() Impl {
 // method and must be used alone. That is, setting the not allowed HTTP method
 // handler must happen in a separate call. Examples of methods: "GET", "PUT,
 // POST", "SHARE, LOCK" or "!".
-func (rb *_ResponderBase) SetHandlerFor(methods string, handler Handler) {
+//
+// The options Produces and Consumes can be used to set several handlers for
+// the same HTTP method that are selected by the media types of the request.
+func (rb *_ResponderBase) SetHandlerFor(
+	methods string,
+	handler Handler,
+	opts ...HandlerOption,
+) {
+	var err = rb.TryToSetHandlerFor(methods, handler, opts...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetHandlerFor is like SetHandlerFor but returns an error instead of
+// panicking.
+func (rb *_ResponderBase) TryToSetHandlerFor(
+	methods string,
+	handler Handler,
+	opts ...HandlerOption,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if rb._RequestHandlerBase == nil {
 		rb.setRequestHandlerBase(&_RequestHandlerBase{})
 	}
 
-	var err = rb.setHandlerFor(methods, handler)
+	var err = rb.setHandlerFor(methods, handler, opts...)
 	if err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
+
+	return nil
 }
 
 // HandlerOf returns the HTTP method handler of the responder. If the handler
@@ -1571,17 +2175,33 @@ func (rb *_ResponderBase) HandlerOf(method string) Handler {
 // resource to the next path segment of the request's URL, the handler for a
 // not-found resource is called.
 func (rb *_ResponderBase) WrapRequestPasser(mws ...Middleware) {
+	var err = rb.TryToWrapRequestPasser(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapRequestPasser is like WrapRequestPasser but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToWrapRequestPasser(mws ...Middleware) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if len(mws) == 0 {
-		panicWithErr("%w", errNoMiddleware)
+		return newErr("%w", errNoMiddleware)
 	}
 
 	for i, mw := range mws {
 		if mw == nil {
-			panicWithErr("%w at index %d", errNoMiddleware, i)
+			return newErr("%w at index %d", errNoMiddleware, i)
 		}
 
 		rb.requestPasser = mw(rb.requestPasser)
+		rb.passerMws = append(rb.passerMws, mw)
 	}
+
+	return nil
 }
 
 // WrapRequestHandler wraps the responder's request handler with the middlewares
@@ -1592,8 +2212,21 @@ func (rb *_ResponderBase) WrapRequestPasser(mws ...Middleware) {
 // called only when the responder is the one to handle the request and has at
 // least one HTTP method handler.
 func (rb *_ResponderBase) WrapRequestHandler(mws ...Middleware) {
+	var err = rb.TryToWrapRequestHandler(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapRequestHandler is like WrapRequestHandler but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToWrapRequestHandler(mws ...Middleware) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if len(mws) == 0 {
-		panicWithErr("%w", errNoMiddleware)
+		return newErr("%w", errNoMiddleware)
 	}
 
 	if rb._RequestHandlerBase == nil {
@@ -1602,11 +2235,14 @@ func (rb *_ResponderBase) WrapRequestHandler(mws ...Middleware) {
 
 	for i, mw := range mws {
 		if mw == nil {
-			panicWithErr("%w at index %d", errNoMiddleware, i)
+			return newErr("%w at index %d", errNoMiddleware, i)
 		}
 
 		rb.requestHandler = mw(rb.requestHandler)
+		rb.handlerMws = append(rb.handlerMws, mw)
 	}
+
+	return nil
 }
 
 // WrapHandlerOf wraps the handlers of the HTTP methods with the middlewares in
@@ -1620,18 +2256,36 @@ func (rb *_ResponderBase) WrapRequestHandler(mws ...Middleware) {
 // handler and all the handlers of HTTP methods in use must happen in separate
 // calls. Examples of methods: "GET", "PUT POST", "SHARE, LOCK", "*" or "!".
 func (rb *_ResponderBase) WrapHandlerOf(methods string, mws ...Middleware) {
+	var err = rb.TryToWrapHandlerOf(methods, mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapHandlerOf is like WrapHandlerOf but returns an error instead of
+// panicking.
+func (rb *_ResponderBase) TryToWrapHandlerOf(
+	methods string,
+	mws ...Middleware,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if rb._RequestHandlerBase == nil {
 		if _, ok := rb.derived.(*Host); ok {
-			panicWithErr("%w", errDormantHost)
+			return newErr("%w", ErrDormantHost)
 		}
 
-		panicWithErr("%w", errDormantResource)
+		return newErr("%w", ErrDormantResource)
 	}
 
 	var err = rb.wrapHandlerOf(methods, mws...)
 	if err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
+
+	return nil
 }
 
 // -------------------------
@@ -1644,13 +2298,31 @@ func (rb *_ResponderBase) WrapHandlerOf(methods string, mws ...Middleware) {
 // HTTP method may change. For example, some clients change the POST HTTP
 // method to GET. The 308 status code does not allow this behavior. By default,
 // the 308 status code is sent.
+//
+// The code is inherited by the resources below in the tree that don't have
+// their own code.
 func (rb *_ResponderBase) SetPermanentRedirectCode(code int) {
+	var err = rb.TryToSetPermanentRedirectCode(code)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetPermanentRedirectCode is like SetPermanentRedirectCode but returns an
+// error instead of panicking.
+func (rb *_ResponderBase) TryToSetPermanentRedirectCode(code int) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if code != http.StatusMovedPermanently &&
 		code != http.StatusPermanentRedirect {
-		panicWithErr("%w", errConflictingStatusCode)
+		return newErr("%w", ErrConflictingStatusCode)
 	}
 
 	rb.permanentRedirectCode = code
+
+	return nil
 }
 
 // PermanentRedirectCode returns the responder's status code for permanent
@@ -1661,12 +2333,12 @@ func (rb *_ResponderBase) SetPermanentRedirectCode(code int) {
 // status code, the request's HTTP method may change. For example, some
 // clients change the POST HTTP method to GET. The 308 status code does
 // not allow this behavior. By default, the 308 status code is sent.
+//
+// If the responder doesn't have its own code, the code of its closest
+// ancestor (including the router) is returned. When none of them has
+// a code, the package's permanent redirect code is returned.
 func (rb *_ResponderBase) PermanentRedirectCode() int {
-	if rb.permanentRedirectCode > 0 {
-		return rb.permanentRedirectCode
-	}
-
-	return permanentRedirectCode
+	return permanentRedirectCodeOf(rb.derived)
 }
 
 // SetRedirectHandler can be used to set a custom implementation of the
@@ -1676,12 +2348,32 @@ func (rb *_ResponderBase) PermanentRedirectCode() int {
 // HTTP, to a URL with a trailing slash from a URL without, or vice versa.
 // It is also used when the responder has been configured to redirect requests
 // to a new location.
+//
+// The handler is inherited by the resources below in the tree that don't have
+// their own handler.
 func (rb *_ResponderBase) SetRedirectHandler(handler RedirectHandler) {
+	var err = rb.TryToSetRedirectHandler(handler)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetRedirectHandler is like SetRedirectHandler but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToSetRedirectHandler(
+	handler RedirectHandler,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if handler == nil {
-		panicWithErr("%w", errNilArgument)
+		return newErr("%w", ErrNilArgument)
 	}
 
 	rb.redirectHandler = handler
+
+	return nil
 }
 
 // RedirectHandler returns the redirect handler function of the responder.
@@ -1690,12 +2382,12 @@ func (rb *_ResponderBase) SetRedirectHandler(handler RedirectHandler) {
 // HTTP, to a URL with a trailing slash from a URL without, or vice versa.
 // It is also used when the responder has been configured to redirect requests
 // to a new location.
+//
+// If the responder doesn't have its own handler, the handler of its closest
+// ancestor (including the router) is returned. When none of them has a
+// handler, the common redirect handler is returned.
 func (rb *_ResponderBase) RedirectHandler() RedirectHandler {
-	if rb.redirectHandler == nil {
-		return commonRedirectHandler
-	}
-
-	return rb.redirectHandler
+	return redirectHandlerOf(rb.derived)
 }
 
 // WrapRedirectHandler wraps the redirect handler function with middlewares
@@ -1709,24 +2401,44 @@ func (rb *_ResponderBase) RedirectHandler() RedirectHandler {
 // an HTTP, to a URL with a trailing slash from a URL without, or vice versa.
 // It's also used when responder has been configured to redirect requests to
 // a new location.
+//
+// If the responder doesn't have its own handler, the inherited one is wrapped
+// and set as the responder's handler.
 func (rb *_ResponderBase) WrapRedirectHandler(
 	mws ...func(RedirectHandler) RedirectHandler,
 ) {
+	var err = rb.TryToWrapRedirectHandler(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapRedirectHandler is like WrapRedirectHandler but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToWrapRedirectHandler(
+	mws ...func(RedirectHandler) RedirectHandler,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if len(mws) == 0 {
-		panicWithErr("%w", errNoMiddleware)
+		return newErr("%w", errNoMiddleware)
 	}
 
 	if rb.redirectHandler == nil {
-		rb.redirectHandler = commonRedirectHandler
+		rb.redirectHandler = redirectHandlerOf(rb.derived)
 	}
 
 	for i, mw := range mws {
 		if mw == nil {
-			panicWithErr("%w at index %d", errNoMiddleware, i)
+			return newErr("%w at index %d", errNoMiddleware, i)
 		}
 
 		rb.redirectHandler = mw(rb.redirectHandler)
 	}
+
+	return nil
 }
 
 // RedirectRequestTo configures the responder to redirect requests to another
@@ -1748,12 +2460,31 @@ func (rb *_ResponderBase) WrapRedirectHandler(
 // 		http.StatusPermanentRedirect,
 // 	)
 func (rb *_ResponderBase) RedirectRequestTo(url string, redirectCode int) {
-	var requestRedirector, err = redirector(rb, url, redirectCode)
+	var err = rb.TryToRedirectRequestTo(url, redirectCode)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToRedirectRequestTo is like RedirectRequestTo but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToRedirectRequestTo(
+	url string,
+	redirectCode int,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var requestRedirector, err = redirector(rb, url, redirectCode)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
 	rb.requestRedirector = requestRedirector
+	rb.requestRedirectArgs = &_RedirectArgs{url, redirectCode}
+
+	return nil
 }
 
 // RedirectAnyRequestTo configures the responder to redirect requests to
@@ -1775,12 +2506,179 @@ func (rb *_ResponderBase) RedirectRequestTo(url string, redirectCode int) {
 // 		http.StatusPermanentRedirect,
 // 	)
 func (rb *_ResponderBase) RedirectAnyRequestTo(url string, redirectCode int) {
-	var anyRequestRedirector, err = redirector(rb, url, redirectCode)
+	var err = rb.TryToRedirectAnyRequestTo(url, redirectCode)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToRedirectAnyRequestTo is like RedirectAnyRequestTo but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToRedirectAnyRequestTo(
+	url string,
+	redirectCode int,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var anyRequestRedirector, err = redirector(rb, url, redirectCode)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
 	rb.requestReceiver = anyRequestRedirector
+	rb.anyRequestRedirectArgs = &_RedirectArgs{url, redirectCode}
+
+	return nil
+}
+
+// SetHandlerForNotFound sets the handler for not-found resources. The
+// handler is called when there is no resource in the responder's subtree
+// to match the request's path. It is inherited by the resources below in
+// the tree that don't have their own handler.
+//
+// Example:
+// 	var api = host.Resource("api")
+// 	api.SetHandlerForNotFound(JSONNotFound)
+func (rb *_ResponderBase) SetHandlerForNotFound(handler Handler) {
+	var err = rb.TryToSetHandlerForNotFound(handler)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetHandlerForNotFound is like SetHandlerForNotFound but returns an
+// error instead of panicking.
+func (rb *_ResponderBase) TryToSetHandlerForNotFound(handler Handler) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if handler == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	rb.notFoundHandler = handler
+	return nil
+}
+
+// HandlerOfNotFound returns the handler for not-found resources. If the
+// responder doesn't have its own handler, the handler of the closest
+// ancestor (including the router) is returned. When none of them has a
+// handler, the handler set with the package's SetHandlerForNotFound
+// function is returned. The returned handler marks the request as not found
+// in the Args, like when the router calls it.
+func (rb *_ResponderBase) HandlerOfNotFound() Handler {
+	var p = rb.derived
+	return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		return handleNotFound(p, w, r, args)
+	}
+}
+
+// WrapHandlerOfNotFound wraps the handler for not-found resources with the
+// middlewares in their passed order. If the responder doesn't have its own
+// handler, the inherited one is wrapped and set as the responder's handler.
+func (rb *_ResponderBase) WrapHandlerOfNotFound(mws ...Middleware) {
+	var err = rb.TryToWrapHandlerOfNotFound(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapHandlerOfNotFound is like WrapHandlerOfNotFound but returns an
+// error instead of panicking.
+func (rb *_ResponderBase) TryToWrapHandlerOfNotFound(mws ...Middleware) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if len(mws) == 0 {
+		return newErr("%w", errNoMiddleware)
+	}
+
+	var h = notFoundHandlerOf(rb.derived)
+	for i, mw := range mws {
+		if mw == nil {
+			return newErr("%w at index %d", ErrNilArgument, i)
+		}
+
+		h = mw(h)
+	}
+
+	rb.notFoundHandler = h
+	return nil
+}
+
+// SetCORSPolicy sets the CORS policy of the responder. The policy is
+// inherited by the resources below in the tree that don't have their own
+// policy. A policy without allowed origins turns off CORS for the subtree.
+//
+// The default OPTIONS handler answers the preflight requests using the
+// policy and the responder's HTTP methods. The responses to the actual
+// cross-origin requests are decorated with the policy's headers.
+func (rb *_ResponderBase) SetCORSPolicy(policy CORSPolicy) {
+	if err := rb.TryToSetCORSPolicy(policy); err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetCORSPolicy is like SetCORSPolicy but returns an error instead of
+// panicking.
+func (rb *_ResponderBase) TryToSetCORSPolicy(policy CORSPolicy) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if err := policy.validate(); err != nil {
+		return newErr("%w", err)
+	}
+
+	rb.corsPolicy = policy.clone()
+	return nil
+}
+
+// CORSPolicy returns a copy of the responder's CORS policy. If the responder
+// doesn't have its own policy, the policy of the closest ancestor (including
+// the router) is returned. When none of them has a policy, nil is returned.
+func (rb *_ResponderBase) CORSPolicy() *CORSPolicy {
+	if cp := corsPolicyOf(rb.derived); cp != nil {
+		return cp.clone()
+	}
+
+	return nil
+}
+
+// SetErrorRenderer sets the renderer of the errors returned from the
+// ErrHandlers of the responder. The renderer is inherited by the resources
+// below in the tree that don't have their own renderer.
+func (rb *_ResponderBase) SetErrorRenderer(renderer ErrorRenderer) {
+	if err := rb.TryToSetErrorRenderer(renderer); err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetErrorRenderer is like SetErrorRenderer but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToSetErrorRenderer(renderer ErrorRenderer) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if renderer == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	rb.errorRenderer = renderer
+	return nil
+}
+
+// ErrorRenderer returns the error renderer of the responder. If the responder
+// doesn't have its own renderer, the renderer of the closest ancestor
+// (including the router) is returned. When none of them has a renderer,
+// RenderErrorAsText is returned.
+func (rb *_ResponderBase) ErrorRenderer() ErrorRenderer {
+	return errorRendererOf(rb.derived)
 }
 
 // --------------------------------------------------
@@ -1795,8 +2693,29 @@ func (rb *_ResponderBase) SetSharedDataAt(
 	pathTmplStr string,
 	data interface{},
 ) {
-	var r = rb.Resource(pathTmplStr)
+	var err = rb.TryToSetSharedDataAt(pathTmplStr, data)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetSharedDataAt is like SetSharedDataAt but returns an error instead of
+// panicking.
+func (rb *_ResponderBase) TryToSetSharedDataAt(
+	pathTmplStr string,
+	data interface{},
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
 	r.SetSharedData(data)
+	return nil
 }
 
 // SharedDataAt returns the shared data of the existing resource at the path.
@@ -1807,7 +2726,7 @@ func (rb *_ResponderBase) SetSharedDataAt(
 func (rb *_ResponderBase) SharedDataAt(pathTmplStr string) interface{} {
 	var r = rb.RegisteredResource(pathTmplStr)
 	if r == nil {
-		panicWithErr("%w", errNonExistentResource)
+		panicWithErr("%w", ErrNonExistentResource)
 	}
 
 	return r.SharedData()
@@ -1837,13 +2756,41 @@ func (rb *_ResponderBase) SetConfigurationAt(
 	pathTmplStr string,
 	config Config,
 ) {
-	var r = rb.RegisteredResource(pathTmplStr)
+	var err = rb.TryToSetConfigurationAt(pathTmplStr, config)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetConfigurationAt is like SetConfigurationAt but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToSetConfigurationAt(
+	pathTmplStr string,
+	config Config,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToRegisteredResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
 	if r != nil {
-		r.SetConfiguration(config)
-		return
+		if err = r.TryToSetConfiguration(config); err != nil {
+			return newErr("%w", err)
+		}
+
+		return nil
+	}
+
+	_, err = rb.TryToResourceUsingConfig(pathTmplStr, config)
+	if err != nil {
+		return newErr("%w", err)
 	}
 
-	rb.ResourceUsingConfig(pathTmplStr, config)
+	return nil
 }
 
 // ConfigurationAt returns the configuration of the existing resource at the
@@ -1854,7 +2801,7 @@ func (rb *_ResponderBase) SetConfigurationAt(
 func (rb *_ResponderBase) ConfigurationAt(pathTmplStr string) Config {
 	var r = rb.RegisteredResource(pathTmplStr)
 	if r == nil {
-		panicWithErr("%w", errNonExistentResource)
+		panicWithErr("%w", ErrNonExistentResource)
 	}
 
 	return r.Configuration()
@@ -1871,9 +2818,34 @@ func (rb *_ResponderBase) ConfigurationAt(pathTmplStr string) Config {
 // compatible with the existing resource's properties. A newly created resource
 // is configured with the values in the path template.
 func (rb *_ResponderBase) SetImplementationAt(pathTmplStr string, rh Impl) {
-	var r = rb.Resource(pathTmplStr)
-	r.SetImplementation(rh)
-}
+	var err = rb.TryToSetImplementationAt(pathTmplStr, rh)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetImplementationAt is like SetImplementationAt but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToSetImplementationAt(
+	pathTmplStr string,
+	rh Impl,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToSetImplementation(rh)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
+}
 
 // ImplementationAt returns the implementation of the existing resource at the
 // path. If the resource wasn't created from an Impl or it has no Impl set, nil
@@ -1884,7 +2856,7 @@ func (rb *_ResponderBase) SetImplementationAt(pathTmplStr string, rh Impl) {
 func (rb *_ResponderBase) ImplementationAt(pathTmplStr string) Impl {
 	var r = rb.RegisteredResource(pathTmplStr)
 	if r == nil {
-		panicWithErr("%w", errNonExistentResource)
+		panicWithErr("%w", ErrNonExistentResource)
 	}
 
 	return r.Implementation()
@@ -1907,9 +2879,36 @@ func (rb *_ResponderBase) ImplementationAt(pathTmplStr string) Impl {
 func (rb *_ResponderBase) SetPathHandlerFor(
 	methods, pathTmplStr string,
 	handler Handler,
+	opts ...HandlerOption,
 ) {
-	var r = rb.Resource(pathTmplStr)
-	r.SetHandlerFor(methods, handler)
+	var err = rb.TryToSetPathHandlerFor(methods, pathTmplStr, handler, opts...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetPathHandlerFor is like SetPathHandlerFor but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToSetPathHandlerFor(
+	methods, pathTmplStr string,
+	handler Handler,
+	opts ...HandlerOption,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToSetHandlerFor(methods, handler, opts...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // PathHandlerOf returns the HTTP method handler of the existing resource at
@@ -1923,7 +2922,7 @@ func (rb *_ResponderBase) SetPathHandlerFor(
 func (rb *_ResponderBase) PathHandlerOf(method, pathTmplStr string) Handler {
 	var r = rb.RegisteredResource(pathTmplStr)
 	if r == nil {
-		panicWithErr("%w", errNonExistentResource)
+		panicWithErr("%w", ErrNonExistentResource)
 	}
 
 	return r.HandlerOf(method)
@@ -1947,8 +2946,33 @@ func (rb *_ResponderBase) WrapRequestPasserAt(
 	pathTmplStr string,
 	mws ...Middleware,
 ) {
-	var r = rb.Resource(pathTmplStr)
-	r.WrapRequestPasser(mws...)
+	var err = rb.TryToWrapRequestPasserAt(pathTmplStr, mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapRequestPasserAt is like WrapRequestPasserAt but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToWrapRequestPasserAt(
+	pathTmplStr string,
+	mws ...Middleware,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToWrapRequestPasser(mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // WrapRequestHandlerAt wraps the request handler of the resource at the path.
@@ -1967,8 +2991,33 @@ func (rb *_ResponderBase) WrapRequestHandlerAt(
 	pathTmplStr string,
 	mws ...Middleware,
 ) {
-	var r = rb.Resource(pathTmplStr)
-	r.WrapRequestHandler(mws...)
+	var err = rb.TryToWrapRequestHandlerAt(pathTmplStr, mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapRequestHandlerAt is like WrapRequestHandlerAt but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToWrapRequestHandlerAt(
+	pathTmplStr string,
+	mws ...Middleware,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToWrapRequestHandler(mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // WrapPathHandlerOf wraps the handlers of the HTTP methods of the existing
@@ -1989,12 +3038,37 @@ func (rb *_ResponderBase) WrapPathHandlerOf(
 	methods, pathTmplStr string,
 	mws ...Middleware,
 ) {
-	var r = rb.RegisteredResource(pathTmplStr)
+	var err = rb.TryToWrapPathHandlerOf(methods, pathTmplStr, mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapPathHandlerOf is like WrapPathHandlerOf but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToWrapPathHandlerOf(
+	methods, pathTmplStr string,
+	mws ...Middleware,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToRegisteredResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
 	if r == nil {
-		panicWithErr("%w", errNonExistentResource)
+		return newErr("%w", ErrNonExistentResource)
+	}
+
+	err = r.TryToWrapHandlerOf(methods, mws...)
+	if err != nil {
+		return newErr("%w", err)
 	}
 
-	r.WrapHandlerOf(methods, mws...)
+	return nil
 }
 
 // -------------------------
@@ -2017,8 +3091,33 @@ func (rb *_ResponderBase) SetPermanentRedirectCodeAt(
 	pathTmplStr string,
 	code int,
 ) {
-	var r = rb.Resource(pathTmplStr)
-	r.SetPermanentRedirectCode(code)
+	var err = rb.TryToSetPermanentRedirectCodeAt(pathTmplStr, code)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetPermanentRedirectCodeAt is like SetPermanentRedirectCodeAt but
+// returns an error instead of panicking.
+func (rb *_ResponderBase) TryToSetPermanentRedirectCodeAt(
+	pathTmplStr string,
+	code int,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToSetPermanentRedirectCode(code)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // PermanentRedirectCodeAt returns the status code of the existing resource
@@ -2037,14 +3136,10 @@ func (rb *_ResponderBase) SetPermanentRedirectCodeAt(
 func (rb *_ResponderBase) PermanentRedirectCodeAt(pathTmplStr string) int {
 	var r = rb.RegisteredResource(pathTmplStr)
 	if r == nil {
-		panicWithErr("%w", errNonExistentResource)
-	}
-
-	if r.permanentRedirectCode > 0 {
-		return r.permanentRedirectCode
+		panicWithErr("%w", ErrNonExistentResource)
 	}
 
-	return permanentRedirectCode
+	return r.PermanentRedirectCode()
 }
 
 // SetRedirectHandlerAt can be used to set a custom implementation of the
@@ -2063,8 +3158,33 @@ func (rb *_ResponderBase) SetRedirectHandlerAt(
 	pathTmplStr string,
 	handler RedirectHandler,
 ) {
-	var r = rb.Resource(pathTmplStr)
-	r.SetRedirectHandler(handler)
+	var err = rb.TryToSetRedirectHandlerAt(pathTmplStr, handler)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetRedirectHandlerAt is like SetRedirectHandlerAt but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToSetRedirectHandlerAt(
+	pathTmplStr string,
+	handler RedirectHandler,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToSetRedirectHandler(handler)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // RedirectHandlerAt returns the redirect handler function of the existing
@@ -2082,14 +3202,10 @@ func (rb *_ResponderBase) RedirectHandlerAt(
 ) RedirectHandler {
 	var r = rb.RegisteredResource(pathTmplStr)
 	if r == nil {
-		panicWithErr("%w", errNonExistentResource)
-	}
-
-	if r.redirectHandler == nil {
-		return commonRedirectHandler
+		panicWithErr("%w", ErrNonExistentResource)
 	}
 
-	return r.redirectHandler
+	return r.RedirectHandler()
 }
 
 // WrapRedirectHandlerAt wraps the redirect handler of the resource at the
@@ -2112,8 +3228,33 @@ func (rb *_ResponderBase) WrapRedirectHandlerAt(
 	pathTmplStr string,
 	mws ...func(RedirectHandler) RedirectHandler,
 ) {
-	var r = rb.Resource(pathTmplStr)
-	r.WrapRedirectHandler(mws...)
+	var err = rb.TryToWrapRedirectHandlerAt(pathTmplStr, mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapRedirectHandlerAt is like WrapRedirectHandlerAt but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToWrapRedirectHandlerAt(
+	pathTmplStr string,
+	mws ...func(RedirectHandler) RedirectHandler,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToWrapRedirectHandler(mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // RedirectRequestAt configures the resource at the path to redirect requests
@@ -2144,8 +3285,34 @@ func (rb *_ResponderBase) RedirectRequestAt(
 	url string,
 	redirectCode int,
 ) {
-	var r = rb.Resource(pathTmplStr)
-	r.RedirectRequestTo(url, redirectCode)
+	var err = rb.TryToRedirectRequestAt(pathTmplStr, url, redirectCode)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToRedirectRequestAt is like RedirectRequestAt but returns an error instead
+// of panicking.
+func (rb *_ResponderBase) TryToRedirectRequestAt(
+	pathTmplStr,
+	url string,
+	redirectCode int,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToRedirectRequestTo(url, redirectCode)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // RedirectAnyRequestAt configures the resource at the path to redirect
@@ -2175,8 +3342,34 @@ func (rb *_ResponderBase) RedirectAnyRequestAt(
 	url string,
 	redirectCode int,
 ) {
-	var r = rb.Resource(pathTmplStr)
-	r.RedirectAnyRequestTo(url, redirectCode)
+	var err = rb.TryToRedirectAnyRequestAt(pathTmplStr, url, redirectCode)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToRedirectAnyRequestAt is like RedirectAnyRequestAt but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToRedirectAnyRequestAt(
+	pathTmplStr,
+	url string,
+	redirectCode int,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var r, err = rb.TryToResource(pathTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = r.TryToRedirectAnyRequestTo(url, redirectCode)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // --------------------------------------------------
@@ -2184,6 +3377,10 @@ func (rb *_ResponderBase) RedirectAnyRequestAt(
 // SetSharedDataForSubtree sets the shared data for each resource in
 // the subtree that has no shared data set yet.
 func (rb *_ResponderBase) SetSharedDataForSubtree(data interface{}) {
+	if err := rb.checkChangeable(); err != nil {
+		panicWithErr("%w", err)
+	}
+
 	traverseAndCall(
 		rb._Responders(),
 		func(_r _Responder) error {
@@ -2202,18 +3399,26 @@ func (rb *_ResponderBase) SetSharedDataForSubtree(data interface{}) {
 // properties other than security and trailing slash, those resources will be
 // skipped.
 func (rb *_ResponderBase) SetConfigurationForSubtree(config Config) {
-	traverseAndCall(
-		rb._Responders(),
-		func(_r _Responder) error {
-			var cfs = _r.configFlags()
-			if (cfs &^ (flagActive | flagSecure | flagTrailingSlash)) > 0 {
-				return nil
-			}
+	var err = rb.TryToSetConfigurationForSubtree(config)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
 
-			_r.SetConfiguration(config)
-			return nil
-		},
-	)
+// TryToSetConfigurationForSubtree is like SetConfigurationForSubtree but
+// returns an error instead of panicking. The resources configured before the
+// error keep their new configuration.
+func (rb *_ResponderBase) TryToSetConfigurationForSubtree(config Config) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var err = setConfigurationOfEvery(rb._Responders(), config)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // WrapSubtreeRequestPassers wraps the request passers of the resources in
@@ -2227,13 +3432,27 @@ func (rb *_ResponderBase) SetConfigurationForSubtree(config Config) {
 func (rb *_ResponderBase) WrapSubtreeRequestPassers(
 	mws ...Middleware,
 ) {
-	traverseAndCall(
-		rb._Responders(),
-		func(_r _Responder) error {
-			_r.WrapRequestPasser(mws...)
-			return nil
-		},
-	)
+	var err = rb.TryToWrapSubtreeRequestPassers(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapSubtreeRequestPassers is like WrapSubtreeRequestPassers but returns
+// an error instead of panicking.
+func (rb *_ResponderBase) TryToWrapSubtreeRequestPassers(
+	mws ...Middleware,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var err = wrapEveryRequestPasser(rb._Responders(), mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // WrapSubtreeRequestHandlers wraps the request handlers of the resources in
@@ -2247,13 +3466,27 @@ func (rb *_ResponderBase) WrapSubtreeRequestPassers(
 func (rb *_ResponderBase) WrapSubtreeRequestHandlers(
 	mws ...Middleware,
 ) {
-	traverseAndCall(
-		rb._Responders(),
-		func(_r _Responder) error {
-			_r.WrapRequestHandler(mws...)
-			return nil
-		},
-	)
+	var err = rb.TryToWrapSubtreeRequestHandlers(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapSubtreeRequestHandlers is like WrapSubtreeRequestHandlers but
+// returns an error instead of panicking.
+func (rb *_ResponderBase) TryToWrapSubtreeRequestHandlers(
+	mws ...Middleware,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var err = wrapEveryRequestHandler(rb._Responders(), mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // WrapSubtreeHandlersOf wraps the HTTP method handlers of the resources in
@@ -2269,12 +3502,30 @@ func (rb *_ResponderBase) WrapSubtreeHandlersOf(
 	methods string,
 	mws ...Middleware,
 ) {
-	var err = wrapEveryHandlerOf(methods, rb._Responders(), mws...)
+	var err = rb.TryToWrapSubtreeHandlersOf(methods, mws...)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
 }
 
+// TryToWrapSubtreeHandlersOf is like WrapSubtreeHandlersOf but returns an error
+// instead of panicking.
+func (rb *_ResponderBase) TryToWrapSubtreeHandlersOf(
+	methods string,
+	mws ...Middleware,
+) error {
+	if err := rb.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var err = wrapEveryHandlerOf(methods, rb._Responders(), mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
+}
+
 // -------------------------
 
 // _Responders returns all the direct child resources.
@@ -2287,9 +3538,267 @@ func (rb *_ResponderBase) _Responders() []_Responder {
 	return rhs
 }
 
+// caseInsensitiveStaticResource returns the case-insensitive static child
+// resource whose template matches the path segment regardless of case, and
+// the resource's key in the static resources.
+func (rb *_ResponderBase) caseInsensitiveStaticResource(ps string) (
+	string,
+	*Resource,
+) {
+	if rb.caseInsensitiveResources == nil {
+		return "", nil
+	}
+
+	var sr = rb.caseInsensitiveResources[strings.ToLower(ps)]
+	if sr == nil {
+		return "", nil
+	}
+
+	return sr.Template().UnescapedContent(), sr
+}
+
+// indexCaseInsensitiveResources rebuilds the index of the case-insensitive
+// static child resources. If a case-insensitive child resource's template
+// collides with the template of another static child resource regardless of
+// case, the index is still rebuilt, but an error is returned.
+func (rb *_ResponderBase) indexCaseInsensitiveResources() error {
+	rb.caseInsensitiveResources = nil
+	for k, sr := range rb.staticResources {
+		if !sr.IsCaseInsensitive() {
+			continue
+		}
+
+		if rb.caseInsensitiveResources == nil {
+			rb.caseInsensitiveResources = make(map[string]*Resource)
+		}
+
+		rb.caseInsensitiveResources[strings.ToLower(k)] = sr
+	}
+
+	if rb.caseInsensitiveResources == nil {
+		return nil
+	}
+
+	for k, sr := range rb.staticResources {
+		var ir = rb.caseInsensitiveResources[strings.ToLower(k)]
+		if ir != nil && ir != sr {
+			return newRegistrationErr(
+				"register",
+				ir.Template().String(),
+				sr,
+				ErrConflictingPathSegment,
+			)
+		}
+	}
+
+	return nil
+}
+
+// hasChildResourceFor returns true if one of the static or pattern child
+// resources matches the path segment.
+func (rb *_ResponderBase) hasChildResourceFor(ps string) bool {
+	var cr, _, _ = rb.childResourceFor(ps, nil)
+	return cr != nil
+}
+
+// childResourceFor returns the static or pattern child resource that matches
+// the path segment the same way the passRequest method matches it. The values
+// of the pattern resource are added to the argument values. If the resource
+// redirects to its canonical case and the path segment is not in it, the
+// path segment in the canonical case is also returned.
+func (rb *_ResponderBase) childResourceFor(
+	ps string,
+	values HostPathValues,
+) (*Resource, string, HostPathValues) {
+	if sr := rb.staticResources[ps]; sr != nil {
+		return sr, "", values
+	}
+
+	if k, sr := rb.caseInsensitiveStaticResource(ps); sr != nil {
+		if sr.RedirectsToCanonicalCase() {
+			return sr, k, values
+		}
+
+		return sr, "", values
+	}
+
+	for _, pr := range rb.patternResources {
+		var matched bool
+		var ptmpl = pr.Template()
+		matched, values = ptmpl.match(ps, values, pr.IsCaseInsensitive())
+		if !matched {
+			continue
+		}
+
+		if pr.RedirectsToCanonicalCase() {
+			var cps, err = ptmpl.TryToApply(values, false)
+			if err == nil && cps != ps {
+				return pr, cps, values
+			}
+		}
+
+		return pr, "", values
+	}
+
+	return nil, "", values
+}
+
+// passRequestToOptionalResource passes the request to the optional wildcard
+// child resource when the request's URL doesn't have the optional segment.
+// The segment gets its default value. If the responder doesn't have an
+// optional wildcard child resource, false is returned.
+func (rb *_ResponderBase) passRequestToOptionalResource(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+) bool {
+	var wr = rb.wildcardResource
+	if wr == nil || !wr.Template().IsOptional() {
+		return false
+	}
+
+	var wtmpl = wr.Template()
+	args.hostPathValues.Set(
+		wtmpl.slices[0].valuePattern.name,
+		wtmpl.DefaultValue(),
+	)
+
+	args._r = wr.derived
+	args.matchedResponder(r, "")
+	return wr.requestReceiver(w, r, args)
+}
+
+// requestPasserOf returns the request passer of the responder. When the
+// request in the args is being resolved, the passer without the middlewares
+// is returned.
+func (rb *_ResponderBase) requestPasserOf(args *Args) Handler {
+	if args.resolution != nil {
+		return rb.passRequest
+	}
+
+	return rb.requestPasser
+}
+
+// commonRedirectURL returns the URL to redirect the request to when it was
+// made over HTTP to a secure responder, when its path was cleaned, or when
+// one of its path segments was matched regardless of case by a resource
+// that redirects to the canonical case. If the request doesn't need to be
+// redirected, nil is returned. The returned bool is false when the
+// responder must not respond to the insecure request.
+func (rb *_ResponderBase) commonRedirectURL(
+	r *http.Request,
+	args *Args,
+) (*url.URL, bool) {
+	var newURL *url.URL
+	if r.TLS == nil && rb.IsSecure() {
+		if !rb.RedirectsInsecureRequest() {
+			args.explainNotFound("", reasonInsecureRequest, nil)
+			return nil, false
+		}
+
+		newURL = cloneRequestURL(r)
+		newURL.Scheme = "https"
+		args.explainRedirect(reasonInsecureRequest)
+	}
+
+	// If the path was cleaned and the responder doesn't allow unclean paths,
+	// then the request will be redirected.
+	if args.cleanPath && !rb.IsLenientOnUncleanPath() {
+		if newURL == nil {
+			newURL = cloneRequestURL(r)
+		}
+
+		newURL.Path = args.path
+		args.explainRedirect(reasonUncleanPath)
+	}
+
+	// If one of the path segments was matched regardless of case by a
+	// resource that redirects to the canonical case, the request will be
+	// redirected.
+	if args.canonicalPath {
+		if newURL == nil {
+			newURL = cloneRequestURL(r)
+		}
+
+		newURL.Path = args.path
+		args.explainRedirect(reasonCanonicalCase)
+	}
+
+	return newURL, true
+}
+
+// trailingSlashRedirectURL updates the new URL, or a clone of the request's
+// URL if the new URL is nil, when the presence of the trailing slash in the
+// request's path doesn't match the responder's. The returned bool is false
+// when the responder is strict on the trailing slash and must not respond to
+// the request.
+func (rb *_ResponderBase) trailingSlashRedirectURL(
+	newURL *url.URL,
+	r *http.Request,
+	args *Args,
+) (*url.URL, bool) {
+	if rb.HasTrailingSlash() == args.pathHasTrailingSlash() {
+		return newURL, true
+	}
+
+	if rb.IsStrictOnTrailingSlash() {
+		args.explainNotFound("", reasonTrailingSlash, nil)
+		return nil, false
+	}
+
+	if newURL == nil {
+		newURL = cloneRequestURL(r)
+	}
+
+	if rb.HasTrailingSlash() {
+		newURL.Path += "/"
+	} else {
+		newURL.Path = newURL.Path[:len(newURL.Path)-1]
+	}
+
+	args.explainRedirect(reasonTrailingSlash)
+	return newURL, true
+}
+
+// redirectOrHandle redirects the request to the new URL if it's not nil.
+// Otherwise, the request is passed to the responder's request redirector,
+// if there is one, or to its request handler.
+func (rb *_ResponderBase) redirectOrHandle(
+	newURL *url.URL,
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+) bool {
+	if newURL != nil {
+		if newURL.Scheme == "" {
+			if r.TLS == nil {
+				newURL.Scheme = "http"
+			} else {
+				newURL.Scheme = "https"
+			}
+		}
+
+		var prc = permanentRedirectCodeOf(rb.derived)
+		return handleRedirect(rb.derived, w, r, newURL.String(), prc, args)
+	}
+
+	if rb.requestRedirector != nil {
+		return rb.requestRedirector(w, r, args)
+	}
+
+	if args.resolution != nil {
+		return args.resolution.handle(rb, r, args)
+	}
+
+	r = args.traceHandler(r)
+	var handled = rb.requestHandler(w, r, args)
+	return renderRecordedErr(w, r, args, handled)
+}
+
 func (rb *_ResponderBase) setRequestHandlerBase(rhb *_RequestHandlerBase) {
 	rb._RequestHandlerBase = rhb
 	rb.requestHandler = rhb.handleRequest
+	rb.handlerMws = nil
 }
 
 func (rb *_ResponderBase) requestHandlerBase() *_RequestHandlerBase {
@@ -2310,59 +3819,72 @@ func (rb *_ResponderBase) passRequest(
 	var currentPathSegmentIdx = args.currentPathSegmentIdx
 	var ps, err = args.nextPathSegment()
 	if err != nil {
-		http.Error(
-			w,
-			http.StatusText(http.StatusBadRequest),
-			http.StatusBadRequest,
-		)
-
+		handleBadRequest(w, r, args, err)
 		args.handled = true
 		args.currentPathSegmentIdx = currentPathSegmentIdx
 		return args.handled
 	}
 
 	if len(ps) > 0 {
-		if sr := rb.staticResources[ps]; sr != nil {
-			args._r = sr.derived
-			args.handled = sr.requestReceiver(w, r, args)
+		var cr *Resource
+		var cps string
+		cr, cps, args.hostPathValues = rb.childResourceFor(
+			ps,
+			args.hostPathValues,
+		)
+
+		if cr != nil {
+			if cps != "" {
+				args.replacePathSegment(currentPathSegmentIdx, cps)
+			}
+
+			args._r = cr.derived
+			args.matchedResponder(r, ps)
+			args.handled = cr.requestReceiver(w, r, args)
 			args.currentPathSegmentIdx = currentPathSegmentIdx
 			return args.handled
 		}
 
-		for _, pr := range rb.patternResources {
-			var matched bool
-			matched, args.hostPathValues = pr.Template().Match(
-				ps,
-				args.hostPathValues,
-			)
-
-			if matched {
-				args._r = pr.derived
-				args.handled = pr.requestReceiver(w, r, args)
+		if rb.wildcardResource != nil {
+			var wtmpl = rb.wildcardResource.Template()
+			if wtmpl.IsOptional() &&
+				rb.wildcardResource.hasChildResourceFor(ps) {
+				// The optional segment is missing in the request's URL. The
+				// path segment is passed to the wildcard resource's child
+				// resources, and the segment gets its default value.
+				args.currentPathSegmentIdx = currentPathSegmentIdx
+				args.handled = rb.passRequestToOptionalResource(w, r, args)
 				args.currentPathSegmentIdx = currentPathSegmentIdx
 				return args.handled
 			}
-		}
 
-		if rb.wildcardResource != nil {
-			_, args.hostPathValues = rb.wildcardResource.Template().Match(
-				ps,
-				args.hostPathValues,
-			)
+			if wtmpl.IsCatchAll() {
+				ps, err = args.pathSegmentsFrom(currentPathSegmentIdx)
+				if err != nil {
+					handleBadRequest(w, r, args, err)
+					args.handled = true
+					args.currentPathSegmentIdx = currentPathSegmentIdx
+					return args.handled
+				}
+			}
+
+			_, args.hostPathValues = wtmpl.Match(ps, args.hostPathValues)
 
 			args._r = rb.wildcardResource.derived
+			args.matchedResponder(r, ps)
 			args.handled = rb.wildcardResource.requestReceiver(w, r, args)
 			args.currentPathSegmentIdx = currentPathSegmentIdx
 			return args.handled
 		}
 	}
 
+	args.explainNotFound(ps, reasonNoMatchingResource, rb.childTemplates)
 	if args.subtreeExists {
 		args.currentPathSegmentIdx = currentPathSegmentIdx
 		return false
 	}
 
-	args.handled = notFoundResourceHandler(w, r, args)
+	args.handled = handleNotFound(rb.derived, w, r, args)
 	args.currentPathSegmentIdx = currentPathSegmentIdx
 	return args.handled
 }
@@ -0,0 +1,375 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// --------------------------------------------------
+
+// Registry maps the names used in the route configuration to the handlers
+// and middlewares.
+type Registry struct {
+	Handlers    map[string]Handler
+	Middlewares map[string]Middleware
+}
+
+// LoadError is the error of the route configuration with its position in
+// the configuration data.
+type LoadError struct {
+	Line, Column int
+	Err          error
+}
+
+func (le *LoadError) Error() string {
+	return fmt.Sprintf("%d:%d: %v", le.Line, le.Column, le.Err)
+}
+
+// Unwrap returns the underlying error.
+func (le *LoadError) Unwrap() error {
+	return le.Err
+}
+
+// LoadErrors is returned from the LoadRoutes method when the route
+// configuration contains errors.
+type LoadErrors []*LoadError
+
+func (les LoadErrors) Error() string {
+	var strb strings.Builder
+	for i, le := range les {
+		if i > 0 {
+			strb.WriteByte('\n')
+		}
+
+		strb.WriteString(le.Error())
+	}
+
+	return strb.String()
+}
+
+// --------------------------------------------------
+
+// _RouteSpec is a route in the route configuration.
+type _RouteSpec struct {
+	URL           string              `json:"url"`
+	Config        *Config             `json:"config"`
+	Handlers      map[string]string   `json:"handlers"`
+	Middlewares   map[string][]string `json:"middlewares"`
+	RedirectTo    *_RedirectSpec      `json:"redirectTo"`
+	RedirectAnyTo *_RedirectSpec      `json:"redirectAnyTo"`
+
+	line, column int
+}
+
+type _RedirectSpec struct {
+	URL  string `json:"url"`
+	Code int    `json:"code"`
+}
+
+// LoadRoutes reads the route configuration in JSON from the reader and
+// loads its routes into the router. The handlers and middlewares are bound
+// to the routes by their names in the registry.
+//
+// The configuration is an object with the "routes" array. Each route has
+// the "url" template of the host or resource. Optionally, it can have the
+// "config" object with the fields of the Config, the "handlers" object that
+// maps HTTP methods to handler names, the "middlewares" object that maps
+// HTTP methods to the names of the middlewares that wrap their handlers, and
+// the "redirectTo" or "redirectAnyTo" objects with the "url" and "code" of
+// the redirect. The keys of the "handlers" and "middlewares" objects are
+// used as the methods argument of the SetURLHandlerFor and WrapURLHandlerOf
+// methods, respectively.
+//
+// Example:
+// 	{
+// 		"routes": [
+// 			{
+// 				"url": "https://example.com/users/{id:int}",
+// 				"config": {"handlesHeadWithGet": true},
+// 				"handlers": {"GET": "getUser", "PUT": "putUser"},
+// 				"middlewares": {"*": ["logger"]}
+// 			},
+// 			{
+// 				"url": "https://example.com/people",
+// 				"redirectAnyTo": {"url": "https://example.com/users", "code": 308}
+// 			}
+// 		]
+// 	}
+//
+// The routes are validated with the same rules as the router's other
+// methods. All the errors are reported as LoadErrors with their positions
+// in the configuration. The routes are loaded with the Update method, so the
+// requests see either none or all of them. If there is an error, none of the
+// routes are loaded, and the router's tree is left as it was.
+func (ro *Router) LoadRoutes(r io.Reader, registry *Registry) error {
+	if r == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	if registry == nil {
+		registry = &Registry{}
+	}
+
+	var data, err = io.ReadAll(r)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	var specs, errs = parseRouteSpecs(data)
+	return ro.Update(func(tx *RouterTx) error {
+		for _, spec := range specs {
+			for _, err := range tx.loadRoute(spec, registry) {
+				errs = append(
+					errs,
+					&LoadError{Line: spec.line, Column: spec.column, Err: err},
+				)
+			}
+		}
+
+		if len(errs) > 0 {
+			sort.SliceStable(errs, func(i, j int) bool {
+				if errs[i].Line != errs[j].Line {
+					return errs[i].Line < errs[j].Line
+				}
+
+				return errs[i].Column < errs[j].Column
+			})
+
+			return errs
+		}
+
+		return nil
+	})
+}
+
+// loadRoute loads the route into the router and returns its errors.
+func (ro *Router) loadRoute(spec *_RouteSpec, registry *Registry) []error {
+	if spec.URL == "" {
+		return []error{newErr("%w: missing url", errInvalidArgument)}
+	}
+
+	if spec.RedirectTo != nil && spec.RedirectAnyTo != nil {
+		return []error{
+			newErr(
+				"%w: both redirectTo and redirectAnyTo are set",
+				errInvalidArgument,
+			),
+		}
+	}
+
+	var err error
+	if spec.Config != nil {
+		err = ro.TryToSetConfigurationAt(spec.URL, *spec.Config)
+	} else {
+		_, err = ro._Responder(spec.URL)
+	}
+
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, methods := range sortedKeys(spec.Handlers) {
+		var name = spec.Handlers[methods]
+		var h = registry.Handlers[name]
+		if h == nil {
+			errs = append(errs, newErr("%w %q", ErrUnknownHandler, name))
+			continue
+		}
+
+		err = ro.TryToSetURLHandlerFor(methods, spec.URL, h)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, methods := range sortedKeys(spec.Middlewares) {
+		var mws []Middleware
+		for _, name := range spec.Middlewares[methods] {
+			var mw = registry.Middlewares[name]
+			if mw == nil {
+				errs = append(
+					errs,
+					newErr("%w %q", ErrUnknownMiddleware, name),
+				)
+				continue
+			}
+
+			mws = append(mws, mw)
+		}
+
+		if len(mws) == 0 {
+			continue
+		}
+
+		err = ro.TryToWrapURLHandlerOf(methods, spec.URL, mws...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if rs := spec.RedirectTo; rs != nil {
+		err = ro.TryToRedirectRequestAt(spec.URL, rs.URL, rs.Code)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if rs := spec.RedirectAnyTo; rs != nil {
+		err = ro.TryToRedirectAnyRequestAt(spec.URL, rs.URL, rs.Code)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// --------------------------------------------------
+
+// parseRouteSpecs parses the route configuration. The routes that can't be
+// decoded are reported as errors with their positions.
+func parseRouteSpecs(data []byte) ([]*_RouteSpec, LoadErrors) {
+	var errs LoadErrors
+	var addErr = func(offset int64, err error) {
+		var l, c = lineAndColumn(data, offset)
+		errs = append(errs, &LoadError{Line: l, Column: c, Err: err})
+	}
+
+	var dec = json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var expectDelim = func(d json.Delim) bool {
+		var offset = skipSpaces(data, dec.InputOffset())
+		var t, err = dec.Token()
+		if err != nil {
+			addErr(syntaxErrOffset(err, offset), err)
+			return false
+		}
+
+		if t != d {
+			addErr(offset, newErr("%w: expected %q", errInvalidArgument, d))
+			return false
+		}
+
+		return true
+	}
+
+	if !expectDelim('{') {
+		return nil, errs
+	}
+
+	var specs []*_RouteSpec
+	for dec.More() {
+		var offset = skipSpaces(data, dec.InputOffset())
+		var t, err = dec.Token()
+		if err != nil {
+			addErr(syntaxErrOffset(err, offset), err)
+			return nil, errs
+		}
+
+		if t != "routes" {
+			addErr(offset, newErr("%w: unknown field %v", errInvalidArgument, t))
+
+			var v json.RawMessage
+			if err = dec.Decode(&v); err != nil {
+				addErr(syntaxErrOffset(err, offset), err)
+				return nil, errs
+			}
+
+			continue
+		}
+
+		if !expectDelim('[') {
+			return nil, errs
+		}
+
+		for dec.More() {
+			offset = skipSpaces(data, dec.InputOffset())
+
+			var spec = &_RouteSpec{}
+			err = dec.Decode(spec)
+			if err != nil {
+				addErr(syntaxErrOffset(err, offset), err)
+
+				var se *json.SyntaxError
+				if errors.As(err, &se) {
+					return nil, errs
+				}
+
+				continue
+			}
+
+			spec.line, spec.column = lineAndColumn(data, offset)
+			specs = append(specs, spec)
+		}
+
+		if !expectDelim(']') {
+			return nil, errs
+		}
+	}
+
+	if !expectDelim('}') {
+		return nil, errs
+	}
+
+	return specs, errs
+}
+
+// syntaxErrOffset returns the offset of the byte that caused the syntax
+// error. For other errors, the passed offset is returned.
+func syntaxErrOffset(err error, offset int64) int64 {
+	var se *json.SyntaxError
+	if errors.As(err, &se) && se.Offset > 0 {
+		// The syntax error's offset is after the byte that caused it.
+		return se.Offset - 1
+	}
+
+	return offset
+}
+
+// skipSpaces returns the offset of the first byte starting from the offset
+// that is not a space or a comma.
+func skipSpaces(data []byte, offset int64) int64 {
+	for ; offset < int64(len(data)); offset++ {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			continue
+		}
+
+		break
+	}
+
+	return offset
+}
+
+// lineAndColumn returns the 1-based line and column of the offset.
+func lineAndColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	var line = 1 + bytes.Count(data[:offset], []byte{'\n'})
+	var column = int(offset) - bytes.LastIndexByte(data[:offset], '\n')
+	return line, column
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	var keys = make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
@@ -0,0 +1,237 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// --------------------------------------------------
+
+func TestRouter_LoadRoutes(t *testing.T) {
+	var writer = func(body string) Handler {
+		return func(w http.ResponseWriter, r *http.Request, _ *Args) bool {
+			w.Write([]byte(body))
+			return true
+		}
+	}
+
+	var registry = &Registry{
+		Handlers: map[string]Handler{
+			"getUser": writer("user"),
+			"putUser": writer("updated"),
+			"index":   writer("index"),
+		},
+		Middlewares: map[string]Middleware{
+			"tag": func(next Handler) Handler {
+				return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+					w.Header().Set("X-Tag", "true")
+					return next(w, r, args)
+				}
+			},
+		},
+	}
+
+	var ro = NewRouter()
+	var err = ro.LoadRoutes(strings.NewReader(`{
+	"routes": [
+		{
+			"url": "http://example.com/users/{id:int}",
+			"config": {"handlesHeadWithGet": true},
+			"handlers": {"GET": "getUser", "PUT": "putUser"},
+			"middlewares": {"GET": ["tag"]}
+		},
+		{"url": "http://example.com/", "handlers": {"get": "index"}},
+		{
+			"url": "http://example.com/people",
+			"redirectAnyTo": {"url": "http://example.com/users", "code": 308}
+		}
+	]
+}`), registry)
+
+	checkErr(t, err, false)
+
+	var cases = []struct {
+		method, url    string
+		wantStatusCode int
+		wantBody       string
+		wantTag        string
+	}{
+		{"GET", "http://example.com/users/1", http.StatusOK, "user", "true"},
+		{"PUT", "http://example.com/users/1", http.StatusOK, "updated", ""},
+		{"HEAD", "http://example.com/users/1", http.StatusOK, "", "true"},
+		{"GET", "http://example.com", http.StatusOK, "index", ""},
+		{
+			"GET", "http://example.com/people/1",
+			http.StatusPermanentRedirect, "", "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.method+" "+c.url, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			var r = httptest.NewRequest(c.method, c.url, nil)
+			ro.ServeHTTP(w, r)
+			checkValue(t, w.Code, c.wantStatusCode)
+			checkValue(t, w.Header().Get("X-Tag"), c.wantTag)
+			if c.wantBody != "" {
+				checkValue(t, w.Body.String(), c.wantBody)
+			}
+		})
+	}
+
+	// Errors.
+	var lenBefore = len(ro._Responders())
+	err = ro.LoadRoutes(strings.NewReader(`{"routes": [
+	{"url": "http://example.org/a", "handlers": {"GET": "getUser"}},
+	{"url": "http://example.org/b", "handlers": {"GET": "missing"}},
+	{"url": "http://example.org/c", "middlewares": {"*": ["missing"]}},
+	{"url": "http://example.org/d", "unknown": true},
+	{"url": "http://example.org/{a}{b}"},
+	{"handlers": {"GET": "index"}},
+	{"url": 1}
+]}`), registry)
+
+	checkErr(t, err, true)
+
+	var les LoadErrors
+	if !errors.As(err, &les) {
+		t.Fatalf("LoadRoutes: err = %T, want LoadErrors", err)
+	}
+
+	var wantPositions = [][2]int{{3, 2}, {4, 2}, {5, 2}, {6, 2}, {7, 2}, {8, 2}}
+	checkValue(t, len(les), len(wantPositions))
+	for i, le := range les {
+		if i >= len(wantPositions) {
+			break
+		}
+
+		checkValue(t, [2]int{le.Line, le.Column}, wantPositions[i])
+	}
+
+	checkValue(t, strings.Count(err.Error(), "\n"), len(les)-1)
+	checkValue(t, errors.Is(les[0], ErrUnknownHandler), true)
+	checkValue(t, errors.Is(les[1], ErrUnknownMiddleware), true)
+
+	// The router's tree must be left as it was.
+	checkValue(t, len(ro._Responders()), lenBefore)
+
+	// Syntax errors.
+	err = ro.LoadRoutes(strings.NewReader("{\"routes\": [\n\t{\"url\": }\n]}"), nil)
+	if !errors.As(err, &les) {
+		t.Fatalf("LoadRoutes: err = %T, want LoadErrors", err)
+	}
+
+	checkValue(t, len(les), 1)
+	checkValue(t, [2]int{les[0].Line, les[0].Column}, [2]int{2, 10})
+
+	err = ro.LoadRoutes(strings.NewReader(`[]`), nil)
+	checkErr(t, err, true)
+
+	err = ro.LoadRoutes(strings.NewReader(`{"routes": []}`), nil)
+	checkErr(t, err, false)
+
+	testPanicker(t, false, func() {
+		checkErr(t, ro.LoadRoutes(nil, nil), true)
+	})
+}
+
+func TestRouter_LoadRoutes_concurrently(t *testing.T) {
+	// The test must be run with the -race flag to be useful.
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("get", "/static", textHandler("static"))
+
+	var registry = &Registry{
+		Handlers: map[string]Handler{
+			"a": textHandler("a"),
+			"b": textHandler("b"),
+		},
+	}
+
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				var w = serve(ro, "GET", "/static")
+				if w.Body.String() != "static" {
+					t.Errorf("static resource wasn't served")
+					return
+				}
+
+				// The routes are visible all together or not at all.
+				var a = serve(ro, "GET", "/a").Body.String()
+				var b = serve(ro, "GET", "/b").Body.String()
+				if a == "a" && b != "b" {
+					t.Errorf("partially loaded routes were served")
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		var err = ro.LoadRoutes(strings.NewReader(`{
+	"routes": [
+		{"url": "/b", "handlers": {"get": "b"}},
+		{"url": "/a", "handlers": {"get": "a"}},
+		{"url": "/c", "handlers": {"get": "unknown"}}
+	]
+}`), registry)
+
+		checkErr(t, err, true)
+	}
+
+	var err = ro.LoadRoutes(strings.NewReader(`{
+	"routes": [
+		{"url": "/b", "handlers": {"get": "b"}},
+		{"url": "/a", "handlers": {"get": "a"}}
+	]
+}`), registry)
+
+	checkErr(t, err, false)
+
+	close(done)
+	wg.Wait()
+
+	checkValue(t, serve(ro, "GET", "/a").Body.String(), "a")
+	checkValue(t, serve(ro, "GET", "/b").Body.String(), "b")
+}
+
+func TestLineAndColumn(t *testing.T) {
+	var data = []byte("ab\ncd\n\nef")
+	var cases = []struct {
+		offset           int64
+		wantLine, wantCl int
+	}{
+		{0, 1, 1},
+		{1, 1, 2},
+		{3, 2, 1},
+		{6, 3, 1},
+		{8, 4, 2},
+		{100, 4, 3},
+	}
+
+	for _, c := range cases {
+		var l, cl = lineAndColumn(data, c.offset)
+		checkValue(t, l, c.wantLine)
+		checkValue(t, cl, c.wantCl)
+	}
+}
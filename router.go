@@ -5,9 +5,10 @@ package nanomux
 
 import (
 	"errors"
-	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // --------------------------------------------------
@@ -19,7 +20,26 @@ type Router struct {
 	patternHosts []*Host
 	r            *Resource
 
-	requestPasser Handler
+	// caseInsensitiveHosts keeps the case-insensitive static hosts by their
+	// lowercase templates.
+	caseInsensitiveHosts map[string]*Host
+
+	requestPasser         Handler
+	passerMws             []Middleware
+	notFoundHandler       Handler
+	permanentRedirectCode int
+	redirectHandler       RedirectHandler
+	corsPolicy            *CORSPolicy
+	errorRenderer         ErrorRenderer
+	tracer                Tracer
+
+	// mu serializes the updates. live keeps the copy of the tree published
+	// by the last update, and once it's set, the tree can be changed only by
+	// updates. origin is set in the copies and refers to the router they
+	// were made for.
+	mu     sync.Mutex
+	live   atomic.Value
+	origin *Router
 }
 
 func NewRouter() *Router {
@@ -84,13 +104,16 @@ func (ro *Router) _Responder(urlTmplStr string) (_Responder, error) {
 			if newFirst != nil {
 				newLast.configure(secure, tslash, nil)
 
-				_r.registerResource(newFirst)
+				err = _r.registerResource(newFirst)
+				if err != nil {
+					return nil, newErr("%w", err)
+				}
+
 				if newHost {
 					// When newHost is true _r would still be holding
 					// a pointer to a newly created host.
 					err = ro.registerHost(_r.(*Host))
 					if err != nil {
-						// Unreachable.
 						return nil, newErr("%w", err)
 					}
 				}
@@ -106,19 +129,23 @@ func (ro *Router) _Responder(urlTmplStr string) (_Responder, error) {
 		_r.configure(secure, tslash, nil)
 
 		if h := ro.HostNamed(_r.Name()); h != nil {
-			return nil, newErr("%w", errDuplicateNameAmongSiblings)
+			return nil, newRegistrationErr(
+				"register",
+				_r.Template().String(),
+				h,
+				ErrDuplicateNameAmongSiblings,
+			)
 		}
 
 		err = ro.registerHost(_r.(*Host))
 		if err != nil {
-			// Unreachable.
 			return nil, newErr("%w", err)
 		}
 	} else {
 		err = _r.checkForConfigCompatibility(secure, tslash, nil)
 		if err != nil {
-			if errors.Is(err, errDormantHost) ||
-				errors.Is(err, errDormantResource) {
+			if errors.Is(err, ErrDormantHost) ||
+				errors.Is(err, ErrDormantResource) {
 				_r.configure(secure, tslash, nil)
 			} else {
 				return nil, newErr("%w", err)
@@ -242,12 +269,30 @@ func (ro *Router) registered_Responder(urlTmplStr string) (
 // be compatible with the existing responder's properties. A newly created
 // responder is configured with the values in the URL template.
 func (ro *Router) SetSharedDataAt(urlTmplStr string, data interface{}) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToSetSharedDataAt(urlTmplStr, data)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToSetSharedDataAt is like SetSharedDataAt but returns an error instead of
+// panicking.
+func (ro *Router) TryToSetSharedDataAt(
+	urlTmplStr string,
+	data interface{},
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
 	_r.SetSharedData(data)
+
+	return nil
 }
 
 // SharedDataAt returns the shared data of the existing responder at the URL.
@@ -263,9 +308,9 @@ func (ro *Router) SharedDataAt(urlTmplStr string) interface{} {
 
 	if _r == nil {
 		if rIsHost {
-			err = errNonExistentHost
+			err = ErrNonExistentHost
 		} else {
-			err = errNonExistentResource
+			err = ErrNonExistentResource
 		}
 
 		panicWithErr("%w %q", err, urlTmplStr)
@@ -295,21 +340,46 @@ func (ro *Router) SharedDataAt(urlTmplStr string) interface{} {
 // the config. The config's Secure and TrailingSlash values are ignored when
 // creating a new responder.
 func (ro *Router) SetConfigurationAt(urlTmplStr string, config Config) {
-	var _r, host, err = ro.registered_Responder(urlTmplStr)
+	var err = ro.TryToSetConfigurationAt(urlTmplStr, config)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToSetConfigurationAt is like SetConfigurationAt but returns an error
+// instead of panicking.
+func (ro *Router) TryToSetConfigurationAt(
+	urlTmplStr string,
+	config Config,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, host, err = ro.registered_Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
 	if _r != nil {
-		_r.SetConfiguration(config)
-		return
+		if err = _r.TryToSetConfiguration(config); err != nil {
+			return newErr("%w", err)
+		}
+
+		return nil
 	}
 
 	if host {
-		ro.HostUsingConfig(urlTmplStr, config)
+		_, err = ro.TryToHostUsingConfig(urlTmplStr, config)
 	} else {
-		ro.ResourceUsingConfig(urlTmplStr, config)
+		_, err = ro.TryToResourceUsingConfig(urlTmplStr, config)
+	}
+
+	if err != nil {
+		return newErr("%w", err)
 	}
+
+	return nil
 }
 
 // ConfigurationAt returns the configuration of the existing responder at
@@ -325,9 +395,9 @@ func (ro *Router) ConfigurationAt(urlTmplStr string) Config {
 
 	if _r == nil {
 		if rIsHost {
-			err = errNonExistentHost
+			err = ErrNonExistentHost
 		} else {
-			err = errNonExistentResource
+			err = ErrNonExistentResource
 		}
 
 		panicWithErr("%w %q", err, urlTmplStr)
@@ -347,12 +417,30 @@ func (ro *Router) ConfigurationAt(urlTmplStr string) Config {
 // be compatible with the existing responder's properties. A newly created
 // responder is configured with the values in the URL template.
 func (ro *Router) SetImplementationAt(urlTmplStr string, impl Impl) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToSetImplementationAt(urlTmplStr, impl)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToSetImplementationAt is like SetImplementationAt but returns an error
+// instead of panicking.
+func (ro *Router) TryToSetImplementationAt(urlTmplStr string, impl Impl) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToSetImplementation(impl)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.SetImplementation(impl)
+	return nil
 }
 
 // ImplementationAt returns the implementation of the existing responder at the
@@ -369,9 +457,9 @@ func (ro *Router) ImplementationAt(urlTmplStr string) Impl {
 
 	if _r == nil {
 		if rIsHost {
-			err = errNonExistentHost
+			err = ErrNonExistentHost
 		} else {
-			err = errNonExistentResource
+			err = ErrNonExistentResource
 		}
 
 		panicWithErr("%w %q", err, urlTmplStr)
@@ -398,13 +486,37 @@ func (ro *Router) SetURLHandlerFor(
 	methods string,
 	urlTmplStr string,
 	handler Handler,
+	opts ...HandlerOption,
 ) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToSetURLHandlerFor(methods, urlTmplStr, handler, opts...)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToSetURLHandlerFor is like SetURLHandlerFor but returns an error instead
+// of panicking.
+func (ro *Router) TryToSetURLHandlerFor(
+	methods string,
+	urlTmplStr string,
+	handler Handler,
+	opts ...HandlerOption,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.SetHandlerFor(methods, handler)
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToSetHandlerFor(methods, handler, opts...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // URLHandlerOf returns the HTTP method handler of the existing responder at
@@ -423,9 +535,9 @@ func (ro *Router) URLHandlerOf(method string, urlTmplStr string) Handler {
 
 	if _r == nil {
 		if rIsHost {
-			err = errNonExistentHost
+			err = ErrNonExistentHost
 		} else {
-			err = errNonExistentResource
+			err = ErrNonExistentResource
 		}
 
 		panicWithErr("%w %q", err, urlTmplStr)
@@ -449,12 +561,33 @@ func (ro *Router) URLHandlerOf(method string, urlTmplStr string) Handler {
 // responder to the next path segment of the request's URL, the handler for a
 // not-found resource is called.
 func (ro *Router) WrapRequestPasserAt(urlTmplStr string, mws ...Middleware) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToWrapRequestPasserAt(urlTmplStr, mws...)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToWrapRequestPasserAt is like WrapRequestPasserAt but returns an error
+// instead of panicking.
+func (ro *Router) TryToWrapRequestPasserAt(
+	urlTmplStr string,
+	mws ...Middleware,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToWrapRequestPasser(mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.WrapRequestPasser(mws...)
+	return nil
 }
 
 // WrapRequestHandlerAt wraps the request handler of the responder at the URL.
@@ -470,12 +603,33 @@ func (ro *Router) WrapRequestPasserAt(urlTmplStr string, mws ...Middleware) {
 // called only when the responder is the one to handle the request and has at
 // least one HTTP method handler.
 func (ro *Router) WrapRequestHandlerAt(urlTmplStr string, mws ...Middleware) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToWrapRequestHandlerAt(urlTmplStr, mws...)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToWrapRequestHandlerAt is like WrapRequestHandlerAt but returns an error
+// instead of panicking.
+func (ro *Router) TryToWrapRequestHandlerAt(
+	urlTmplStr string,
+	mws ...Middleware,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToWrapRequestHandler(mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.WrapRequestHandler(mws...)
+	return nil
 }
 
 // WrapURLHandlerOf wraps the handlers of the HTTP methods of the existing
@@ -497,22 +651,44 @@ func (ro *Router) WrapURLHandlerOf(
 	urlTmplStr string,
 	mws ...Middleware,
 ) {
-	var _r, rIsHost, err = ro.registered_Responder(urlTmplStr)
+	var err = ro.TryToWrapURLHandlerOf(methods, urlTmplStr, mws...)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToWrapURLHandlerOf is like WrapURLHandlerOf but returns an error instead
+// of panicking.
+func (ro *Router) TryToWrapURLHandlerOf(
+	methods string,
+	urlTmplStr string,
+	mws ...Middleware,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, rIsHost, err = ro.registered_Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
 	if _r == nil {
 		if rIsHost {
-			err = errNonExistentHost
+			err = ErrNonExistentHost
 		} else {
-			err = errNonExistentResource
+			err = ErrNonExistentResource
 		}
 
-		panicWithErr("%w %q", err, urlTmplStr)
+		return newErr("%w %q", err, urlTmplStr)
+	}
+
+	err = _r.TryToWrapHandlerOf(methods, mws...)
+	if err != nil {
+		return newErr("%w", err)
 	}
 
-	_r.WrapHandlerOf(methods, mws...)
+	return nil
 }
 
 // -------------------------
@@ -535,12 +711,33 @@ func (ro *Router) SetPermanentRedirectCodeAt(
 	urlTmplStr string,
 	code int,
 ) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToSetPermanentRedirectCodeAt(urlTmplStr, code)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToSetPermanentRedirectCodeAt is like SetPermanentRedirectCodeAt but
+// returns an error instead of panicking.
+func (ro *Router) TryToSetPermanentRedirectCodeAt(
+	urlTmplStr string,
+	code int,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToSetPermanentRedirectCode(code)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.SetPermanentRedirectCode(code)
+	return nil
 }
 
 // PermanentRedirectCodeAt returns the status code of the existing responder
@@ -564,9 +761,9 @@ func (ro *Router) PermanentRedirectCodeAt(urlTmplStr string) int {
 
 	if _r == nil {
 		if rIsHost {
-			err = errNonExistentHost
+			err = ErrNonExistentHost
 		} else {
-			err = errNonExistentResource
+			err = ErrNonExistentResource
 		}
 
 		panicWithErr("%w %q", err, urlTmplStr)
@@ -591,12 +788,33 @@ func (ro *Router) SetRedirectHandlerAt(
 	urlTmplStr string,
 	handler RedirectHandler,
 ) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToSetRedirectHandlerAt(urlTmplStr, handler)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToSetRedirectHandlerAt is like SetRedirectHandlerAt but returns an error
+// instead of panicking.
+func (ro *Router) TryToSetRedirectHandlerAt(
+	urlTmplStr string,
+	handler RedirectHandler,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToSetRedirectHandler(handler)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.SetRedirectHandler(handler)
+	return nil
 }
 
 // RedirectHandlerAt returns the redirect handler function of the existing
@@ -617,9 +835,9 @@ func (ro *Router) RedirectHandlerAt(urlTmplStr string) RedirectHandler {
 
 	if _r == nil {
 		if rIsHost {
-			err = errNonExistentHost
+			err = ErrNonExistentHost
 		} else {
-			err = errNonExistentResource
+			err = ErrNonExistentResource
 		}
 
 		panicWithErr("%w %q", err, urlTmplStr)
@@ -648,12 +866,33 @@ func (ro *Router) WrapRedirectHandlerAt(
 	urlTmplStr string,
 	mws ...func(RedirectHandler) RedirectHandler,
 ) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToWrapRedirectHandlerAt(urlTmplStr, mws...)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToWrapRedirectHandlerAt is like WrapRedirectHandlerAt but returns an error
+// instead of panicking.
+func (ro *Router) TryToWrapRedirectHandlerAt(
+	urlTmplStr string,
+	mws ...func(RedirectHandler) RedirectHandler,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToWrapRedirectHandler(mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.WrapRedirectHandler(mws...)
+	return nil
 }
 
 // RedirectRequestAt configures the responder at the path to redirect requests
@@ -680,12 +919,34 @@ func (ro *Router) WrapRedirectHandlerAt(
 // 		http.StatusMovedPermanently,
 // 	)
 func (ro *Router) RedirectRequestAt(urlTmplStr, url string, redirectCode int) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToRedirectRequestAt(urlTmplStr, url, redirectCode)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToRedirectRequestAt is like RedirectRequestAt but returns an error instead
+// of panicking.
+func (ro *Router) TryToRedirectRequestAt(
+	urlTmplStr,
+	url string,
+	redirectCode int,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToRedirectRequestTo(url, redirectCode)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.RedirectRequestTo(url, redirectCode)
+	return nil
 }
 
 // RedirectAnyRequestAt configures the responder at the path to redirect
@@ -716,12 +977,34 @@ func (ro *Router) RedirectAnyRequestAt(
 	url string,
 	redirectCode int,
 ) {
-	var _r, err = ro._Responder(urlTmplStr)
+	var err = ro.TryToRedirectAnyRequestAt(urlTmplStr, url, redirectCode)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
+}
+
+// TryToRedirectAnyRequestAt is like RedirectAnyRequestAt but returns an error
+// instead of panicking.
+func (ro *Router) TryToRedirectAnyRequestAt(
+	urlTmplStr,
+	url string,
+	redirectCode int,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var _r, err = ro._Responder(urlTmplStr)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	err = _r.TryToRedirectAnyRequestTo(url, redirectCode)
+	if err != nil {
+		return newErr("%w", err)
+	}
 
-	_r.RedirectAnyRequestTo(url, redirectCode)
+	return nil
 }
 
 // --------------------------------------------------
@@ -772,6 +1055,11 @@ func (ro *Router) replaceHost(oldH, newH *Host) error {
 	var tmpl = oldH.Template()
 	if tmpl.IsStatic() {
 		ro.staticHosts[tmpl.Content()] = newH
+		if err := ro.indexCaseInsensitiveHosts(); err != nil {
+			ro.staticHosts[tmpl.Content()] = oldH
+			ro.indexCaseInsensitiveHosts()
+			return err
+		}
 	} else {
 		var idx = -1
 		for i, h := range ro.patternHosts {
@@ -799,20 +1087,46 @@ func (ro *Router) replaceHost(oldH, newH *Host) error {
 	return nil
 }
 
-// registerHost registers the passed host and sets the router as it's parent.
-func (ro *Router) registerHost(h *Host) error {
+// unregisterHost removes the host from the router and clears its parent.
+func (ro *Router) unregisterHost(h *Host) error {
+	if !ro.HasHost(h) {
+		return newErr("%w", ErrNonExistentHost)
+	}
+
 	var tmpl = h.Template()
 	if tmpl.IsStatic() {
-		if ro.staticHosts == nil {
-			ro.staticHosts = make(map[string]*Host)
+		delete(ro.staticHosts, tmpl.Content())
+		if len(ro.staticHosts) == 0 {
+			ro.staticHosts = nil
 		}
 
-		ro.staticHosts[tmpl.Content()] = h
+		if err := ro.indexCaseInsensitiveHosts(); err != nil {
+			if ro.staticHosts == nil {
+				ro.staticHosts = make(map[string]*Host)
+			}
+
+			ro.staticHosts[tmpl.Content()] = h
+			ro.indexCaseInsensitiveHosts()
+			return err
+		}
 	} else {
-		ro.patternHosts = append(ro.patternHosts, h)
+		for i, ph := range ro.patternHosts {
+			if ph == h {
+				ro.patternHosts = append(
+					ro.patternHosts[:i:i],
+					ro.patternHosts[i+1:]...,
+				)
+
+				break
+			}
+		}
+
+		if len(ro.patternHosts) == 0 {
+			ro.patternHosts = nil
+		}
 	}
 
-	var err = h.setParent(ro)
+	var err = h.setParent(nil)
 	if err != nil {
 		// Unreachable.
 		return newErr("%w", err)
@@ -821,29 +1135,108 @@ func (ro *Router) registerHost(h *Host) error {
 	return nil
 }
 
-// host returns the host with the passed template as well as its security and
-// trailing slash properties. If the host doesn't exist, the method creates a
-// new one, but returns unregistered. A newly created host is indicated by the
-// newHost return value. When it's true, registering the new host is the
-// caller's responsibility
-func (ro *Router) host(hostTmplStr string) (
-	h *Host,
-	newHost, secure, tslash bool,
-	err error,
-) {
-	hostTmplStr, secure, tslash, err = getHost(hostTmplStr)
-	if err != nil {
-		err = newErr("%w", err)
-		return
+// caseInsensitiveHost returns the case-insensitive static host whose template
+// matches the host regardless of case. If there is no such host, nil is
+// returned.
+func (ro *Router) caseInsensitiveHost(host string) *Host {
+	if ro.caseInsensitiveHosts == nil {
+		return nil
 	}
 
-	var tmpl *Template
-	h, tmpl, err = ro.registeredHost(hostTmplStr)
-	if err != nil {
-		secure, tslash = false, false
-		err = newErr("%w", err)
-		return
-	}
+	return ro.caseInsensitiveHosts[strings.ToLower(host)]
+}
+
+// indexCaseInsensitiveHosts rebuilds the index of the case-insensitive static
+// hosts. If a case-insensitive host's template collides with the template
+// of another static host regardless of case, the index is still rebuilt, but
+// an error is returned.
+func (ro *Router) indexCaseInsensitiveHosts() error {
+	ro.caseInsensitiveHosts = nil
+	for k, h := range ro.staticHosts {
+		if !h.IsCaseInsensitive() {
+			continue
+		}
+
+		if ro.caseInsensitiveHosts == nil {
+			ro.caseInsensitiveHosts = make(map[string]*Host)
+		}
+
+		ro.caseInsensitiveHosts[strings.ToLower(k)] = h
+	}
+
+	if ro.caseInsensitiveHosts == nil {
+		return nil
+	}
+
+	for k, h := range ro.staticHosts {
+		var ih = ro.caseInsensitiveHosts[strings.ToLower(k)]
+		if ih != nil && ih != h {
+			return newRegistrationErr(
+				"register",
+				ih.Template().String(),
+				h,
+				ErrConflictingHost,
+			)
+		}
+	}
+
+	return nil
+}
+
+// registerHost registers the passed host and sets the router as it's parent.
+func (ro *Router) registerHost(h *Host) error {
+	var tmpl = h.Template()
+	if tmpl.IsStatic() {
+		if ro.staticHosts == nil {
+			ro.staticHosts = make(map[string]*Host)
+		}
+
+		ro.staticHosts[tmpl.Content()] = h
+		if err := ro.indexCaseInsensitiveHosts(); err != nil {
+			delete(ro.staticHosts, tmpl.Content())
+			if len(ro.staticHosts) == 0 {
+				ro.staticHosts = nil
+			}
+
+			ro.indexCaseInsensitiveHosts()
+			return err
+		}
+	} else {
+		ro.patternHosts = append(ro.patternHosts, h)
+	}
+
+	var err = h.setParent(ro)
+	if err != nil {
+		// Unreachable.
+		return newErr("%w", err)
+	}
+
+	return nil
+}
+
+// host returns the host with the passed template as well as its security and
+// trailing slash properties. If the host doesn't exist, the method creates a
+// new one, but returns unregistered. A newly created host is indicated by the
+// newHost return value. When it's true, registering the new host is the
+// caller's responsibility
+func (ro *Router) host(hostTmplStr string) (
+	h *Host,
+	newHost, secure, tslash bool,
+	err error,
+) {
+	hostTmplStr, secure, tslash, err = getHost(hostTmplStr)
+	if err != nil {
+		err = newErr("%w", err)
+		return
+	}
+
+	var tmpl *Template
+	h, tmpl, err = ro.registeredHost(hostTmplStr)
+	if err != nil {
+		secure, tslash = false, false
+		err = newErr("%w", err)
+		return
+	}
 
 	if h == nil {
 		h, err = createDormantHost(tmpl)
@@ -872,31 +1265,49 @@ func (ro *Router) host(hostTmplStr string) (
 //
 // The name given to the host must be unique among the other hosts.
 func (ro *Router) Host(hostTmplStr string) *Host {
-	var h, newHost, secure, tslash, err = ro.host(hostTmplStr)
+	var h, err = ro.TryToHost(hostTmplStr)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
 
+	return h
+}
+
+// TryToHost is like Host but returns an error instead of panicking.
+func (ro *Router) TryToHost(hostTmplStr string) (*Host, error) {
+	if err := ro.checkChangeable(); err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var h, newHost, secure, tslash, err = ro.host(hostTmplStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
 	if newHost {
 		h.configure(secure, tslash, nil)
 
-		if ro.HostNamed(h.Name()) != nil {
-			panicWithErr("%w", errDuplicateNameAmongSiblings)
+		if eh := ro.HostNamed(h.Name()); eh != nil {
+			return nil, newRegistrationErr(
+				"register",
+				h.Template().String(),
+				eh,
+				ErrDuplicateNameAmongSiblings,
+			)
 		}
 
 		err = ro.registerHost(h)
 		if err != nil {
-			// Unreachable.
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	} else {
 		err = h.checkForConfigCompatibility(secure, tslash, nil)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	}
 
-	return h
+	return h, nil
 }
 
 // HostUsingConfig uses the template and config to find an existing host
@@ -917,13 +1328,36 @@ func (ro *Router) HostUsingConfig(
 	hostTmplStr string,
 	config Config,
 ) *Host {
-	var h, newHost, secure, tslash, err = ro.host(hostTmplStr)
+	var h, err = ro.TryToHostUsingConfig(hostTmplStr, config)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
 
+	return h
+}
+
+// TryToHostUsingConfig is like HostUsingConfig but returns an error instead of
+// panicking.
+func (ro *Router) TryToHostUsingConfig(
+	hostTmplStr string,
+	config Config,
+) (*Host, error) {
+	if err := ro.checkChangeable(); err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var h, newHost, secure, tslash, err = ro.host(hostTmplStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
 	if config.RedirectsInsecureRequest && !secure {
-		panicWithErr("%w", errConflictingSecurity)
+		return nil, newRegistrationErr(
+			"configure",
+			hostTmplStr,
+			nil,
+			ErrConflictingSecurity,
+		)
 	}
 
 	var cfs = config.asFlags()
@@ -931,23 +1365,27 @@ func (ro *Router) HostUsingConfig(
 	if newHost {
 		h.configure(secure, tslash, &cfs)
 
-		if ro.HostNamed(h.Name()) != nil {
-			panicWithErr("%w", errDuplicateNameAmongSiblings)
+		if eh := ro.HostNamed(h.Name()); eh != nil {
+			return nil, newRegistrationErr(
+				"register",
+				h.Template().String(),
+				eh,
+				ErrDuplicateNameAmongSiblings,
+			)
 		}
 
 		err = ro.registerHost(h)
 		if err != nil {
-			// Unreachable.
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	} else {
 		err = h.checkForConfigCompatibility(secure, tslash, &cfs)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	}
 
-	return h
+	return h, nil
 }
 
 // RegisterHost registers the passed host if its name and template content
@@ -958,51 +1396,81 @@ func (ro *Router) HostUsingConfig(
 // other host's child resources to it. If both hosts can handle a request,
 // the method panics.
 func (ro *Router) RegisterHost(h *Host) {
+	var err = ro.TryToRegisterHost(h)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToRegisterHost is like RegisterHost but returns an error instead of
+// panicking.
+func (ro *Router) TryToRegisterHost(h *Host) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if h == nil {
-		panicWithErr("%w", errNilArgument)
+		return newErr("%w", ErrNilArgument)
 	}
 
 	if h.parent() != nil {
-		panicWithErr("%w", errRegisteredHost)
+		return newRegistrationErr(
+			"register",
+			h.Template().String(),
+			h,
+			ErrRegisteredHost,
+		)
 	}
 
 	var hwt, err = ro.hostWithTemplate(h.Template())
 	if err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if hwt == nil {
-		if ro.HostNamed(h.Name()) != nil {
-			panicWithErr("%w", errDuplicateNameAmongSiblings)
+		if eh := ro.HostNamed(h.Name()); eh != nil {
+			return newRegistrationErr(
+				"register",
+				h.Template().String(),
+				eh,
+				ErrDuplicateNameAmongSiblings,
+			)
 		}
 
 		err = ro.registerHost(h)
 		if err != nil {
-			// Unreachable.
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 
-		return
+		return nil
 	}
 
 	if !h.canHandleRequest() {
 		if err = h.passChildResourcesTo(hwt); err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 
-		return
+		return nil
 	}
 
 	if !hwt.canHandleRequest() {
 		if err = hwt.passChildResourcesTo(h); err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 
-		ro.replaceHost(hwt, h)
-		return
+		if err = ro.replaceHost(hwt, h); err != nil {
+			return newErr("%w", err)
+		}
+
+		return nil
 	}
 
-	panicWithErr("%w", errDuplicateHostTemplate)
+	return newRegistrationErr(
+		"register",
+		h.Template().String(),
+		hwt,
+		ErrDuplicateHostTemplate,
+	)
 }
 
 // RegisteredHost returns an already registered host. The host template may
@@ -1014,6 +1482,17 @@ func (ro *Router) RegisterHost(h *Host) {
 // Template's scheme and trailing slash property values must be compatible with
 // the host's properties, otherwise the method panics.
 func (ro *Router) RegisteredHost(hostTmplStr string) *Host {
+	var h, err = ro.TryToRegisteredHost(hostTmplStr)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return h
+}
+
+// TryToRegisteredHost is like RegisteredHost but returns an error instead of
+// panicking.
+func (ro *Router) TryToRegisteredHost(hostTmplStr string) (*Host, error) {
 	var (
 		err            error
 		secure, tslash bool
@@ -1021,27 +1500,56 @@ func (ro *Router) RegisteredHost(hostTmplStr string) *Host {
 
 	hostTmplStr, secure, tslash, err = getHost(hostTmplStr)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
 	var h *Host
 	h, _, err = ro.registeredHost(hostTmplStr)
 	if err != nil {
-		panicWithErr("%w", err)
+		return nil, newErr("%w", err)
 	}
 
 	if h != nil {
 		err = h.checkForConfigCompatibility(secure, tslash, nil)
 		if err != nil {
-			if errors.Is(err, errDormantHost) {
+			if errors.Is(err, ErrDormantHost) {
 				h.configure(secure, tslash, nil)
 			} else {
-				panicWithErr("%w", err)
+				return nil, newErr("%w", err)
 			}
 		}
 	}
 
-	return h
+	return h, nil
+}
+
+// UnregisterHost detaches the host and its subtree from the router. If the
+// host wasn't registered in the router, the method panics. After
+// unregistration, the host can be registered again.
+func (ro *Router) UnregisterHost(h *Host) {
+	var err = ro.TryToUnregisterHost(h)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToUnregisterHost is like UnregisterHost but returns an error instead of
+// panicking.
+func (ro *Router) TryToUnregisterHost(h *Host) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if h == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	var err = ro.unregisterHost(h)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // HostNamed returns the registered host with the name. If the host doesn't
@@ -1114,6 +1622,7 @@ func (ro *Router) HasAnyHost() bool {
 
 func (ro *Router) initializeRootResource() {
 	ro.r = newRootResource()
+	ro.r.papa = ro
 }
 
 // Resource returns an existing or newly created resource.
@@ -1133,13 +1642,27 @@ func (ro *Router) initializeRootResource() {
 // path and among their respective siblings. The host's name must be unique
 // among the other hosts.
 func (ro *Router) Resource(urlTmplStr string) *Resource {
-	var hTmplStr, pTmplStr, secure, tslash, err = splitHostAndPath(urlTmplStr)
+	var r, err = ro.TryToResource(urlTmplStr)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
 
+	return r
+}
+
+// TryToResource is like Resource but returns an error instead of panicking.
+func (ro *Router) TryToResource(urlTmplStr string) (*Resource, error) {
+	if err := ro.checkChangeable(); err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var hTmplStr, pTmplStr, secure, tslash, err = splitHostAndPath(urlTmplStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
 	if pTmplStr == "" {
-		panicWithErr("%w", errEmptyPathTemplate)
+		return nil, newErr("%w", errEmptyPathTemplate)
 	}
 
 	var _r _Responder
@@ -1147,12 +1670,17 @@ func (ro *Router) Resource(urlTmplStr string) *Resource {
 	if hTmplStr != "" {
 		if pTmplStr == "/" {
 			// The root resource cannot be registered under a host.
-			panicWithErr("%w", errNonRouterParent)
+			return nil, newRegistrationErr(
+				"register",
+				urlTmplStr,
+				nil,
+				ErrNonRouterParent,
+			)
 		}
 
 		_r, newHost, _, _, err = ro.host(hTmplStr)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	} else {
 		if ro.r == nil {
@@ -1166,40 +1694,47 @@ func (ro *Router) Resource(urlTmplStr string) *Resource {
 		var newFirst, newLast *Resource
 		_r, newFirst, newLast, _, err = _r.pathSegmentResources(pTmplStr)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 
 		if newFirst != nil {
 			newLast.configure(secure, tslash, nil)
-			_r.registerResource(newFirst)
+			err = _r.registerResource(newFirst)
+			if err != nil {
+				return nil, newErr("%w", err)
+			}
 
 			if newHost {
-				if ro.HostNamed(_r.Name()) != nil {
-					panicWithErr("%w", errDuplicateNameAmongSiblings)
+				if eh := ro.HostNamed(_r.Name()); eh != nil {
+					return nil, newRegistrationErr(
+						"register",
+						_r.Template().String(),
+						eh,
+						ErrDuplicateNameAmongSiblings,
+					)
 				}
 
 				err = ro.registerHost(_r.(*Host))
 				if err != nil {
-					// Unreachable.
-					panicWithErr("%w", err)
+					return nil, newErr("%w", err)
 				}
 			}
 
-			return newLast
+			return newLast, nil
 		}
 	}
 
 	err = _r.checkForConfigCompatibility(secure, tslash, nil)
 	if err != nil {
-		if errors.Is(err, errDormantHost) ||
-			errors.Is(err, errDormantResource) {
+		if errors.Is(err, ErrDormantHost) ||
+			errors.Is(err, ErrDormantResource) {
 			_r.configure(secure, tslash, nil)
 		} else {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	}
 
-	return _r.(*Resource)
+	return _r.(*Resource), nil
 }
 
 // ResourceUsingConfig returns an existing or newly created resource.
@@ -1225,17 +1760,40 @@ func (ro *Router) ResourceUsingConfig(
 	urlTmplStr string,
 	config Config,
 ) *Resource {
-	var hTmplStr, pTmplStr, secure, tslash, err = splitHostAndPath(urlTmplStr)
+	var r, err = ro.TryToResourceUsingConfig(urlTmplStr, config)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
 
+	return r
+}
+
+// TryToResourceUsingConfig is like ResourceUsingConfig but returns an error
+// instead of panicking.
+func (ro *Router) TryToResourceUsingConfig(
+	urlTmplStr string,
+	config Config,
+) (*Resource, error) {
+	if err := ro.checkChangeable(); err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var hTmplStr, pTmplStr, secure, tslash, err = splitHostAndPath(urlTmplStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
 	if pTmplStr == "" {
-		panicWithErr("%w", errEmptyPathTemplate)
+		return nil, newErr("%w", errEmptyPathTemplate)
 	}
 
 	if config.RedirectsInsecureRequest && !secure {
-		panicWithErr("%w", errConflictingSecurity)
+		return nil, newRegistrationErr(
+			"configure",
+			urlTmplStr,
+			nil,
+			ErrConflictingSecurity,
+		)
 	}
 
 	var _r _Responder
@@ -1243,12 +1801,17 @@ func (ro *Router) ResourceUsingConfig(
 	if hTmplStr != "" {
 		if pTmplStr == "/" {
 			// The root resource cannot be registered under a host.
-			panicWithErr("%w", errNonRouterParent)
+			return nil, newRegistrationErr(
+				"register",
+				urlTmplStr,
+				nil,
+				ErrNonRouterParent,
+			)
 		}
 
 		_r, newHost, _, _, err = ro.host(hTmplStr)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	} else {
 		if ro.r == nil {
@@ -1262,38 +1825,44 @@ func (ro *Router) ResourceUsingConfig(
 		var newFirst, newLast *Resource
 		_r, newFirst, newLast, _, err = _r.pathSegmentResources(pTmplStr)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 
 		if newFirst != nil {
 			var cfs = config.asFlags()
 			newLast.configure(secure, tslash, &cfs)
 
-			_r.registerResource(newFirst)
+			err = _r.registerResource(newFirst)
+			if err != nil {
+				return nil, newErr("%w", err)
+			}
+
 			if newHost {
 				err = ro.registerHost(_r.(*Host))
 				if err != nil {
-					// Unreachable.
-					panicWithErr("%w", err)
+					return nil, newErr("%w", err)
 				}
 			}
 
-			return newLast
+			return newLast, nil
 		}
 	}
 
 	var cfs = config.asFlags()
 	err = _r.checkForConfigCompatibility(secure, tslash, &cfs)
 	if err != nil {
-		if errors.Is(err, errDormantHost) ||
-			errors.Is(err, errDormantResource) {
-			_r.configure(secure, tslash, &cfs)
+		if errors.Is(err, ErrDormantHost) ||
+			errors.Is(err, ErrDormantResource) {
+			err = _r.configure(secure, tslash, &cfs)
+			if err != nil {
+				return nil, newErr("%w", err)
+			}
 		} else {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	}
 
-	return _r.(*Resource)
+	return _r.(*Resource), nil
 }
 
 // registerNewRoot is a helper method. It registers the new root resource
@@ -1307,7 +1876,12 @@ func (ro *Router) ResourceUsingConfig(
 // error.
 func (ro *Router) registerNewRoot(r *Resource) error {
 	if r.parent() != nil {
-		return newErr("%w", errRegisteredResource)
+		return newRegistrationErr(
+			"register",
+			r.Template().String(),
+			r,
+			ErrRegisteredResource,
+		)
 	}
 
 	if ro.r == nil {
@@ -1334,7 +1908,12 @@ func (ro *Router) registerNewRoot(r *Resource) error {
 		return nil
 	}
 
-	return newErr("%w", errDuplicateResourceTemplate)
+	return newRegistrationErr(
+		"register",
+		r.Template().String(),
+		ro.r,
+		ErrDuplicateResourceTemplate,
+	)
 }
 
 // RegisterResource registers the resource under the root resource if it doesn't
@@ -1353,12 +1932,30 @@ func (ro *Router) registerNewRoot(r *Resource) error {
 // handle a request are passed to the resource that can. Child resources are
 // also checked recursively.
 func (ro *Router) RegisterResource(r *Resource) {
+	var err = ro.TryToRegisterResource(r)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToRegisterResource is like RegisterResource but returns an error instead
+// of panicking.
+func (ro *Router) TryToRegisterResource(r *Resource) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if r == nil {
-		panicWithErr("%w", errNilArgument)
+		return newErr("%w", ErrNilArgument)
 	}
 
 	if r.parent() != nil {
-		panicWithErr("%w", errRegisteredResource)
+		return newRegistrationErr(
+			"register",
+			r.Template().String(),
+			r,
+			ErrRegisteredResource,
+		)
 	}
 
 	var (
@@ -1372,7 +1969,7 @@ func (ro *Router) RegisterResource(r *Resource) {
 			var err error
 			_r, newHost, _, _, err = ro.host(urlt.Host)
 			if err != nil {
-				panicWithErr("%w", err)
+				return newErr("%w", err)
 			}
 
 			// The following if statement should never be true.
@@ -1389,14 +1986,19 @@ func (ro *Router) RegisterResource(r *Resource) {
 			// The following if statement should never be true.
 			if urlt != nil && urlt.PrefixPath != "" {
 				// Unreachable.
-				panicWithErr("%w", errNonRouterParent)
+				return newRegistrationErr(
+					"register",
+					r.Template().String(),
+					nil,
+					ErrNonRouterParent,
+				)
 			}
 
 			if err := ro.registerNewRoot(r); err != nil {
-				panicWithErr("%w", err)
+				return newErr("%w", err)
 			}
 
-			return
+			return nil
 		}
 
 		if ro.r == nil {
@@ -1407,32 +2009,33 @@ func (ro *Router) RegisterResource(r *Resource) {
 	}
 
 	if err := _r.validate(r.Template()); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if err := _r.checkChildResourceNamesAreUniqueInURL(r); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if urlt != nil && urlt.PrefixPath != "" {
 		var err = _r.registerResourceUnder(urlt.PrefixPath, r)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 	} else {
 		var err = _r.keepResourceOrItsChildResources(r)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 	}
 
 	if newHost {
 		var err = ro.registerHost(_r.(*Host))
 		if err != nil {
-			// Unreachable.
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 	}
+
+	return nil
 }
 
 // RegisterResourceUnder registers the resource under the URL template.
@@ -1451,12 +2054,33 @@ func (ro *Router) RegisterResource(r *Resource) {
 // handle a request are passed to the resource that can. Child resources are
 // also checked recursively.
 func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
+	var err = ro.TryToRegisterResourceUnder(urlTmplStr, r)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToRegisterResourceUnder is like RegisterResourceUnder but returns an error
+// instead of panicking.
+func (ro *Router) TryToRegisterResourceUnder(
+	urlTmplStr string,
+	r *Resource,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if r == nil {
-		panicWithErr("%w", errNilArgument)
+		return newErr("%w", ErrNilArgument)
 	}
 
 	if r.parent() != nil {
-		panicWithErr("%w", errRegisteredResource)
+		return newRegistrationErr(
+			"register",
+			r.Template().String(),
+			r,
+			ErrRegisteredResource,
+		)
 	}
 
 	var (
@@ -1468,7 +2092,7 @@ func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
 	if urlTmplStr != "" {
 		hTmplStr, pTmplStr, secure, _, err = splitHostAndPath(urlTmplStr)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 	}
 
@@ -1480,15 +2104,30 @@ func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
 	if urlt != nil {
 		if urlt.Host != "" {
 			if hTmplStr == "" {
-				panicWithErr("%w", errConflictingHost)
+				return newRegistrationErr(
+					"validate",
+					hTmplStr,
+					r,
+					ErrConflictingHost,
+				)
 			}
 
 			if len(urlt.Host) != len(hTmplStr) {
-				panicWithErr("%w", errConflictingHost)
+				return newRegistrationErr(
+					"validate",
+					hTmplStr,
+					r,
+					ErrConflictingHost,
+				)
 			}
 
 			if urlt.Host != hTmplStr {
-				panicWithErr("%w", errConflictingHost)
+				return newRegistrationErr(
+					"validate",
+					hTmplStr,
+					r,
+					ErrConflictingHost,
+				)
 			}
 		}
 
@@ -1506,11 +2145,21 @@ func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
 			}
 
 			if lpTmplStr != len(urlt.PrefixPath) {
-				panicWithErr("%w", errConflictingPath)
+				return newRegistrationErr(
+					"validate",
+					pTmplStr,
+					r,
+					ErrConflictingPath,
+				)
 			}
 
 			if pTmplStr != urlt.PrefixPath {
-				panicWithErr("%w", errConflictingPath)
+				return newRegistrationErr(
+					"validate",
+					pTmplStr,
+					r,
+					ErrConflictingPath,
+				)
 			}
 		}
 	}
@@ -1520,7 +2169,7 @@ func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
 	if hTmplStr != "" {
 		_r, newHost, _, _, err = ro.host(hTmplStr)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 	}
 
@@ -1529,12 +2178,17 @@ func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
 		if r.isRoot() {
 			if pTmplStr == "" {
 				if err := ro.registerNewRoot(r); err != nil {
-					panicWithErr("%w", err)
+					return newErr("%w", err)
 				}
 
-				return
+				return nil
 			} else {
-				panicWithErr("%w", errNonRouterParent)
+				return newRegistrationErr(
+					"register",
+					urlTmplStr,
+					nil,
+					ErrNonRouterParent,
+				)
 			}
 		}
 
@@ -1546,11 +2200,11 @@ func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
 	}
 
 	if err = _r.validate(r.Template()); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if err = _r.checkChildResourceNamesAreUniqueInURL(r); err != nil {
-		panicWithErr("%w", err)
+		return newErr("%w", err)
 	}
 
 	if secure {
@@ -1560,22 +2214,23 @@ func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
 	if pTmplStr != "" && pTmplStr != "/" {
 		err = _r.registerResourceUnder(pTmplStr, r)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 	} else {
 		var err = _r.keepResourceOrItsChildResources(r)
 		if err != nil {
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 	}
 
 	if newHost {
 		var err = ro.registerHost(_r.(*Host))
 		if err != nil {
-			// Unreachable.
-			panicWithErr("%w", err)
+			return newErr("%w", err)
 		}
 	}
+
+	return nil
 }
 
 // RegisteredResource returns an existing resource with the URL template.
@@ -1592,29 +2247,42 @@ func (ro *Router) RegisterResourceUnder(urlTmplStr string, r *Resource) {
 // The scheme and trailing slash property values in the URL template must be
 // compatible with the resource's properties, otherwise the method panics.
 func (ro *Router) RegisteredResource(urlTmplStr string) *Resource {
-	var hTmplStr, pTmplStr, secure, tslash, err = splitHostAndPath(urlTmplStr)
+	var r, err = ro.TryToRegisteredResource(urlTmplStr)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
 
+	return r
+}
+
+// TryToRegisteredResource is like RegisteredResource but returns an error
+// instead of panicking.
+func (ro *Router) TryToRegisteredResource(
+	urlTmplStr string,
+) (*Resource, error) {
+	var hTmplStr, pTmplStr, secure, tslash, err = splitHostAndPath(urlTmplStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
 	if pTmplStr == "" {
-		panicWithErr("%w", errEmptyPathTemplate)
+		return nil, newErr("%w", errEmptyPathTemplate)
 	}
 
 	var _r _Responder
 	if hTmplStr != "" {
 		_r, _, err = ro.registeredHost(hTmplStr)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 
 		// Extracting the underlying value before comparing it to nil.
 		if h, ok := _r.(*Host); ok && h == nil {
-			return nil
+			return nil, nil
 		}
 	} else {
 		if ro.r == nil {
-			return nil
+			return nil, nil
 		}
 
 		_r = ro.r
@@ -1626,7 +2294,7 @@ func (ro *Router) RegisteredResource(urlTmplStr string) *Resource {
 	if pTmplStr != "/" {
 		_r, _, err = _r.registeredResource(pTmplStr)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 	}
 
@@ -1634,13 +2302,70 @@ func (ro *Router) RegisteredResource(urlTmplStr string) *Resource {
 	if r, ok := _r.(*Resource); ok && r != nil {
 		err = _r.checkForConfigCompatibility(secure, tslash, nil)
 		if err != nil {
-			panicWithErr("%w", err)
+			return nil, newErr("%w", err)
 		}
 
-		return _r.(*Resource)
+		return _r.(*Resource), nil
 	}
 
-	return nil
+	return nil, nil
+}
+
+// UnregisterResourceAt detaches the resource at the URL from the tree and
+// returns it. The resource's subtree is detached along with it. In the URL
+// template, names can be used instead of the complete segment templates. If
+// the URL template's path is a root "/" and it doesn't contain a host, the
+// root resource is detached. If the resource doesn't exist, the method
+// panics.
+//
+// For example,
+// 		https://$hostName/$resourceName/
+//
+// The scheme and trailing slash property values in the URL template must be
+// compatible with the resource's properties, otherwise the method panics.
+func (ro *Router) UnregisterResourceAt(urlTmplStr string) *Resource {
+	var r, err = ro.TryToUnregisterResourceAt(urlTmplStr)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+
+	return r
+}
+
+// TryToUnregisterResourceAt is like UnregisterResourceAt but returns an error
+// instead of panicking.
+func (ro *Router) TryToUnregisterResourceAt(
+	urlTmplStr string,
+) (*Resource, error) {
+	if err := ro.checkChangeable(); err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	var r, err = ro.TryToRegisteredResource(urlTmplStr)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	if r == nil {
+		return nil, newErr("%w", ErrNonExistentResource)
+	}
+
+	if r.isRoot() {
+		err = r.setParent(nil)
+		if err != nil {
+			return nil, newErr("%w", err)
+		}
+
+		ro.r = nil
+		return r, nil
+	}
+
+	err = r.parent().(_Responder).unregisterResource(r)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return r, nil
 }
 
 // -------------------------
@@ -1649,17 +2374,33 @@ func (ro *Router) RegisteredResource(urlTmplStr string) *Resource {
 // in their passed order. The router's request passer is responsible for
 // passing the request to the matching host or the root resource.
 func (ro *Router) WrapRequestPasser(mws ...Middleware) {
+	var err = ro.TryToWrapRequestPasser(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapRequestPasser is like WrapRequestPasser but returns an error instead
+// of panicking.
+func (ro *Router) TryToWrapRequestPasser(mws ...Middleware) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	if len(mws) == 0 {
-		panicWithErr("%w", errNoMiddleware)
+		return newErr("%w", errNoMiddleware)
 	}
 
 	for i, mw := range mws {
 		if mw == nil {
-			panicWithErr("%w at index %d", errNoMiddleware, i)
+			return newErr("%w at index %d", errNoMiddleware, i)
 		}
 
 		ro.requestPasser = mw(ro.requestPasser)
+		ro.passerMws = append(ro.passerMws, mw)
 	}
+
+	return nil
 }
 
 // -------------------------
@@ -1667,6 +2408,10 @@ func (ro *Router) WrapRequestPasser(mws ...Middleware) {
 // SetSharedDataForAll sets the shared data for each host and resource that
 // has no shared data set yet.
 func (ro *Router) SetSharedDataForAll(data interface{}) {
+	if err := ro.checkChangeable(); err != nil {
+		panicWithErr("%w", err)
+	}
+
 	traverseAndCall(
 		ro._Responders(),
 		func(_r _Responder) error {
@@ -1685,18 +2430,26 @@ func (ro *Router) SetSharedDataForAll(data interface{}) {
 // with properties other than security and trailing slash, those responders
 // will be skipped.
 func (ro *Router) SetConfigurationForAll(config Config) {
-	traverseAndCall(
-		ro._Responders(),
-		func(_r _Responder) error {
-			var cfs = _r.configFlags()
-			if (cfs &^ (flagActive | flagSecure | flagTrailingSlash)) > 0 {
-				return nil
-			}
+	var err = ro.TryToSetConfigurationForAll(config)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
 
-			_r.SetConfiguration(config)
-			return nil
-		},
-	)
+// TryToSetConfigurationForAll is like SetConfigurationForAll but returns an
+// error instead of panicking. The responders configured before the error
+// keep their new configuration.
+func (ro *Router) TryToSetConfigurationForAll(config Config) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var err = setConfigurationOfEvery(ro._Responders(), config)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // WrapAllRequestPassers wraps all the request passers of all the hosts and
@@ -1707,13 +2460,25 @@ func (ro *Router) SetConfigurationForAll(config Config) {
 // resource to the next path segment of the request's URL, the handler for a
 // not-found resource is called.
 func (ro *Router) WrapAllRequestPassers(mws ...Middleware) {
-	traverseAndCall(
-		ro._Responders(),
-		func(_r _Responder) error {
-			_r.WrapRequestPasser(mws...)
-			return nil
-		},
-	)
+	var err = ro.TryToWrapAllRequestPassers(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapAllRequestPassers is like WrapAllRequestPassers but returns an
+// error instead of panicking.
+func (ro *Router) TryToWrapAllRequestPassers(mws ...Middleware) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var err = wrapEveryRequestPasser(ro._Responders(), mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // WrapAllRequestHandlers wraps all the request handlers of all the hosts and
@@ -1724,13 +2489,25 @@ func (ro *Router) WrapAllRequestPassers(mws ...Middleware) {
 // called only when the responder is the one to handle the request and has at
 // least one HTTP method handler.
 func (ro *Router) WrapAllRequestHandlers(mws ...Middleware) {
-	traverseAndCall(
-		ro._Responders(),
-		func(_r _Responder) error {
-			_r.WrapRequestHandler(mws...)
-			return nil
-		},
-	)
+	var err = ro.TryToWrapAllRequestHandlers(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapAllRequestHandlers is like WrapAllRequestHandlers but returns an
+// error instead of panicking.
+func (ro *Router) TryToWrapAllRequestHandlers(mws ...Middleware) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	var err = wrapEveryRequestHandler(ro._Responders(), mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
 }
 
 // WrapAllHandlersOf wraps the handlers of the HTTP methods of all the hosts and
@@ -1746,12 +2523,280 @@ func (ro *Router) WrapAllHandlersOf(
 	methods string,
 	mws ...Middleware,
 ) {
+	var err = ro.TryToWrapAllHandlersOf(methods, mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapAllHandlersOf is like WrapAllHandlersOf but returns an error
+// instead of panicking.
+func (ro *Router) TryToWrapAllHandlersOf(
+	methods string,
+	mws ...Middleware,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
 	var err = wrapEveryHandlerOf(methods, ro._Responders(), mws...)
+	if err != nil {
+		return newErr("%w", err)
+	}
+
+	return nil
+}
+
+// SetHandlerForNotFound sets the handler for not-found resources. The handler
+// is inherited by the hosts and resources that don't have their own handler.
+// Unless set, the handler set with the package's SetHandlerForNotFound
+// function is used.
+func (ro *Router) SetHandlerForNotFound(handler Handler) {
+	var err = ro.TryToSetHandlerForNotFound(handler)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetHandlerForNotFound is like SetHandlerForNotFound but returns an
+// error instead of panicking.
+func (ro *Router) TryToSetHandlerForNotFound(handler Handler) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if handler == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	ro.notFoundHandler = handler
+	return nil
+}
+
+// HandlerOfNotFound returns the router's handler for not-found resources.
+// If the router doesn't have its own handler, the handler set with the
+// package's SetHandlerForNotFound function is returned. The returned handler
+// marks the request as not found in the Args, like when the router calls it.
+func (ro *Router) HandlerOfNotFound() Handler {
+	return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		return handleNotFound(ro, w, r, args)
+	}
+}
+
+// WrapHandlerOfNotFound wraps the router's handler for not-found resources
+// with the middlewares in their passed order. If the router doesn't have its
+// own handler, the handler returned from the package's HandlerOfNotFound
+// function is wrapped and set as the router's handler.
+func (ro *Router) WrapHandlerOfNotFound(mws ...Middleware) {
+	var err = ro.TryToWrapHandlerOfNotFound(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapHandlerOfNotFound is like WrapHandlerOfNotFound but returns an
+// error instead of panicking.
+func (ro *Router) TryToWrapHandlerOfNotFound(mws ...Middleware) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if len(mws) == 0 {
+		return newErr("%w", errNoMiddleware)
+	}
+
+	var h = notFoundHandlerOf(ro)
+	for i, mw := range mws {
+		if mw == nil {
+			return newErr("%w at index %d", ErrNilArgument, i)
+		}
+
+		h = mw(h)
+	}
+
+	ro.notFoundHandler = h
+	return nil
+}
+
+// SetPermanentRedirectCode sets the router's status code for permanent
+// redirects. It's used to redirect requests to an HTTPS from an HTTP, to a
+// URL with a trailing slash from one without, or vice versa. The code is
+// either 301 (moved permanently) or 308 (permanent redirect).
+//
+// The code is inherited by the hosts and resources that don't have their
+// own code. Unless set, the package's permanent redirect code is used.
+func (ro *Router) SetPermanentRedirectCode(code int) {
+	var err = ro.TryToSetPermanentRedirectCode(code)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetPermanentRedirectCode is like SetPermanentRedirectCode but returns an
+// error instead of panicking.
+func (ro *Router) TryToSetPermanentRedirectCode(code int) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if code != http.StatusMovedPermanently &&
+		code != http.StatusPermanentRedirect {
+		return newErr("%w", ErrConflictingStatusCode)
+	}
+
+	ro.permanentRedirectCode = code
+	return nil
+}
+
+// PermanentRedirectCode returns the router's status code for permanent
+// redirects. If the router doesn't have its own code, the package's
+// permanent redirect code is returned.
+func (ro *Router) PermanentRedirectCode() int {
+	return permanentRedirectCodeOf(ro)
+}
+
+// SetRedirectHandler sets the router's redirect handler. The handler is
+// inherited by the hosts and resources that don't have their own handler.
+// Unless set, the common redirect handler is used.
+//
+// The handler is mostly used to redirect requests to an HTTPS from an
+// HTTP, to a URL with a trailing slash from a URL without, or vice versa.
+// It is also used when responders have been configured to redirect requests
+// to a new location.
+func (ro *Router) SetRedirectHandler(handler RedirectHandler) {
+	var err = ro.TryToSetRedirectHandler(handler)
 	if err != nil {
 		panicWithErr("%w", err)
 	}
 }
 
+// TryToSetRedirectHandler is like SetRedirectHandler but returns an error
+// instead of panicking.
+func (ro *Router) TryToSetRedirectHandler(handler RedirectHandler) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if handler == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	ro.redirectHandler = handler
+	return nil
+}
+
+// RedirectHandler returns the router's redirect handler. If the router
+// doesn't have its own handler, the common redirect handler is returned.
+func (ro *Router) RedirectHandler() RedirectHandler {
+	return redirectHandlerOf(ro)
+}
+
+// WrapRedirectHandler wraps the router's redirect handler with the
+// middlewares in their passed order. If the router doesn't have its own
+// handler, the common redirect handler is wrapped and set as the router's
+// handler.
+func (ro *Router) WrapRedirectHandler(
+	mws ...func(RedirectHandler) RedirectHandler,
+) {
+	var err = ro.TryToWrapRedirectHandler(mws...)
+	if err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToWrapRedirectHandler is like WrapRedirectHandler but returns an error
+// instead of panicking.
+func (ro *Router) TryToWrapRedirectHandler(
+	mws ...func(RedirectHandler) RedirectHandler,
+) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if len(mws) == 0 {
+		return newErr("%w", errNoMiddleware)
+	}
+
+	var h = redirectHandlerOf(ro)
+	for i, mw := range mws {
+		if mw == nil {
+			return newErr("%w at index %d", errNoMiddleware, i)
+		}
+
+		h = mw(h)
+	}
+
+	ro.redirectHandler = h
+	return nil
+}
+
+// SetCORSPolicy sets the router's CORS policy. The policy is inherited by
+// the hosts and resources that don't have their own policy.
+//
+// The default OPTIONS handler answers the preflight requests using the
+// policy and the responder's HTTP methods. The responses to the actual
+// cross-origin requests are decorated with the policy's headers.
+func (ro *Router) SetCORSPolicy(policy CORSPolicy) {
+	if err := ro.TryToSetCORSPolicy(policy); err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetCORSPolicy is like SetCORSPolicy but returns an error instead of
+// panicking.
+func (ro *Router) TryToSetCORSPolicy(policy CORSPolicy) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if err := policy.validate(); err != nil {
+		return newErr("%w", err)
+	}
+
+	ro.corsPolicy = policy.clone()
+	return nil
+}
+
+// CORSPolicy returns a copy of the router's CORS policy, or nil if the
+// router doesn't have one.
+func (ro *Router) CORSPolicy() *CORSPolicy {
+	if ro.corsPolicy != nil {
+		return ro.corsPolicy.clone()
+	}
+
+	return nil
+}
+
+// SetErrorRenderer sets the router's renderer of the errors returned from
+// the ErrHandlers. The renderer is inherited by the hosts and resources that
+// don't have their own renderer. Unless set, RenderErrorAsText is used.
+func (ro *Router) SetErrorRenderer(renderer ErrorRenderer) {
+	if err := ro.TryToSetErrorRenderer(renderer); err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetErrorRenderer is like SetErrorRenderer but returns an error instead
+// of panicking.
+func (ro *Router) TryToSetErrorRenderer(renderer ErrorRenderer) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if renderer == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	ro.errorRenderer = renderer
+	return nil
+}
+
+// ErrorRenderer returns the router's error renderer. If the router doesn't
+// have its own renderer, RenderErrorAsText is returned.
+func (ro *Router) ErrorRenderer() ErrorRenderer {
+	return errorRendererOf(ro)
+}
+
 // -------------------------
 
 // _Responders returns all the existing hosts and the root resource.
@@ -1775,13 +2820,27 @@ func (ro *Router) _Responders() []_Responder {
 // -------------------------
 
 // ServeHTTP is the Router's implementation of the http.Handler interface.
+//
+// After the first call to the Update method, requests are served by the
+// tree published by the last update.
 func (ro *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var args = getArgs(r.URL, nil)
-	if !ro.requestPasser(w, r, args) {
-		notFoundResourceHandler(w, r, args)
+	defer putArgsInThePool(args)
+
+	var current = ro.current()
+	if t := current.tracer; t != nil {
+		args.tracer = t
+		var ctx = t.StartRouting(r.Context(), r)
+		r = r.WithContext(ctx)
+
+		// The tracer's End hook must be called even if one of the handlers
+		// panics. It's called before the args are put back in the pool.
+		defer t.End(ctx, args)
 	}
 
-	putArgsInThePool(args)
+	if !current.requestPasser(w, r, args) {
+		handleNotFound(current, w, r, args)
+	}
 }
 
 // passRequest is the request passer of the Router. It passes the request
@@ -1791,34 +2850,33 @@ func (ro *Router) passRequest(
 	r *http.Request,
 	args *Args,
 ) bool {
-	var host = r.Host
-	if host == "" {
-		host = r.URL.Host
-	}
-
+	var host = requestHost(r)
 	if host != "" {
-		if strings.LastIndexByte(host, ':') >= 0 {
-			var h, _, err = net.SplitHostPort(host)
-			if err == nil {
-				host = h
-			}
+		if h := ro.staticHosts[host]; h != nil {
+			args._r = h.derived
+			args.matchedResponder(r, host)
+			args.handled = h.requestReceiver(w, r, args)
+			return args.handled
 		}
 
-		if h := ro.staticHosts[host]; h != nil {
+		if h := ro.caseInsensitiveHost(host); h != nil {
 			args._r = h.derived
+			args.matchedResponder(r, host)
 			args.handled = h.requestReceiver(w, r, args)
 			return args.handled
 		}
 
 		for _, ph := range ro.patternHosts {
 			var matched bool
-			matched, args.hostPathValues = ph.Template().Match(
+			matched, args.hostPathValues = ph.Template().match(
 				host,
 				args.hostPathValues,
+				ph.IsCaseInsensitive(),
 			)
 
 			if matched {
 				args._r = ph.derived
+				args.matchedResponder(r, host)
 				args.handled = ph.requestReceiver(w, r, args)
 				return args.handled
 			}
@@ -1829,10 +2887,17 @@ func (ro *Router) passRequest(
 		args.nextPathSegment() // Returns '/'.
 
 		args._r = ro.r.derived
+		args.matchedResponder(r, "/")
 		args.handled = ro.r.requestReceiver(w, r, args)
 		return args.handled
 	}
 
-	args.handled = notFoundResourceHandler(w, r, args)
+	var tried func() []string
+	if host != "" {
+		tried = ro.hostTemplates
+	}
+
+	args.explainNotFound(host, reasonNoMatchingHost, tried)
+	args.handled = handleNotFound(ro, w, r, args)
 	return args.handled
 }
@@ -4,6 +4,7 @@
 package nanomux
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -3117,6 +3118,37 @@ func TestRouter_RegisteredHost(t *testing.T) {
 	)
 }
 
+func TestRouter_UnregisterHost(t *testing.T) {
+	var (
+		ro       = NewRouter()
+		static   = ro.Host("example.com")
+		pattern1 = ro.Host("{sub1:a}.example.com")
+		pattern2 = ro.Host("{sub2:b}.example.com")
+		r        = ro.Resource("http://example.com/r")
+		other    = NewDormantHost("other.com")
+	)
+
+	testPanicker(t, false, func() { ro.UnregisterHost(static) })
+	checkValue(t, ro.staticHosts, map[string]*Host(nil))
+	checkValue(t, static.parent(), nil)
+	checkValue(t, r.Host(), static)
+	checkValue(t, r.Router(), (*Router)(nil))
+
+	testPanicker(t, false, func() { ro.UnregisterHost(pattern1) })
+	checkValue(t, ro.patternHosts, []*Host{pattern2})
+	checkValue(t, pattern1.parent(), nil)
+
+	testPanicker(t, false, func() { ro.UnregisterHost(pattern2) })
+	checkValue(t, ro.HasAnyHost(), false)
+
+	testPanicker(t, true, func() { ro.UnregisterHost(static) })
+	testPanicker(t, true, func() { ro.UnregisterHost(other) })
+	testPanicker(t, true, func() { ro.UnregisterHost(nil) })
+
+	testPanicker(t, false, func() { ro.RegisterHost(static) })
+	checkValue(t, ro.RegisteredResource("http://example.com/r"), r)
+}
+
 func TestRouter_HostNamed(t *testing.T) {
 	var ro = NewRouter()
 
@@ -4236,6 +4268,54 @@ func TestRouter_RegisteredResource(t *testing.T) {
 	}
 }
 
+func TestRouter_UnregisterResourceAt(t *testing.T) {
+	var (
+		ro   = NewRouter()
+		r    = ro.Resource("http://example.com/$r:r/")
+		r0   = ro.Resource("http://example.com/$r:r/{r0:\\d+}")
+		r1   = ro.Resource("/r1")
+		root = ro.Resource("/")
+	)
+
+	testPanickerValue(t, false, r0, func() interface{} {
+		return ro.UnregisterResourceAt("http://example.com/$r/{r0:\\d+}")
+	})
+
+	checkValue(t, r0.parent(), nil)
+	checkValue(t, r.HasAnyChildResources(), false)
+
+	testPanicker(t, true, func() {
+		ro.UnregisterResourceAt("http://example.com/$r")
+	})
+
+	testPanickerValue(t, false, r, func() interface{} {
+		return ro.UnregisterResourceAt("http://example.com/$r/")
+	})
+
+	testPanickerValue(t, false, root, func() interface{} {
+		return ro.UnregisterResourceAt("/")
+	})
+
+	checkValue(t, ro.r, (*Resource)(nil))
+	checkValue(t, root.parent(), nil)
+	checkValue(t, r1.parent(), _Parent(root))
+
+	testPanicker(t, true, func() { ro.UnregisterResourceAt("/") })
+	testPanicker(t, true, func() { ro.UnregisterResourceAt("/r1") })
+	testPanicker(t, true, func() {
+		ro.UnregisterResourceAt("http://example.com/$r")
+	})
+
+	testPanicker(t, true, func() {
+		ro.UnregisterResourceAt("http://non-existent.com/r")
+	})
+
+	testPanicker(t, true, func() { ro.UnregisterResourceAt("") })
+
+	testPanicker(t, false, func() { ro.RegisterResource(root) })
+	checkValue(t, ro.RegisteredResource("/r1"), r1)
+}
+
 func TestRouter_WrapRequestPasser(t *testing.T) {
 	var (
 		ro   = NewRouter()
@@ -4874,6 +4954,174 @@ func TestRouter_WrapAllHandlersOf(t *testing.T) {
 	}
 }
 
+func TestRouter_SetHandlerForNotFound(t *testing.T) {
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("get", "http://example.com/a", textHandler("a"))
+	ro.SetURLHandlerFor("get", "/a", textHandler("a"))
+
+	testPanicker(t, true, func() { ro.SetHandlerForNotFound(nil) })
+	testPanicker(t, false, func() {
+		ro.SetHandlerForNotFound(textHandler("not found"))
+	})
+
+	for _, url := range []string{
+		"http://example.com/b",
+		"/b",
+		"http://other.com/b",
+	} {
+		checkValue(t, serve(ro, "GET", url).Body.String(), "not found")
+	}
+
+	// The handler is kept in the published snapshots.
+	var err = ro.Update(func(tx *RouterTx) error {
+		tx.SetHandlerForNotFound(textHandler("not found 2"))
+		return nil
+	})
+
+	checkErr(t, err, false)
+	checkValue(t, serve(ro, "GET", "/b").Body.String(), "not found 2")
+
+	var ro2 = NewRouter()
+	ro2.SetURLHandlerFor("get", "/a", textHandler("a"))
+	checkValue(t, serve(ro2, "GET", "/b").Body.String() != "not found 2", true)
+}
+
+func TestRouter_WrapHandlerOfNotFound(t *testing.T) {
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("get", "/a", textHandler("a"))
+
+	testPanicker(t, true, func() { ro.WrapHandlerOfNotFound() })
+	testPanicker(t, true, func() { ro.WrapHandlerOfNotFound(nil) })
+
+	ro.SetHandlerForNotFound(textHandler("not found"))
+	testPanicker(t, false, func() {
+		ro.WrapHandlerOfNotFound(textMiddleware("mw1-"), textMiddleware("mw2-"))
+	})
+
+	checkValue(t, serve(ro, "GET", "/b").Body.String(), "mw2-mw1-not found")
+}
+
+func TestRouter_SetPermanentRedirectCode(t *testing.T) {
+	var ro = NewRouter()
+	var r = ro.Resource("http://example.com/a/")
+	r.SetHandlerFor("get", textHandler("a"))
+
+	testPanicker(t, true, func() {
+		ro.SetPermanentRedirectCode(http.StatusTemporaryRedirect)
+	})
+
+	testPanicker(t, false, func() {
+		ro.SetPermanentRedirectCode(http.StatusMovedPermanently)
+	})
+
+	checkValue(t, ro.PermanentRedirectCode(), http.StatusMovedPermanently)
+	checkValue(t, r.PermanentRedirectCode(), http.StatusMovedPermanently)
+	checkValue(t, r.Host().PermanentRedirectCode(), http.StatusMovedPermanently)
+
+	var w = serve(ro, "GET", "http://example.com/a")
+	checkValue(t, w.Code, http.StatusMovedPermanently)
+
+	r.SetPermanentRedirectCode(http.StatusPermanentRedirect)
+	checkValue(t, r.PermanentRedirectCode(), http.StatusPermanentRedirect)
+	checkValue(t, ro.PermanentRedirectCode(), http.StatusMovedPermanently)
+
+	w = serve(ro, "GET", "http://example.com/a")
+	checkValue(t, w.Code, http.StatusPermanentRedirect)
+
+	checkValue(t, NewRouter().PermanentRedirectCode(), permanentRedirectCode)
+}
+
+func TestRouter_SetRedirectHandler(t *testing.T) {
+	t.Run("parallel", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			var name = fmt.Sprintf("router%d", i)
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				var ro = NewRouter()
+				ro.SetURLHandlerFor(
+					"get",
+					"http://example.com/a/",
+					textHandler(""),
+				)
+				testPanicker(t, true, func() { ro.SetRedirectHandler(nil) })
+				testPanicker(t, false, func() {
+					ro.SetRedirectHandler(textRedirectHandler(name + "-"))
+				})
+
+				var w = serve(ro, "GET", "http://example.com/a")
+				checkValue(t, w.Body.String(), name+"-http://example.com/a/")
+
+				var h = ro.RegisteredHost("http://example.com")
+				w = httptest.NewRecorder()
+				h.RedirectHandler()(w, nil, "url", 0, nil)
+				checkValue(t, w.Body.String(), name+"-url")
+			})
+		}
+	})
+
+	var ro = NewRouter()
+	var r = ro.Resource("http://example.com/a/")
+	r.SetHandlerFor("get", textHandler("a"))
+	ro.SetRedirectHandler(textRedirectHandler("router-"))
+	r.SetRedirectHandler(textRedirectHandler("resource-"))
+
+	var w = serve(ro, "GET", "http://example.com/a")
+	checkValue(t, w.Body.String(), "resource-http://example.com/a/")
+
+	// The handler is kept in the published snapshots.
+	var err = ro.Update(func(tx *RouterTx) error {
+		tx.SetRedirectHandler(textRedirectHandler("router2-"))
+		return nil
+	})
+
+	checkErr(t, err, false)
+
+	w = serve(ro, "GET", "http://example.com/b")
+	checkValue(t, w.Body.String() != "router2-http://example.com/b/", true)
+
+	err = ro.Update(func(tx *RouterTx) error {
+		tx.SetURLHandlerFor("get", "http://example.com/b/", textHandler("b"))
+		return nil
+	})
+
+	checkErr(t, err, false)
+
+	w = serve(ro, "GET", "http://example.com/b")
+	checkValue(t, w.Body.String(), "router2-http://example.com/b/")
+}
+
+func TestRouter_WrapRedirectHandler(t *testing.T) {
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("get", "/a/", textHandler("a"))
+
+	testPanicker(t, true, func() { ro.WrapRedirectHandler() })
+	testPanicker(t, true, func() { ro.WrapRedirectHandler(nil) })
+
+	var mw = func(text string) func(RedirectHandler) RedirectHandler {
+		return func(next RedirectHandler) RedirectHandler {
+			return func(
+				w http.ResponseWriter,
+				r *http.Request,
+				url string,
+				code int,
+				args *Args,
+			) bool {
+				w.Write([]byte(text))
+				return next(w, r, url, code, args)
+			}
+		}
+	}
+
+	ro.SetRedirectHandler(textRedirectHandler("redirect-"))
+	testPanicker(t, false, func() {
+		ro.WrapRedirectHandler(mw("mw1-"), mw("mw2-"))
+	})
+
+	var w = serve(ro, "GET", "/a")
+	checkValue(t, w.Body.String(), "mw2-mw1-redirect-http://example.com/a/")
+}
+
 func TestRouter__Responders(t *testing.T) {
 	var (
 		ro = NewRouter()
@@ -5457,6 +5705,655 @@ func TestRouter_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestRouter_ServeHTTP_catchAll(t *testing.T) {
+	var ro = NewRouter()
+	var hr = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		w.Write([]byte(args.HostPathValues().Get("filepath")))
+		return true
+	}
+
+	var rawValue string
+	var rawHr = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		rawValue = args.RawCatchAllValue()
+		return hr(w, r, args)
+	}
+
+	ro.SetURLHandlerFor("get", "/files/{filepath...}", rawHr)
+	ro.SetURLHandlerFor("get", "/files/static", textHandler("static"))
+	ro.SetHandlerForNotFound(textHandler("not found"))
+	ro.SetRedirectHandler(textRedirectHandler("redirect-"))
+
+	var w = serve(ro, "GET", "/files/a")
+	checkValue(t, w.Body.String(), "a")
+
+	w = serve(ro, "GET", "/files/a/b%20c/d.txt")
+	checkValue(t, w.Body.String(), "a/b c/d.txt")
+
+	w = serve(ro, "GET", "/files/a%2Fb/c")
+	checkValue(t, w.Body.String(), "a/b/c")
+	checkValue(t, rawValue, "a%2Fb/c")
+
+	w = serve(ro, "GET", "/files/a/b%20c")
+	checkValue(t, rawValue, "a/b%20c")
+
+	// The malformed escape is rendered with the error renderer.
+	ro.SetErrorRenderer(
+		func(w http.ResponseWriter, r *http.Request, err error, a *Args) bool {
+			var he *HTTPError
+			if errors.As(err, &he) {
+				w.WriteHeader(he.Status)
+				w.Write([]byte("bad request"))
+			}
+
+			return true
+		},
+	)
+
+	var r = httptest.NewRequest("GET", "/files/a", nil)
+	r.URL.RawPath = "/files/a%zz"
+	w = httptest.NewRecorder()
+	ro.ServeHTTP(w, r)
+	checkValue(t, w.Code, http.StatusBadRequest)
+	checkValue(t, w.Body.String(), "bad request")
+
+	w = serve(ro, "GET", "/files/static")
+	checkValue(t, w.Body.String(), "static")
+
+	// Static and pattern siblings take precedence over the catch-all.
+	w = serve(ro, "GET", "/files/static/a")
+	checkValue(t, w.Body.String(), "not found")
+
+	w = serve(ro, "GET", "/files/a/b/")
+	checkValue(t, w.Body.String(), "redirect-http://example.com/files/a/b")
+
+	w = serve(ro, "GET", "/files/")
+	checkValue(t, w.Body.String(), "not found")
+
+	var rr = ro.RegisteredResource("/files/{filepath...}")
+	var url, err = rr.URL(HostPathValues{{"filepath", "a/b c/d.txt"}})
+	checkErr(t, err, false)
+	checkValue(t, url.String(), "http:///files/a/b%20c/d.txt")
+
+	testPanicker(t, true, func() { ro.Resource("/files/{filepath...}/a") })
+	testPanicker(t, true, func() { rr.Resource("a") })
+	testPanicker(t, true, func() { ro.Resource("/files/{filepath}") })
+	testPanicker(t, true, func() { ro.Host("{sub...}") })
+
+	rr = NewDormantResource("{filepath...}")
+	rr.SetHandlerFor("get", hr)
+
+	w = httptest.NewRecorder()
+	rr.ServeHTTP(w, httptest.NewRequest("GET", "/a/b", nil))
+	checkValue(t, w.Body.String(), "a/b")
+}
+
+func TestRouter_ServeHTTP_optional(t *testing.T) {
+	var ro = NewRouter()
+	ro.SetHandlerForNotFound(textHandler("not found"))
+
+	var hr = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		var hpVs = args.HostPathValues()
+		w.Write([]byte(hpVs.Get("lang") + " " + hpVs.Get("page")))
+		return true
+	}
+
+	ro.SetURLHandlerFor("get", "/{lang?=en}", hr)
+	ro.SetURLHandlerFor("get", "/{lang?=en}/docs", hr)
+	ro.SetURLHandlerFor("get", "/{lang?=en}/{page:page-\\d+}", hr)
+
+	var w = serve(ro, "GET", "/docs")
+	checkValue(t, w.Body.String(), "en ")
+
+	w = serve(ro, "GET", "/fr/docs")
+	checkValue(t, w.Body.String(), "fr ")
+
+	w = serve(ro, "GET", "/page-1")
+	checkValue(t, w.Body.String(), "en page-1")
+
+	w = serve(ro, "GET", "/fr/page-1")
+	checkValue(t, w.Body.String(), "fr page-1")
+
+	w = serve(ro, "GET", "/fr")
+	checkValue(t, w.Body.String(), "fr ")
+
+	w = serve(ro, "GET", "/fr/other")
+	checkValue(t, w.Body.String(), "not found")
+
+	// Case-insensitive children are matched when the segment is missing.
+	ro.ResourceUsingConfig("/{lang?=en}/faq", Config{CaseInsensitive: true})
+	ro.SetURLHandlerFor("get", "/{lang?=en}/faq", hr)
+
+	w = serve(ro, "GET", "/FAQ")
+	checkValue(t, w.Body.String(), "en ")
+
+	var r = ro.RegisteredResource("/{lang?=en}/docs")
+	var url, err = r.URL(HostPathValues{{"lang", "en"}})
+	checkErr(t, err, false)
+	checkValue(t, url.String(), "http:///docs")
+
+	url, err = r.URL(HostPathValues{{"lang", "fr"}})
+	checkErr(t, err, false)
+	checkValue(t, url.String(), "http:///fr/docs")
+
+	url, err = r.URL(nil)
+	checkErr(t, err, false)
+	checkValue(t, url.String(), "http:///docs")
+
+	testPanicker(t, true, func() { ro.Resource("/{lang?=fr}") })
+	testPanicker(t, true, func() { ro.Resource("/{lang}") })
+}
+
+func TestRouter_ServeHTTP_trailingOptional(t *testing.T) {
+	var ro = NewRouter()
+	ro.SetHandlerForNotFound(textHandler("not found"))
+	ro.SetRedirectHandler(textRedirectHandler("redirect-"))
+
+	var hr = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		var hpVs = args.HostPathValues()
+		w.Write([]byte(hpVs.Get("page") + " " + hpVs.Get("size")))
+		return true
+	}
+
+	ro.SetURLHandlerFor("get", "/{page?=1}", hr)
+	ro.SetURLHandlerFor("get", "/items/{page?=1}", hr)
+	ro.SetURLHandlerFor("get", "/posts/{page?=1}/{size?=10}", hr)
+	ro.SetURLHandlerFor("get", "http://other.com/{page?=1}", hr)
+	ro.SetURLHandlerFor("get", "/users", textHandler("users"))
+	ro.SetURLHandlerFor("get", "/users/{page?=1}", hr)
+
+	// Root.
+	var w = serve(ro, "GET", "/")
+	checkValue(t, w.Body.String(), "1 ")
+
+	w = serve(ro, "GET", "/2")
+	checkValue(t, w.Body.String(), "2 ")
+
+	w = serve(ro, "GET", "http://other.com/")
+	checkValue(t, w.Body.String(), "1 ")
+
+	w = serve(ro, "GET", "http://other.com")
+	checkValue(t, w.Body.String(), "1 ")
+
+	// Last segment.
+	w = serve(ro, "GET", "/items")
+	checkValue(t, w.Body.String(), "1 ")
+
+	w = serve(ro, "GET", "/items/3")
+	checkValue(t, w.Body.String(), "3 ")
+
+	w = serve(ro, "GET", "/items/")
+	checkValue(t, w.Body.String(), "redirect-http://example.com/items")
+
+	w = serve(ro, "GET", "/posts")
+	checkValue(t, w.Body.String(), "1 10")
+
+	w = serve(ro, "GET", "/posts/2")
+	checkValue(t, w.Body.String(), "2 10")
+
+	// The parent's own handler takes precedence.
+	w = serve(ro, "GET", "/users")
+	checkValue(t, w.Body.String(), "users")
+
+	w = serve(ro, "GET", "/users/2")
+	checkValue(t, w.Body.String(), "2 ")
+}
+
+func TestRouter_ServeHTTP_caseInsensitive(t *testing.T) {
+	var ro = NewRouter()
+	ro.SetHandlerForNotFound(textHandler("not found"))
+	ro.SetRedirectHandler(textRedirectHandler("redirect-"))
+
+	var ci = Config{CaseInsensitive: true}
+	var rc = Config{RedirectsToCanonicalCase: true}
+
+	ro.SetURLHandlerFor("get", "/products", textHandler("products"))
+	ro.Resource("/products").SetConfiguration(ci)
+	ro.SetURLHandlerFor("get", "/products/{id:\\d+}-Item", textHandler("item"))
+	ro.Resource("/products/{id:\\d+}-Item").SetConfiguration(ci)
+	ro.SetURLHandlerFor("get", "/Blog/Posts", textHandler("posts"))
+	ro.Resource("/Blog").SetConfiguration(rc)
+	ro.Resource("/Blog/Posts").SetConfiguration(rc)
+	ro.SetURLHandlerFor("get", "/sensitive", textHandler("sensitive"))
+
+	ro.SetURLHandlerFor("get", "http://Example.net", textHandler("host"))
+	ro.Host("http://Example.net").SetConfiguration(ci)
+	ro.SetURLHandlerFor("get", "http://{sub}.Example.org", textHandler("sub"))
+	ro.Host("http://{sub}.Example.org").SetConfiguration(ci)
+
+	var cases = []struct{ url, want string }{
+		{"/products", "products"},
+		{"/PRODUCTS", "products"},
+		{"/Products/12-item", "item"},
+		{"/products/12-ITEM", "item"},
+		{"/Blog/Posts", "posts"},
+		{"/blog/posts", "redirect-http://example.com/Blog/Posts"},
+		{"/blog/Posts", "redirect-http://example.com/Blog/Posts"},
+		{"/BLOG/posts?a=b", "redirect-http://example.com/Blog/Posts?a=b"},
+		{"/sensitive", "sensitive"},
+		{"/Sensitive", "not found"},
+		{"http://example.net", "host"},
+		{"http://EXAMPLE.NET", "host"},
+		{"http://a.example.ORG", "sub"},
+	}
+
+	for _, c := range cases {
+		checkValue(t, serve(ro, "GET", c.url).Body.String(), c.want)
+	}
+}
+
+func TestRouter_caseInsensitiveConflicts(t *testing.T) {
+	var ro = NewRouter()
+	var ci = Config{CaseInsensitive: true}
+
+	ro.SetURLHandlerFor("get", "/products", textHandler("products"))
+	var _, err = ro.TryToResourceUsingConfig("/Products", ci)
+	checkErr(t, err, true)
+
+	var re *RegistrationError
+	if !errors.As(err, &re) || !errors.Is(err, ErrConflictingPathSegment) {
+		t.Fatalf("TryToResourceUsingConfig: err = %v", err)
+	}
+
+	checkValue(t, re.Resource, ro.RegisteredResource("/products"))
+	checkValue(t, ro.RegisteredResource("/Products"), (*Resource)(nil))
+
+	_, err = ro.TryToResourceUsingConfig("/Orders", ci)
+	checkErr(t, err, false)
+
+	_, err = ro.TryToResource("/orders")
+	if !errors.Is(err, ErrConflictingPathSegment) {
+		t.Fatalf("TryToResource: err = %v", err)
+	}
+
+	// Reconfiguring the sibling to be case-insensitive collides too.
+	ro.Resource("/Products")
+	err = ro.TryToSetConfigurationAt("/products", ci)
+	if !errors.Is(err, ErrConflictingPathSegment) {
+		t.Fatalf("TryToSetConfigurationAt: err = %v", err)
+	}
+
+	checkValue(t, ro.ConfigurationAt("/products").CaseInsensitive, false)
+	testPanicker(t, true, func() {
+		ro.RegisteredResource("/Products").SetConfiguration(ci)
+	})
+
+	// After the collision is removed, the configuration succeeds.
+	ro.UnregisterResourceAt("/Products")
+	checkErr(t, ro.TryToSetConfigurationAt("/products", ci), false)
+
+	var w = serve(ro, "GET", "/PRODUCTS")
+	checkValue(t, w.Body.String(), "products")
+
+	// Hosts.
+	ro.SetURLHandlerFor("get", "http://example.com/", textHandler("host"))
+	_, err = ro.TryToHostUsingConfig("http://EXAMPLE.com", ci)
+	if !errors.As(err, &re) || !errors.Is(err, ErrConflictingHost) {
+		t.Fatalf("TryToHostUsingConfig: err = %v", err)
+	}
+
+	checkValue(t, re.Host, ro.RegisteredHost("example.com/"))
+
+	_, err = ro.TryToHostUsingConfig("http://Example.org", ci)
+	checkErr(t, err, false)
+	ro.Host("http://Example.org").SetHandlerFor("get", textHandler("org"))
+
+	w = serve(ro, "GET", "http://EXAMPLE.ORG/")
+	checkValue(t, w.Body.String(), "org")
+
+	// The indexes are kept in the snapshot.
+	checkErr(t, ro.Update(func(*RouterTx) error { return nil }), false)
+	w = serve(ro, "GET", "http://example.net/PRODUCTS")
+	checkValue(t, w.Body.String(), "products")
+
+	w = serve(ro, "GET", "http://EXAMPLE.ORG/")
+	checkValue(t, w.Body.String(), "org")
+}
+
+func TestRouter_TryTo(t *testing.T) {
+	var ro = NewRouter()
+	var h = func(http.ResponseWriter, *http.Request, *Args) bool {
+		return true
+	}
+
+	var err = ro.TryToSetURLHandlerFor("GET", "http://example.com/{a}{b}", h)
+	checkErr(t, err, true)
+
+	err = ro.TryToSetURLHandlerFor("GET", "http://example.com/a", nil)
+	if !errors.Is(err, ErrNilArgument) {
+		t.Fatalf("TryToSetURLHandlerFor: err = %v", err)
+	}
+
+	err = ro.TryToSetURLHandlerFor("GET", "http://example.com/a", h)
+	checkErr(t, err, false)
+
+	err = ro.TryToSetConfigurationAt(
+		"https://example.com/a",
+		Config{SubtreeHandler: true},
+	)
+
+	if !errors.Is(err, ErrConflictingSecurity) {
+		t.Fatalf("TryToSetConfigurationAt: err = %v", err)
+	}
+
+	err = ro.TryToWrapURLHandlerOf("GET", "http://example.com/a", nil)
+	if !errors.Is(err, errNoMiddleware) {
+		t.Fatalf("TryToWrapURLHandlerOf: err = %v", err)
+	}
+
+	err = ro.TryToRedirectRequestAt("http://example.com/b", "/c", 200)
+	checkErr(t, err, true)
+
+	var hst, herr = ro.TryToHost("example.com/a")
+	if hst != nil || !errors.Is(herr, errUnwantedPathTemplate) {
+		t.Fatalf("TryToHost: h = %v, err = %v", hst, herr)
+	}
+
+	var r, rerr = ro.TryToRegisteredResource("http://example.com/a")
+	checkErr(t, rerr, false)
+	if r == nil {
+		t.Fatalf("TryToRegisteredResource: missing resource")
+	}
+
+	var child, cerr = r.TryToResource("http://example.com/a/b")
+	if child != nil || !errors.Is(cerr, ErrNonRouterParent) {
+		t.Fatalf("Resource.TryToResource: r = %v, err = %v", child, cerr)
+	}
+
+	err = r.TryToRegisterResource(nil)
+	if !errors.Is(err, ErrNilArgument) {
+		t.Fatalf("Resource.TryToRegisterResource: err = %v", err)
+	}
+
+	err = r.TryToSetHandlerFor("", h)
+	checkErr(t, err, true)
+
+	var nr, nerr = TryToNewResource("http://example.com/{a}{b}", nil)
+	if nr != nil || !errors.Is(nerr, ErrNilArgument) {
+		t.Fatalf("TryToNewResource: r = %v, err = %v", nr, nerr)
+	}
+
+	_, nerr = TryToNewDormantResource("http://example.com/{a}{b}")
+	checkErr(t, nerr, true)
+
+	var nh *Host
+	nh, nerr = TryToNewDormantHost("example.com/a")
+	if nh != nil || !errors.Is(nerr, errUnwantedPathTemplate) {
+		t.Fatalf("TryToNewDormantHost: h = %v, err = %v", nh, nerr)
+	}
+
+	nh, nerr = TryToNewDormantHost("example.org")
+	checkErr(t, nerr, false)
+
+	err = ro.TryToUnregisterHost(nh)
+	checkErr(t, err, true)
+
+	err = r.TryToUnregisterResource(NewDormantResource("b"))
+	if !errors.Is(err, ErrNonExistentResource) {
+		t.Fatalf("Resource.TryToUnregisterResource: err = %v", err)
+	}
+
+	_, err = ro.TryToUnregisterResourceAt("http://example.com/x")
+	if !errors.Is(err, ErrNonExistentResource) {
+		t.Fatalf("TryToUnregisterResourceAt: err = %v", err)
+	}
+
+	err = ro.TryToSetPermanentRedirectCode(302)
+	if !errors.Is(err, ErrConflictingStatusCode) {
+		t.Fatalf("TryToSetPermanentRedirectCode: err = %v", err)
+	}
+
+	checkErr(t, ro.TryToSetRedirectHandler(nil), true)
+	checkErr(t, ro.TryToWrapRedirectHandler(), true)
+	checkErr(t, ro.TryToWrapRequestPasser(nil), true)
+	checkErr(t, ro.TryToSetHandlerForNotFound(nil), true)
+	checkErr(t, ro.TryToWrapHandlerOfNotFound(), true)
+	checkErr(t, ro.TryToSetErrorRenderer(nil), true)
+	checkErr(t, ro.TryToSetTracer(nil), true)
+	checkErr(t, ro.TryToWrapAllHandlersOf("", textMiddleware("mw-")), true)
+
+	// The middlewares are checked before any responder is wrapped.
+	checkErr(t, ro.TryToWrapAllRequestPassers(textMiddleware("mw-"), nil), true)
+	checkErr(t, ro.TryToWrapAllRequestHandlers(textMiddleware("mw-"), nil), true)
+	checkValue(t, len(r.passerMws), 0)
+	checkValue(t, len(r.handlerMws), 0)
+
+	err = ro.TryToSetCORSPolicy(CORSPolicy{AllowedOrigins: []string{""}})
+	checkErr(t, err, true)
+
+	checkErr(t, r.TryToSetHandlerForNotFound(nil), true)
+	checkErr(t, r.TryToWrapHandlerOfNotFound(nil), true)
+	checkErr(t, r.TryToSetErrorRenderer(nil), true)
+	checkErr(t, r.TryToWrapSubtreeRequestPassers(), true)
+	checkErr(t, r.TryToWrapSubtreeRequestHandlers(), true)
+	checkErr(t, r.TryToWrapSubtreeHandlersOf("", textMiddleware("mw-")), true)
+
+	// The panicking counterparts must still panic with the same errors.
+	testPanicker(t, true, func() {
+		ro.SetURLHandlerFor("GET", "http://example.com/{a}{b}", h)
+	})
+
+	testPanicker(t, true, func() { r.Resource("http://example.com/a/b") })
+	testPanicker(t, true, func() { r.RegisterResource(nil) })
+	testPanicker(t, true, func() { ro.SetPermanentRedirectCode(302) })
+	testPanicker(t, true, func() { r.WrapSubtreeRequestHandlers() })
+}
+
+func TestRegistrationError(t *testing.T) {
+	var ro = NewRouter()
+	var r = ro.Resource("https://example.com/{name:users}/")
+
+	var checkRE = func(
+		err error,
+		wantOp, wantTmplStr string,
+		wantExisting *Resource,
+		wantErr error,
+	) {
+		t.Helper()
+
+		var re *RegistrationError
+		if !errors.As(err, &re) {
+			t.Fatalf("err = %v, want *RegistrationError", err)
+		}
+
+		checkValue(t, re.Op, wantOp)
+		checkValue(t, re.Template, wantTmplStr)
+		checkValue(t, re.Resource, wantExisting)
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("err = %v, want %v", err, wantErr)
+		}
+	}
+
+	var _, err = ro.TryToResource("http://example.com/{name:users}/")
+	checkRE(
+		err,
+		"configure",
+		Parse("{name:users}").String(),
+		r,
+		ErrConflictingSecurity,
+	)
+
+	_, err = ro.TryToResource("https://example.com/{name:users}")
+	checkRE(
+		err,
+		"configure",
+		Parse("{name:users}").String(),
+		r,
+		ErrConflictingTrailingSlash,
+	)
+
+	_, err = ro.TryToResource("https://example.com/{name:people}/")
+	checkRE(
+		err,
+		"register",
+		Parse("{name:people}").String(),
+		r,
+		ErrDuplicateNameAmongSiblings,
+	)
+
+	_, err = r.TryToResource("$name:{id}")
+	checkRE(
+		err,
+		"register",
+		Parse("$name:{id}").String(),
+		r,
+		ErrDuplicateNameInTheURL,
+	)
+
+	err = r.TryToRegisterResource(
+		NewDormantResource("https://example.com/people/{id}"),
+	)
+
+	checkRE(err, "validate", "people", r, ErrConflictingPathSegment)
+
+	var h = func(http.ResponseWriter, *http.Request, *Args) bool {
+		return true
+	}
+
+	r.SetHandlerFor("GET", h)
+
+	var nr = NewDormantResource("https://example.com/{name:users}/")
+	nr.SetHandlerFor("GET", h)
+	err = ro.TryToRegisterResource(nr)
+	checkRE(
+		err,
+		"register",
+		Parse("{name:users}").String(),
+		r,
+		ErrDuplicateResourceTemplate,
+	)
+
+	var eh = ro.Host("{sub:api}.example.com")
+	var hst, herr = ro.TryToHost("{sub:www}.example.com")
+	if hst != nil {
+		t.Fatalf("TryToHost: host = %v, want nil", hst)
+	}
+
+	var re *RegistrationError
+	if !errors.As(herr, &re) {
+		t.Fatalf("TryToHost: err = %v, want *RegistrationError", herr)
+	}
+
+	checkValue(t, re.Host, eh)
+	checkValue(t, re.Resource, (*Resource)(nil))
+	if !errors.Is(herr, ErrDuplicateNameAmongSiblings) {
+		t.Fatalf("TryToHost: err = %v", herr)
+	}
+
+	checkValue(t, re.Op, "register")
+	checkValue(t, re.Template, Parse("{sub:www}.example.com").String())
+}
+
+func TestRegistrationError_sentinels(t *testing.T) {
+	var h = func(http.ResponseWriter, *http.Request, *Args) bool {
+		return true
+	}
+
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("GET", "http://example.com/{name:users}/", h)
+	ro.SetURLHandlerFor("GET", "http://dup.example.com", h)
+
+	var eh = ro.RegisteredHost("http://example.com")
+	var er = ro.RegisteredResource("http://example.com/{name:users}/")
+	var catchAll = ro.Resource("http://example.com/files/{path...}")
+	ro.Resource("http://example.com/config")
+	ro.Resource("http://example.com/values/{id}")
+
+	var cases = []struct {
+		name string
+		err  error
+		fn   func() error
+	}{
+		{"host", ErrConflictingHost, func() error {
+			return ro.TryToRegisterResourceUnder(
+				"http://example.com/a",
+				NewDormantResource("http://other.com/a/b"),
+			)
+		}},
+		{"path", ErrConflictingPath, func() error {
+			return ro.TryToRegisterResourceUnder(
+				"http://example.com/a",
+				NewDormantResource("http://example.com/c/b"),
+			)
+		}},
+		{"path segment", ErrConflictingPathSegment, func() error {
+			return er.TryToRegisterResource(
+				NewDormantResource("http://example.com/people/{id}"),
+			)
+		}},
+		{"security", ErrConflictingSecurity, func() error {
+			var _, err = TryToNewDormantResourceUsingConfig(
+				"http://example.com/a",
+				Config{RedirectsInsecureRequest: true},
+			)
+
+			return err
+		}},
+		{"trailing slash", ErrConflictingTrailingSlash, func() error {
+			var _, err = ro.TryToResource("http://example.com/{name:users}")
+			return err
+		}},
+		{"config", ErrConflictingConfig, func() error {
+			var _, err = ro.TryToResourceUsingConfig(
+				"http://example.com/config",
+				Config{SubtreeHandler: true},
+			)
+
+			return err
+		}},
+		{"non-router parent", ErrNonRouterParent, func() error {
+			return eh.TryToRegisterResource(NewDormantResource("/"))
+		}},
+		{"catch-all parent", ErrCatchAllParent, func() error {
+			var _, err = catchAll.TryToResource("a")
+			return err
+		}},
+		{"host template", ErrDuplicateHostTemplate, func() error {
+			var nh = NewDormantHost("dup.example.com")
+			nh.SetHandlerFor("GET", h)
+			return ro.TryToRegisterHost(nh)
+		}},
+		{"resource template", ErrDuplicateResourceTemplate, func() error {
+			var nr = NewDormantResource("http://example.com/{name:users}/")
+			nr.SetHandlerFor("GET", h)
+			return ro.TryToRegisterResource(nr)
+		}},
+		{"name in the URL", ErrDuplicateNameInTheURL, func() error {
+			var _, err = er.TryToResource("$name:{id}")
+			return err
+		}},
+		{"value name in the URL", ErrDuplicateValueNameInTheURL, func() error {
+			var _, err = ro.TryToResource(
+				"http://example.com/values/{id}/$other:{id}",
+			)
+
+			return err
+		}},
+		{"name among siblings", ErrDuplicateNameAmongSiblings, func() error {
+			var _, err = ro.TryToResource("http://example.com/{name:people}/")
+			return err
+		}},
+		{"registered host", ErrRegisteredHost, func() error {
+			return ro.TryToRegisterHost(eh)
+		}},
+		{"registered resource", ErrRegisteredResource, func() error {
+			return ro.TryToRegisterResource(er)
+		}},
+	}
+
+	for _, c := range cases {
+		var err = c.fn()
+
+		var re *RegistrationError
+		if !errors.As(err, &re) {
+			t.Fatalf("%s: err = %v, want *RegistrationError", c.name, err)
+		}
+
+		if !errors.Is(err, c.err) {
+			t.Fatalf("%s: err = %v, want %v", c.name, err, c.err)
+		}
+	}
+}
+
 // --------------------------------------------------
 
 func TestArgs_SetGet(t *testing.T) {
@@ -5714,6 +6611,170 @@ func TestArgs_CurrentResource(t *testing.T) {
 	checkValue(t, strb.String(), "abc")
 }
 
+func TestArgs_PathValue(t *testing.T) {
+	RegisterConverter("testuint", `\d+`, func(v string) (interface{}, error) {
+		return strconv.ParseUint(v, 10, 0)
+	})
+
+	var ro = NewRouter()
+	var values []interface{}
+	var errs []error
+	var hr = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		for _, name := range []string{"sub", "id", "name", "missing"} {
+			var v, err = args.PathValue(name)
+			values = append(values, v)
+			errs = append(errs, err)
+		}
+
+		return true
+	}
+
+	ro.SetURLHandlerFor(
+		"get",
+		"http://{sub:testuint}.example.com/{id:testuint}/{name}",
+		hr,
+	)
+
+	serve(ro, "GET", "http://1.example.com/12/abc")
+	checkValue(t, values, []interface{}{uint64(1), uint64(12), "abc", nil})
+	checkErr(t, errs[0], false)
+	checkErr(t, errs[1], false)
+	checkErr(t, errs[2], false)
+	checkValue(t, errors.Is(errs[3], ErrMissingValue), true)
+
+	values, errs = nil, nil
+	serve(ro, "GET", "http://1.example.com/123456789012345678901234567890/abc")
+	checkValue(t, values[1], nil)
+	checkValue(t, errors.Is(errs[1], ErrInvalidValue), true)
+}
+
+func TestArgs_MatchedTemplate(t *testing.T) {
+	var (
+		tmpl      string
+		resources []string
+		outcome   string
+	)
+
+	var mw = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			var args = ArgsFrom(r)
+			tmpl = args.MatchedTemplate()
+			resources = nil
+			for _, r := range args.MatchedResources() {
+				resources = append(resources, r.Template().Content())
+			}
+
+			switch {
+			case args.Redirected():
+				outcome = "redirected"
+			case args.NotFound():
+				outcome = "not found"
+			case args.MethodNotAllowed():
+				outcome = "method not allowed"
+			case args.BadRequest():
+				outcome = "bad request"
+			default:
+				outcome = "handled"
+			}
+		})
+	}
+
+	var hr = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		return true
+	}
+
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("GET", "http://example.com/users/{id:int}/posts", hr)
+	ro.SetURLHandlerFor("GET", "http://example.com", hr)
+	ro.SetURLHandlerFor("GET", "http://example.com/files/{path...}", hr)
+	ro.SetURLHandlerFor("GET", "http://example.com/strict/", hr)
+	ro.SetConfigurationAt(
+		"http://example.com/strict/",
+		Config{StrictOnTrailingSlash: true},
+	)
+
+	ro.WrapRequestPasser(Mw(mw))
+
+	var cases = []struct {
+		method, url   string
+		wantTmpl      string
+		wantResources []string
+		wantOutcome   string
+	}{
+		{
+			"GET", "http://example.com/users/12/posts",
+			"http://example.com/users/{id:int}/posts",
+			[]string{"users", "{id:int}", "posts"},
+			"handled",
+		},
+		{
+			"GET", "http://example.com",
+			"http://example.com",
+			nil,
+			"handled",
+		},
+		{
+			"GET", "http://example.com/users/12/posts/",
+			"http://example.com/users/{id:int}/posts",
+			[]string{"users", "{id:int}", "posts"},
+			"redirected",
+		},
+		{
+			"GET", "http://example.com/users/abc",
+			"http://example.com/users",
+			[]string{"users"},
+			"not found",
+		},
+		{
+			"DELETE", "http://example.com/users/12/posts",
+			"http://example.com/users/{id:int}/posts",
+			[]string{"users", "{id:int}", "posts"},
+			"method not allowed",
+		},
+		{
+			"GET", "http://example.org/users",
+			"",
+			nil,
+			"not found",
+		},
+		{
+			"GET", "http://example.com/files/a/b",
+			"http://example.com/files/{path...}",
+			[]string{"files", "{path...}"},
+			"handled",
+		},
+		{
+			"GET", "http://example.com/files",
+			"http://example.com/files",
+			[]string{"files"},
+			"not found",
+		},
+		{
+			"GET", "http://example.com/strict",
+			"http://example.com/strict/",
+			[]string{"strict"},
+			"not found",
+		},
+	}
+
+	for _, c := range cases {
+		serve(ro, c.method, c.url)
+		checkValue(t, tmpl, c.wantTmpl)
+		checkValue(t, resources, c.wantResources)
+		checkValue(t, outcome, c.wantOutcome)
+	}
+
+	var r = httptest.NewRequest("GET", "http://example.com/files/a", nil)
+	r.URL.RawPath = "/files/a%zz"
+	var w = httptest.NewRecorder()
+	ro.ServeHTTP(w, r)
+	checkValue(t, w.Code, http.StatusBadRequest)
+	checkValue(t, tmpl, "http://example.com/files")
+	checkValue(t, outcome, "bad request")
+}
+
 // --------------------------------------------------
 
 func getStaticRouter() (*Router, *http.Request, error) {
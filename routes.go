@@ -0,0 +1,363 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// --------------------------------------------------
+
+// Route describes a host or resource that can respond to requests.
+type Route struct {
+	// URLTemplate is the full URL template of the responder. It contains the
+	// scheme, the host and path segment templates, and the trailing slash.
+	// Templates' names are not included. Slashes in path segment templates
+	// are escaped as %2F.
+	URLTemplate string
+
+	// Name is the name of the responder given in its template.
+	Name string
+
+	// Config is the configuration of the responder.
+	Config Config
+
+	// Methods are the HTTP methods the responder allows.
+	Methods []string
+
+	// HasRedirector is true when the responder was configured with the
+	// RedirectRequestTo or RedirectAnyRequestTo methods.
+	HasRedirector bool
+
+	// Host is the responder's host. When the route belongs to a host, it's
+	// the host itself. Host is nil for resources without a host.
+	Host *Host
+
+	// Resource is the responder itself when the route belongs to a resource.
+	// It's nil for hosts.
+	Resource *Resource
+
+	// ParentURLTemplate is the URL template of the responder's parent. It's
+	// empty for hosts, the root resource, and the resources without a parent.
+	ParentURLTemplate string
+}
+
+// --------------------------------------------------
+
+// Routes returns the routes of the responder and the responders in its
+// subtree. Only the responders that can handle a request or redirect it are
+// included. The subtree of a responder that redirects any request is not
+// reachable and is skipped.
+//
+// The routes are listed in depth-first order. Child resources are visited
+// in the order they are matched against the path segment: static resources
+// sorted by their templates, pattern resources in the order they were
+// registered and then the wildcard resource.
+func (rb *_ResponderBase) Routes() []Route {
+	var routes []Route
+	rb.appendRoutesTo(&routes)
+	return routes
+}
+
+// appendRoutesTo appends the routes of the responder and its subtree to
+// the routes.
+func (rb *_ResponderBase) appendRoutesTo(routes *[]Route) {
+	if rb.canHandleRequest() || rb.requestRedirector != nil ||
+		rb.anyRequestRedirectArgs != nil {
+		*routes = append(*routes, rb.route())
+	}
+
+	if rb.anyRequestRedirectArgs != nil {
+		return
+	}
+
+	var keys = make([]string, 0, len(rb.staticResources))
+	for k := range rb.staticResources {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		rb.staticResources[k].appendRoutesTo(routes)
+	}
+
+	for _, r := range rb.patternResources {
+		r.appendRoutesTo(routes)
+	}
+
+	if rb.wildcardResource != nil {
+		rb.wildcardResource.appendRoutesTo(routes)
+	}
+}
+
+// route returns the route of the responder.
+func (rb *_ResponderBase) route() Route {
+	var rt = Route{
+		URLTemplate: responderURLTmplStr(rb.derived),
+		Name:        rb.Name(),
+		Config:      rb.Configuration(),
+		Methods:     rb.AllowedHTTPMethods(),
+		HasRedirector: rb.requestRedirector != nil ||
+			rb.anyRequestRedirectArgs != nil,
+	}
+
+	switch _r := rb.derived.(type) {
+	case *Host:
+		rt.Host = _r
+	case *Resource:
+		rt.Resource = _r
+		rt.Host = _r.Host()
+
+		if p, ok := rb.papa.(_Responder); ok {
+			rt.ParentURLTemplate = responderURLTmplStr(p)
+		}
+	}
+
+	return rt
+}
+
+// -------------------------
+
+// Routes returns the routes of all the hosts and resources registered in
+// the router. Only the responders that can handle a request or redirect it
+// are included.
+//
+// The hosts are visited first: static hosts sorted by their templates and
+// then pattern hosts in the order they were registered. The routes of the
+// root resource and its subtree come last. Each subtree is listed in
+// depth-first order.
+func (ro *Router) Routes() []Route {
+	var routes []Route
+	var keys = make([]string, 0, len(ro.staticHosts))
+	for k := range ro.staticHosts {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		ro.staticHosts[k].appendRoutesTo(&routes)
+	}
+
+	for _, h := range ro.patternHosts {
+		h.appendRoutesTo(&routes)
+	}
+
+	if ro.r != nil {
+		ro.r.appendRoutesTo(&routes)
+	}
+
+	return routes
+}
+
+// --------------------------------------------------
+
+// URLFor returns the URL of the responder in the subtree with the values,
+// query, and fragment applied. The name is either a dot-separated chain of
+// names of the resources starting from the responder's child resource, e.g.,
+// "news.newsType", or the name of a resource that is unique in the subtree.
+// The query and fragment may be empty.
+func (rb *_ResponderBase) URLFor(
+	name string,
+	values HostPathValues,
+	query url.Values,
+	fragment string,
+) (*url.URL, error) {
+	var _r, err = rb.responderNamed(name)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return urlFor(_r, values, query, fragment)
+}
+
+// URLFor returns the URL of the host or resource with the values, query,
+// and fragment applied. The name is either a dot-separated chain of names of
+// the host and resources, e.g., "example.news.newsType", or a chain of names
+// starting from the root resource's child resource, or the name of a host or
+// resource that is unique in the router. The query and fragment may be
+// empty.
+func (ro *Router) URLFor(
+	name string,
+	values HostPathValues,
+	query url.Values,
+	fragment string,
+) (*url.URL, error) {
+	var _r, err = ro.responderNamed(name)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	return urlFor(_r, values, query, fragment)
+}
+
+// urlFor returns the responder's URL with the values, query, and fragment
+// applied.
+func urlFor(
+	_r _Responder,
+	values HostPathValues,
+	query url.Values,
+	fragment string,
+) (*url.URL, error) {
+	var u, err = responderURL(_r, values)
+	if err != nil {
+		return nil, newErr("%w", err)
+	}
+
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	u.Fragment = fragment
+	return u, nil
+}
+
+// responderNamed returns the responder in the subtree that the name refers
+// to. The name is first considered a dot-separated chain of names starting
+// from the responder's child resource. If the chain doesn't exist, the
+// resource with the name must be unique in the subtree.
+func (rb *_ResponderBase) responderNamed(name string) (_Responder, error) {
+	if name == "" {
+		return nil, newErr("%w: empty name", errInvalidArgument)
+	}
+
+	var names = strings.Split(name, ".")
+	if _r := resourceWithNameChain(rb.derived, names); _r != nil {
+		return _r, nil
+	}
+
+	return uniqueResponderNamed(rb._Responders(), name)
+}
+
+// responderNamed returns the host or resource that the name refers to. The
+// name is first considered a dot-separated chain of names starting from
+// the host or the root resource's child resource. If the chain doesn't
+// exist, the host or resource with the name must be unique in the router.
+func (ro *Router) responderNamed(name string) (_Responder, error) {
+	if name == "" {
+		return nil, newErr("%w: empty name", errInvalidArgument)
+	}
+
+	var names = strings.Split(name, ".")
+	if h := ro.HostNamed(names[0]); h != nil {
+		if _r := resourceWithNameChain(h, names[1:]); _r != nil {
+			return _r, nil
+		}
+	}
+
+	if ro.r != nil {
+		if _r := resourceWithNameChain(ro.r, names); _r != nil {
+			return _r, nil
+		}
+	}
+
+	return uniqueResponderNamed(ro._Responders(), name)
+}
+
+// uniqueResponderNamed returns the responder with the name in the trees of
+// the responders. If there is no such responder or there is more than one,
+// an error is returned.
+func uniqueResponderNamed(rs []_Responder, name string) (_Responder, error) {
+	var found _Responder
+	var err = traverseAndCall(
+		rs,
+		func(_r _Responder) error {
+			if _r.Name() != name {
+				return nil
+			}
+
+			if found != nil {
+				return newErr("%w %q", errAmbiguousName, name)
+			}
+
+			found = _r
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if found == nil {
+		return nil, newErr("%w named %q", ErrNonExistentResource, name)
+	}
+
+	return found, nil
+}
+
+// resourceWithNameChain returns the responder at the end of the chain of
+// names starting from the responder's child resource. If the names are empty,
+// the responder itself is returned.
+func resourceWithNameChain(_r _Responder, names []string) _Responder {
+	for _, n := range names {
+		var r = _r.ChildResourceNamed(n)
+		if r == nil {
+			return nil
+		}
+
+		_r = r
+	}
+
+	return _r
+}
+
+// --------------------------------------------------
+
+// responderURLTmplStr returns the full URL template string of the responder,
+// including its scheme and trailing slash.
+func responderURLTmplStr(_r _Responder) string {
+	var (
+		host string
+		pss  []string
+	)
+
+loop:
+	for p := _Parent(_r); p != nil; p = p.parent() {
+		switch p := p.(type) {
+		case *Resource:
+			if p.isRoot() {
+				continue
+			}
+
+			pss = append(
+				pss,
+				strings.ReplaceAll(p.Template().Content(), "/", "%2F"),
+			)
+		case *Host:
+			host = p.Template().Content()
+			break loop
+		default:
+			break loop
+		}
+	}
+
+	var strb = strings.Builder{}
+	if _r.IsSecure() {
+		strb.WriteString("https://")
+	} else if host != "" {
+		strb.WriteString("http://")
+	}
+
+	strb.WriteString(host)
+	for i := len(pss) - 1; i > -1; i-- {
+		strb.WriteByte('/')
+		strb.WriteString(pss[i])
+	}
+
+	switch rr := _r.(type) {
+	case *Resource:
+		if (rr.HasTrailingSlash() && !rr.isRoot()) ||
+			(len(pss) == 0 && rr.isRoot()) {
+			strb.WriteByte('/')
+		}
+	case *Host:
+		if rr.HasTrailingSlash() {
+			strb.WriteByte('/')
+		}
+	}
+
+	return strb.String()
+}
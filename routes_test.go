@@ -0,0 +1,219 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// --------------------------------------------------
+
+func TestRouter_Routes(t *testing.T) {
+	var ro = NewRouter()
+	var h = func(http.ResponseWriter, *http.Request, *Args) bool {
+		return true
+	}
+
+	ro.Host("https://example.com").SetImplementation(&_Impl{})
+	ro.SetURLHandlerFor("get", "http://example.com/b/{id:\\d+}/", h)
+	ro.SetURLHandlerFor("get", "http://example.com/a", h)
+	ro.SetURLHandlerFor("get", "http://example.com/b/{name}", h)
+	ro.SetURLHandlerFor("get", "http://example.com/b/{id:\\d+}/c", h)
+	ro.SetURLHandlerFor("post", "http://{sub}.example.com/$x:x%2Fy", h)
+	ro.Resource("/dormant/r").SetImplementation(&_Impl{})
+	ro.RedirectAnyRequestAt(
+		"https:///old",
+		"https:///new",
+		http.StatusPermanentRedirect,
+	)
+
+	ro.SetURLHandlerFor("get", "https:///old/unreachable", h)
+	ro.SetURLHandlerFor("get", "https:///", h)
+	ro.RedirectRequestAt("/r", "/dormant/r", http.StatusMovedPermanently)
+
+	var routes = ro.Routes()
+	var wantTmpls = []string{
+		"https://example.com",
+		"http://example.com/a",
+		"http://example.com/b/{id:^\\d+$}/",
+		"http://example.com/b/{id:^\\d+$}/c",
+		"http://example.com/b/{name}",
+		"http://{sub}.example.com/x%2Fy",
+		"https:///",
+		"/dormant/r",
+		"https:///old",
+		"/r",
+	}
+
+	var gotTmpls = make([]string, len(routes))
+	for i, route := range routes {
+		gotTmpls[i] = route.URLTemplate
+	}
+
+	checkValue(t, gotTmpls, wantTmpls)
+
+	var route = routes[0]
+	checkValue(t, route.Host, ro.RegisteredHost("https://example.com"))
+	checkValue(t, route.Resource, (*Resource)(nil))
+	checkValue(t, route.Config.Secure, true)
+	checkValue(t, route.Methods, []string{"CUSTOM", "GET", "OPTIONS", "POST"})
+	checkValue(t, route.ParentURLTemplate, "")
+
+	route = routes[3]
+	checkValue(t, route.Host, ro.RegisteredHost("https://example.com"))
+	checkValue(
+		t,
+		route.Resource,
+		ro.RegisteredResource("http://example.com/b/{id:\\d+}/c"),
+	)
+
+	checkValue(
+		t,
+		route.ParentURLTemplate,
+		"http://example.com/b/{id:^\\d+$}/",
+	)
+
+	route = routes[5]
+	checkValue(t, route.Name, "x")
+	checkValue(t, route.Methods, []string{"OPTIONS", "POST"})
+
+	route = routes[6]
+	checkValue(t, route.Host, (*Host)(nil))
+	checkValue(t, route.Resource, ro.RegisteredResource("https:///"))
+	checkValue(t, route.ParentURLTemplate, "")
+
+	route = routes[7]
+	checkValue(t, route.ParentURLTemplate, "/dormant")
+
+	route = routes[8]
+	checkValue(t, route.HasRedirector, true)
+	checkValue(t, route.Methods, []string(nil))
+
+	route = routes[9]
+	checkValue(t, route.HasRedirector, true)
+
+	// HEAD is reported when it's handled by the GET handler.
+	ro = NewRouter()
+	ro.ResourceUsingConfig("/h", Config{HandlesHeadWithGet: true})
+	ro.SetURLHandlerFor("get", "/h", h)
+	routes = ro.Routes()
+	checkValue(t, routes[0].Methods, []string{"GET", "HEAD", "OPTIONS"})
+
+	checkValue(t, NewRouter().Routes(), []Route(nil))
+}
+
+func TestResourceBase_Routes(t *testing.T) {
+	var r = NewDormantResource("a")
+	r.SetConfiguration(Config{SubtreeHandler: true})
+
+	var h = func(http.ResponseWriter, *http.Request, *Args) bool {
+		return true
+	}
+
+	r.SetPathHandlerFor("get", "b/", h)
+	r.SetPathHandlerFor("get", "{c}", h)
+
+	var routes = r.Routes()
+	checkValue(t, len(routes), 2)
+	checkValue(t, routes[0].URLTemplate, "/a/b/")
+	checkValue(t, routes[0].ParentURLTemplate, "/a")
+	checkValue(t, routes[1].URLTemplate, "/a/{c}")
+
+	r.SetHandlerFor("get", h)
+	routes = r.Routes()
+	checkValue(t, len(routes), 3)
+	checkValue(t, routes[0].URLTemplate, "/a")
+	checkValue(t, routes[0].Config.SubtreeHandler, true)
+	checkValue(t, routes[0].ParentURLTemplate, "")
+
+	var host = NewDormantHost("https://example.com/")
+	host.SetPathHandlerFor("get", "https:///a/", h)
+	routes = host.Routes()
+	checkValue(t, len(routes), 1)
+	checkValue(t, routes[0].URLTemplate, "https://example.com/a/")
+	checkValue(t, routes[0].ParentURLTemplate, "https://example.com/")
+
+	host.SetHandlerFor("get", h)
+	routes = host.Routes()
+	checkValue(t, len(routes), 2)
+	checkValue(t, routes[0].URLTemplate, "https://example.com/")
+}
+
+func TestRouter_URLFor(t *testing.T) {
+	var ro = NewRouter()
+	var h = func(http.ResponseWriter, *http.Request, *Args) bool {
+		return true
+	}
+
+	ro.SetURLHandlerFor(
+		"get",
+		"https://$example:{sub}.example.com/$news:news/$newsType:{type}/",
+		h,
+	)
+
+	ro.SetURLHandlerFor("get", "http://example.com/$news:news/$unique:u", h)
+	ro.SetURLHandlerFor("get", "/$users:users/$user:{name}", h)
+	ro.SetURLHandlerFor("get", "/$x:a/$y:b", h)
+	ro.SetURLHandlerFor("get", "/$z:c/$y:b", h)
+
+	var cases = []struct {
+		name     string
+		values   HostPathValues
+		query    url.Values
+		fragment string
+		wantURL  string
+		wantErr  bool
+	}{
+		{
+			"example.news.newsType",
+			HostPathValues{{"sub", "www"}, {"type", "local"}},
+			url.Values{"page": []string{"2"}},
+			"top",
+			"https://www.example.com/news/local/?page=2#top",
+			false,
+		},
+		{
+			"users.user",
+			HostPathValues{{"name", "John"}},
+			nil,
+			"",
+			"http:///users/John",
+			false,
+		},
+		{"unique", nil, nil, "", "http://example.com/news/u", false},
+		{"user", HostPathValues{{"name", "Jane"}}, nil, "", "http:///users/Jane", false},
+		{"x.y", nil, nil, "", "http:///a/b", false},
+		{"y", nil, nil, "", "", true},
+		{"news", nil, nil, "", "", true},
+		{"example.news.newsType", nil, nil, "", "", true},
+		{"nonexistent", nil, nil, "", "", true},
+		{"", nil, nil, "", "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var u, err = ro.URLFor(c.name, c.values, c.query, c.fragment)
+			checkErr(t, err, c.wantErr)
+			if err == nil {
+				checkValue(t, u.String(), c.wantURL)
+			}
+		})
+	}
+
+	var host = ro.HostNamed("example")
+	var u, err = host.URLFor(
+		"newsType",
+		HostPathValues{{"sub", "blog"}, {"type", "world"}},
+		nil,
+		"",
+	)
+
+	checkErr(t, err, false)
+	checkValue(t, u.String(), "https://blog.example.com/news/world/")
+
+	_, err = host.URLFor("unique", nil, nil, "")
+	checkErr(t, err, true)
+}
@@ -6,7 +6,10 @@ package nanomux
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // --------------------------------------------------
@@ -117,11 +120,247 @@ func (tmplVs TemplateValues) Get(key string) string {
 	return v
 }
 
+// GetInt returns the value of the key as an int. If the key doesn't exist,
+// the error wraps the ErrMissingValue. If the value cannot be parsed, the
+// error wraps the ErrInvalidValue.
+func (tmplVs TemplateValues) GetInt(key string) (int, error) {
+	return Value(tmplVs, key, strconv.Atoi)
+}
+
+// GetInt64 returns the value of the key as an int64. If the key doesn't
+// exist, the error wraps the ErrMissingValue. If the value cannot be parsed,
+// the error wraps the ErrInvalidValue.
+func (tmplVs TemplateValues) GetInt64(key string) (int64, error) {
+	return Value(tmplVs, key, func(v string) (int64, error) {
+		return strconv.ParseInt(v, 10, 64)
+	})
+}
+
+// GetUint returns the value of the key as a uint. If the key doesn't exist,
+// the error wraps the ErrMissingValue. If the value cannot be parsed, the
+// error wraps the ErrInvalidValue.
+func (tmplVs TemplateValues) GetUint(key string) (uint, error) {
+	return Value(tmplVs, key, func(v string) (uint, error) {
+		var u, err = strconv.ParseUint(v, 10, 0)
+		return uint(u), err
+	})
+}
+
+// GetBool returns the value of the key as a bool. The value is parsed with
+// the strconv.ParseBool function. If the key doesn't exist, the error wraps
+// the ErrMissingValue. If the value cannot be parsed, the error wraps the
+// ErrInvalidValue.
+func (tmplVs TemplateValues) GetBool(key string) (bool, error) {
+	return Value(tmplVs, key, strconv.ParseBool)
+}
+
+// GetFloat returns the value of the key as a float64. If the key doesn't
+// exist, the error wraps the ErrMissingValue. If the value cannot be parsed,
+// the error wraps the ErrInvalidValue.
+func (tmplVs TemplateValues) GetFloat(key string) (float64, error) {
+	return Value(tmplVs, key, func(v string) (float64, error) {
+		return strconv.ParseFloat(v, 64)
+	})
+}
+
+// Value returns the value of the key in the template values, parsed with the
+// parse function. If the key doesn't exist, the error wraps the
+// ErrMissingValue. If the parse function fails, the error wraps the
+// ErrInvalidValue.
+//
+// Example:
+// 	var id, err = nanomux.Value(
+// 		args.HostPathValues(),
+// 		"id",
+// 		uuid.Parse,
+// 	)
+func Value[T any](
+	tmplVs TemplateValues,
+	key string,
+	parse func(string) (T, error),
+) (T, error) {
+	var v T
+	if parse == nil {
+		return v, newErr("%w", ErrNilArgument)
+	}
+
+	var i, str = tmplVs.get(key)
+	if i < 0 {
+		return v, newErr("%w for %q", ErrMissingValue, key)
+	}
+
+	var err error
+	v, err = parse(str)
+	if err != nil {
+		return v, newErr("%w for %q: %v", ErrInvalidValue, key, err)
+	}
+
+	return v, nil
+}
+
+// --------------------------------------------------
+
+// Converter converts the value of a dynamic segment to the type it
+// represents.
+type Converter func(value string) (interface{}, error)
+
+type _PatternType struct {
+	name    string
+	pattern string
+	convert Converter
+}
+
+var (
+	patternTypesMu sync.RWMutex
+
+	// patternTypes contains the built-in and registered pattern types.
+	patternTypes = map[string]*_PatternType{
+		"int":       {name: "int", pattern: `\d+`, convert: convertInt},
+		"uint":      {name: "uint", pattern: `\d+`, convert: convertUint},
+		"alpha":     {name: "alpha", pattern: `[A-Za-z]+`},
+		"alnum":     {name: "alnum", pattern: `[A-Za-z0-9]+`},
+		"slug":      {name: "slug", pattern: `[a-z0-9]+(?:-[a-z0-9]+)*`},
+		"uuid":      {name: "uuid", pattern: uuidPattern},
+		"date":      {name: "date", pattern: datePattern, convert: convertDate},
+		"hex":       {name: "hex", pattern: `[0-9A-Fa-f]+`},
+		"base64url": {name: "base64url", pattern: `[A-Za-z0-9_-]+`},
+	}
+)
+
+const (
+	uuidPattern = `[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-` +
+		`[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}`
+
+	datePattern = `\d{4}-\d{2}-\d{2}`
+)
+
+func convertInt(v string) (interface{}, error) {
+	return strconv.Atoi(v)
+}
+
+func convertUint(v string) (interface{}, error) {
+	var u, err = strconv.ParseUint(v, 10, 0)
+	return uint(u), err
+}
+
+func convertDate(v string) (interface{}, error) {
+	return time.Parse("2006-01-02", v)
+}
+
+// RegisterPatternType registers the pattern with the type name. When the
+// type name is given as the pattern of a dynamic segment in a template, the
+// segment matches the values with the registered pattern.
+//
+// The type name must be an identifier: a letter followed by letters, digits,
+// or underscores. An existing type name can be registered again only with
+// the same pattern; otherwise, the function panics. Registering it again with
+// RegisterPatternType keeps its converter, and registering it again with
+// RegisterConverter replaces the converter. The templates parsed before are
+// not affected.
+//
+// The built-in pattern types are:
+// 	int        `\d+`, converted to an int
+// 	uint       `\d+`, converted to a uint
+// 	alpha      `[A-Za-z]+`
+// 	alnum      `[A-Za-z0-9]+`
+// 	slug       `[a-z0-9]+(?:-[a-z0-9]+)*`
+// 	uuid       hyphenated UUID in its canonical form
+// 	date       `\d{4}-\d{2}-\d{2}`, converted to a time.Time
+// 	hex        `[0-9A-Fa-f]+`
+// 	base64url  `[A-Za-z0-9_-]+`
+//
+// The type name is given priority over a regular expression. A pattern that
+// consists only of a type name, like in "{v:date}", refers to the pattern
+// type, not to the name as a literal. To match the name literally, the
+// pattern must be wrapped in a non-capturing group: "{v:(?:date)}".
+//
+// Example:
+// 	nanomux.RegisterPatternType("year", `\d{4}`)
+//
+// 	var r = router.Resource("/archive/{year:year}")
+func RegisterPatternType(typeName, pattern string) {
+	registerPatternType(typeName, pattern, nil)
+}
+
+// RegisterConverter registers the converter with the type name and its
+// pattern. It's the same as the RegisterPatternType function, except that
+// the values of the segments with the type name can be retrieved in their
+// converted form with the Args' PathValue method.
+//
+// Example:
+// 	nanomux.RegisterConverter(
+// 		"bool",
+// 		"true|false",
+// 		func(v string) (interface{}, error) {
+// 			return strconv.ParseBool(v)
+// 		},
+// 	)
+//
+// 	var r = router.Resource("/features/{enabled:bool}")
+func RegisterConverter(typeName, pattern string, convert Converter) {
+	if convert == nil {
+		panicWithErr("%w", ErrNilArgument)
+	}
+
+	registerPatternType(typeName, pattern, convert)
+}
+
+// typeNameRe is the pattern of the pattern type names.
+var typeNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+func registerPatternType(typeName, pattern string, convert Converter) {
+	if typeName == "" || pattern == "" {
+		panicWithErr("%w", errInvalidArgument)
+	}
+
+	if !typeNameRe.MatchString(typeName) {
+		panicWithErr(
+			"%w - type name %q is not an identifier",
+			errInvalidArgument,
+			typeName,
+		)
+	}
+
+	if _, err := regexp.Compile(pattern); err != nil {
+		panicWithErr("%w - %v", errInvalidArgument, err)
+	}
+
+	patternTypesMu.Lock()
+	defer patternTypesMu.Unlock()
+
+	if pt := patternTypes[typeName]; pt != nil {
+		if pt.pattern != pattern {
+			panicWithErr(
+				"%w - type name %q is registered with the pattern %q",
+				errInvalidArgument,
+				typeName,
+				pt.pattern,
+			)
+		}
+
+		if convert == nil {
+			// The converter is replaced only by RegisterConverter.
+			convert = pt.convert
+		}
+	}
+
+	patternTypes[typeName] = &_PatternType{typeName, pattern, convert}
+}
+
+// patternType returns the pattern type registered with the name, or nil.
+func patternType(typeName string) *_PatternType {
+	patternTypesMu.RLock()
+	var pt = patternTypes[typeName]
+	patternTypesMu.RUnlock()
+	return pt
+}
+
 // --------------------------------------------------
 
 type _TemplateSegment struct {
 	staticStr    string         // Static segment of the template.
 	valuePattern *_ValuePattern // Dynamic segment of the template.
+	patternType  *_PatternType  // Pattern type of the dynamic segment.
 }
 
 // --------------------------------------------------
@@ -140,6 +379,10 @@ type _TemplateSegment struct {
 // in the template and the template doesn't have a name, the dynamic segment's
 // name is used as the name of the template.
 //
+// The pattern of the regexp segment may be the name of a built-in or
+// registered pattern type, like in "{id:int}". See the RegisterPatternType
+// function.
+//
 // If the regexp dynamic segment is repeated in the template, from the second
 // repetition, its pattern may be omitted. When the regexp matches a string, its
 // repetitions must get the same value, otherwise the match fails.
@@ -151,6 +394,18 @@ type _TemplateSegment struct {
 // static segment at the beginning of the template must start with the "$"
 // sign, and the template doesn't need a name, the "$" sign must be escaped too.
 //
+// A wildcard segment whose value name is followed by "..." is a catch-all
+// segment, like in "{filepath...}". The catch-all segment must be the only
+// segment of the template. When the template is used by a resource, the
+// segment matches all the remaining path segments of the request's URL.
+//
+// A wildcard segment whose value name is followed by "?" is an optional
+// segment. The "?" may be followed by "=" and the default value, like in
+// "{lang?=en}". The optional segment must be the only segment of the
+// template. When the template is used by a resource and the request's URL
+// doesn't have the segment, the path segment is passed to the resource's
+// child resources, and the default value is used as the segment's value.
+//
 // Some examples of the template forms:
 //
 // 	$templateName:staticPart{valueName:pattern},
@@ -170,6 +425,9 @@ type Template struct {
 	name        string
 	slices      []_TemplateSegment
 	wildcardIdx int
+	catchAll    bool
+	optional    bool
+	dflt        string
 }
 
 // -----
@@ -198,6 +456,23 @@ func (t *Template) ValueNames() []string {
 	return vns
 }
 
+// valueConverter returns the converter of the dynamic segment with the value
+// name. If the segment doesn't exist or doesn't have a converter, nil is
+// returned.
+func (t *Template) valueConverter(valueName string) Converter {
+	for _, slice := range t.slices {
+		if slice.valuePattern != nil && slice.valuePattern.name == valueName {
+			if slice.patternType != nil {
+				return slice.patternType.convert
+			}
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
 // HasValueName returns true if one of the dynamic segments of the
 // template has one of the names.
 func (t *Template) HasValueName(names ...string) bool {
@@ -248,7 +523,8 @@ func (t *Template) Content() string {
 			strb.WriteString(slice.staticStr[idx:len(slice.staticStr)])
 		} else {
 			strb.WriteByte('{')
-			for str := slice.valuePattern.name; len(str) > 0; {
+			var vn = slice.valuePattern.name + t.valueNameSuffix()
+			for str := vn; len(str) > 0; {
 				var idx = strings.Index(str, ":")
 				if idx < 0 {
 					strb.WriteString(str)
@@ -266,7 +542,12 @@ func (t *Template) Content() string {
 
 			if slice.valuePattern.re != nil && !vns[slice.valuePattern.name] {
 				strb.WriteByte(':')
-				strb.WriteString(slice.valuePattern.re.String())
+				if slice.patternType != nil {
+					strb.WriteString(slice.patternType.name)
+				} else {
+					strb.WriteString(slice.valuePattern.re.String())
+				}
+
 				vns[slice.valuePattern.name] = true
 			}
 
@@ -290,6 +571,7 @@ func (t *Template) UnescapedContent() string {
 		} else {
 			strb.WriteByte('{')
 			strb.WriteString(slice.valuePattern.name)
+			strb.WriteString(t.valueNameSuffix())
 
 			if vns == nil {
 				vns = make(map[string]bool)
@@ -297,7 +579,12 @@ func (t *Template) UnescapedContent() string {
 
 			if slice.valuePattern.re != nil && !vns[slice.valuePattern.name] {
 				strb.WriteByte(':')
-				strb.WriteString(slice.valuePattern.re.String())
+				if slice.patternType != nil {
+					strb.WriteString(slice.patternType.name)
+				} else {
+					strb.WriteString(slice.valuePattern.re.String())
+				}
+
 				vns[slice.valuePattern.name] = true
 			}
 
@@ -319,6 +606,37 @@ func (t *Template) IsWildcard() bool {
 	return len(t.slices) == 1 && t.wildcardIdx == 0
 }
 
+// IsCatchAll returns true if the template has only a catch-all segment.
+func (t *Template) IsCatchAll() bool {
+	return t.catchAll
+}
+
+// IsOptional returns true if the template has only an optional segment.
+func (t *Template) IsOptional() bool {
+	return t.optional
+}
+
+// DefaultValue returns the default value of the optional segment. If the
+// template doesn't have an optional segment, an empty string is returned.
+func (t *Template) DefaultValue() string {
+	return t.dflt
+}
+
+// valueNameSuffix returns the suffix of the catch-all or optional segment's
+// value name in the template's content.
+func (t *Template) valueNameSuffix() string {
+	switch {
+	case t.catchAll:
+		return "..."
+	case t.optional && t.dflt != "":
+		return "?=" + t.dflt
+	case t.optional:
+		return "?"
+	default:
+		return ""
+	}
+}
+
 // HasPattern returns true if the template has any regular expression segments.
 func (t *Template) HasPattern() bool {
 	var lslices = len(t.slices)
@@ -336,7 +654,7 @@ func (t *Template) HasPattern() bool {
 // templates.
 func (t *Template) SimilarityWith(t2 *Template) Similarity {
 	if t2 == nil {
-		panicWithErr("%w", errNilArgument)
+		panicWithErr("%w", ErrNilArgument)
 	}
 
 	if t.IsStatic() {
@@ -355,6 +673,11 @@ func (t *Template) SimilarityWith(t2 *Template) Similarity {
 
 	if t.IsWildcard() {
 		if t2.IsWildcard() {
+			if t.catchAll != t2.catchAll || t.optional != t2.optional ||
+				t.dflt != t2.dflt {
+				return Different
+			}
+
 			if t.slices[0].valuePattern.name ==
 				t2.slices[0].valuePattern.name {
 				if t.name != t2.name {
@@ -432,6 +755,16 @@ func (t *Template) SimilarityWith(t2 *Template) Similarity {
 func (t *Template) Match(
 	str string,
 	values TemplateValues,
+) (bool, TemplateValues) {
+	return t.match(str, values, false)
+}
+
+// match is the implementation of the Match method. When fold is true, the
+// static segments of the template are matched regardless of case.
+func (t *Template) match(
+	str string,
+	values TemplateValues,
+	fold bool,
 ) (bool, TemplateValues) {
 	var ltslices = len(t.slices)
 	var k = ltslices
@@ -441,7 +774,7 @@ func (t *Template) Match(
 
 	for i := 0; i < k; i++ {
 		if t.slices[i].staticStr != "" {
-			if strings.HasPrefix(str, t.slices[i].staticStr) {
+			if hasPrefix(str, t.slices[i].staticStr, fold) {
 				str = str[len(t.slices[i].staticStr):]
 			} else {
 				return false, values
@@ -472,7 +805,7 @@ func (t *Template) Match(
 
 	for i := ltslices - 1; i > k; i-- {
 		if t.slices[i].staticStr != "" {
-			if strings.HasSuffix(str, t.slices[i].staticStr) {
+			if hasSuffix(str, t.slices[i].staticStr, fold) {
 				str = str[:len(str)-len(t.slices[i].staticStr)]
 			} else {
 				return false, values
@@ -520,13 +853,44 @@ func (t *Template) Match(
 	return true, values
 }
 
+// hasPrefix is strings.HasPrefix that ignores case when fold is true.
+func hasPrefix(str, prefix string, fold bool) bool {
+	if fold {
+		return len(str) >= len(prefix) &&
+			strings.EqualFold(str[:len(prefix)], prefix)
+	}
+
+	return strings.HasPrefix(str, prefix)
+}
+
+// hasSuffix is strings.HasSuffix that ignores case when fold is true.
+func hasSuffix(str, suffix string, fold bool) bool {
+	if fold {
+		return len(str) >= len(suffix) &&
+			strings.EqualFold(str[len(str)-len(suffix):], suffix)
+	}
+
+	return strings.HasSuffix(str, suffix)
+}
+
 // TryToApply puts the values in the place of the wildcard segment and
 // regular expression segments if they match, otherwise returns an error.
-// When ignoreMissing is true, the method ignores the missing values.
+// When ignoreMissing is true, the method ignores the missing values. The
+// optional segment is omitted if its value is missing or equals its default
+// value.
 func (t *Template) TryToApply(
 	values TemplateValues,
 	ignoreMissing bool,
 ) (string, error) {
+	if t.optional {
+		// The optional segment is omitted when its value is missing or
+		// equals the default value.
+		var vi, vf = values.get(t.slices[0].valuePattern.name)
+		if vi < 0 || vf == t.dflt {
+			return "", nil
+		}
+	}
+
 	var lslices = len(t.slices)
 	var strb = strings.Builder{}
 
@@ -607,6 +971,9 @@ func (t *Template) Clear() {
 	t.name = ""
 	t.slices = nil
 	t.wildcardIdx = -1
+	t.catchAll = false
+	t.optional = false
+	t.dflt = ""
 }
 
 // --------------------------------------------------
@@ -867,6 +1234,11 @@ func appendDynamicSegmentTo(
 	valuePatterns _ValuePatterns,
 	wildcardIdx int,
 ) ([]_TemplateSegment, _ValuePatterns, int, error) {
+	var pt = patternType(pattern)
+	if pt != nil {
+		pattern = pt.pattern
+	}
+
 	if vpi, vp := valuePatterns.get(vName); vpi >= 0 {
 		if pattern != "" {
 			if wildcardIdx >= 0 {
@@ -885,8 +1257,16 @@ func appendDynamicSegmentTo(
 		}
 
 		// If a value-pattern pair already exists, we don't have to create a
-		// new one.
-		tss = append(tss, _TemplateSegment{valuePattern: vp})
+		// new one. The repeated segment gets the pattern type of the first
+		// one.
+		for _, ts := range tss {
+			if ts.valuePattern != nil && ts.valuePattern.name == vName {
+				pt = ts.patternType
+				break
+			}
+		}
+
+		tss = append(tss, _TemplateSegment{valuePattern: vp, patternType: pt})
 		return tss, valuePatterns, wildcardIdx, nil
 	}
 
@@ -944,7 +1324,7 @@ func appendDynamicSegmentTo(
 	}
 
 	var vp = &_ValuePattern{name: vName, re: re}
-	tss = append(tss, _TemplateSegment{valuePattern: vp})
+	tss = append(tss, _TemplateSegment{valuePattern: vp, patternType: pt})
 	valuePatterns.set(vName, vp)
 
 	return tss, valuePatterns, wildcardIdx, nil
@@ -1042,6 +1422,48 @@ func TryToParse(tmplStr string) (*Template, error) {
 		return nil, err
 	}
 
+	if tmpl.wildcardIdx >= 0 {
+		var vp = tmpl.slices[tmpl.wildcardIdx].valuePattern
+		if strings.HasSuffix(vp.name, "...") {
+			if len(vp.name) == 3 {
+				return nil, newErr("%w - empty value name", ErrInvalidTemplate)
+			}
+
+			if !tmpl.IsWildcard() {
+				return nil, newErr(
+					"%w - catch-all segment must be the only segment",
+					ErrInvalidTemplate,
+				)
+			}
+
+			vp.name = vp.name[:len(vp.name)-3]
+			tmpl.catchAll = true
+		} else if idx := strings.IndexByte(vp.name, '?'); idx >= 0 {
+			if idx == 0 {
+				return nil, newErr("%w - empty value name", ErrInvalidTemplate)
+			}
+
+			var dflt = vp.name[idx+1:]
+			if dflt != "" && dflt[0] != '=' {
+				return nil, newErr(
+					"%w - invalid optional segment",
+					ErrInvalidTemplate,
+				)
+			}
+
+			if !tmpl.IsWildcard() {
+				return nil, newErr(
+					"%w - optional segment must be the only segment",
+					ErrInvalidTemplate,
+				)
+			}
+
+			vp.name = vp.name[:idx]
+			tmpl.optional = true
+			tmpl.dflt = strings.TrimPrefix(dflt, "=")
+		}
+	}
+
 	if !tmpl.IsStatic() && tmpl.name == "" {
 		if tmpl.IsWildcard() {
 			tmpl.name = tmpl.slices[0].valuePattern.name
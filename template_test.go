@@ -4,9 +4,13 @@
 package nanomux
 
 import (
+	"errors"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 // --------------------------------------------------
@@ -73,6 +77,200 @@ func TestTemplateValues_SetGet(t *testing.T) {
 	}
 }
 
+func TestTemplateValues_GetTyped(t *testing.T) {
+	var tvs = TemplateValues{
+		{"int", "-12"},
+		{"uint", "12"},
+		{"bool", "true"},
+		{"float", "1.5"},
+		{"str", "abc"},
+	}
+
+	var i, err = tvs.GetInt("int")
+	checkErr(t, err, false)
+	checkValue(t, i, -12)
+
+	var i64 int64
+	i64, err = tvs.GetInt64("int")
+	checkErr(t, err, false)
+	checkValue(t, i64, int64(-12))
+
+	var u uint
+	u, err = tvs.GetUint("uint")
+	checkErr(t, err, false)
+	checkValue(t, u, uint(12))
+
+	_, err = tvs.GetUint("int")
+	checkValue(t, errors.Is(err, ErrInvalidValue), true)
+
+	var b bool
+	b, err = tvs.GetBool("bool")
+	checkErr(t, err, false)
+	checkValue(t, b, true)
+
+	var f float64
+	f, err = tvs.GetFloat("float")
+	checkErr(t, err, false)
+	checkValue(t, f, 1.5)
+
+	_, err = tvs.GetInt("str")
+	checkValue(t, errors.Is(err, ErrInvalidValue), true)
+
+	_, err = tvs.GetBool("missing")
+	checkValue(t, errors.Is(err, ErrMissingValue), true)
+}
+
+func TestValue(t *testing.T) {
+	var tvs = TemplateValues{{"list", "a,b,c"}}
+	var split = func(v string) ([]string, error) {
+		if v == "" {
+			return nil, errors.New("empty list")
+		}
+
+		return strings.Split(v, ","), nil
+	}
+
+	var v, err = Value(tvs, "list", split)
+	checkErr(t, err, false)
+	checkValue(t, v, []string{"a", "b", "c"})
+
+	tvs.Set("list", "")
+	_, err = Value(tvs, "list", split)
+	checkValue(t, errors.Is(err, ErrInvalidValue), true)
+
+	_, err = Value(tvs, "missing", split)
+	checkValue(t, errors.Is(err, ErrMissingValue), true)
+
+	_, err = Value[int](tvs, "list", nil)
+	checkErr(t, err, true)
+}
+
+// -------------------------
+
+func TestRegisterConverter(t *testing.T) {
+	var convert = func(v string) (interface{}, error) {
+		return strconv.Atoi(v)
+	}
+
+	testPanicker(t, true, func() { RegisterConverter("", `\d+`, convert) })
+	testPanicker(t, true, func() { RegisterConverter("testint", "", convert) })
+	testPanicker(t, true, func() { RegisterConverter("testint", `\d+`, nil) })
+	testPanicker(t, true, func() {
+		RegisterConverter("testint", `\d+(`, convert)
+	})
+	testPanicker(t, false, func() {
+		RegisterConverter("testint", `\d+`, convert)
+	})
+
+	var tmpl = Parse("{id:testint}-{id}.")
+	checkValue(t, tmpl.Content(), "{id:testint}-{id}.")
+	checkValue(t, tmpl.UnescapedContent(), "{id:testint}-{id}.")
+	checkValue(t, tmpl.SimilarityWith(Parse(`{id:\d+}-{id}.`)), TheSame)
+
+	var matched, values = tmpl.Match("12-12.", nil)
+	checkValue(t, matched, true)
+	checkValue(t, values.Get("id"), "12")
+
+	matched, _ = tmpl.Match("ab-ab.", nil)
+	checkValue(t, matched, false)
+
+	var v, err = tmpl.valueConverter("id")("12")
+	checkErr(t, err, false)
+	checkValue(t, v, 12)
+
+	checkValue(t, Parse(`{id:\d+}`).valueConverter("id") == nil, true)
+	checkValue(t, Parse(`{id}`).valueConverter("id") == nil, true)
+	checkValue(t, tmpl.valueConverter("name") == nil, true)
+}
+
+func TestRegisterPatternType(t *testing.T) {
+	testPanicker(t, true, func() { RegisterPatternType("", `\d{4}`) })
+	testPanicker(t, true, func() { RegisterPatternType("testyear", "") })
+	testPanicker(t, true, func() { RegisterPatternType("testyear", `\d{4}(`) })
+	testPanicker(t, false, func() { RegisterPatternType("testyear", `\d{4}`) })
+	testPanicker(t, false, func() { RegisterPatternType("testyear", `\d{4}`) })
+	testPanicker(t, true, func() { RegisterPatternType("testyear", `\d{2}`) })
+	testPanicker(t, true, func() { RegisterPatternType("int", `-?\d+`) })
+	testPanicker(t, true, func() { RegisterPatternType(`\d+`, `\d{2}`) })
+	testPanicker(t, true, func() { RegisterPatternType("1year", `\d{4}`) })
+
+	// Registering a built-in type again keeps its converter, which is
+	// checked below.
+	testPanicker(t, false, func() { RegisterPatternType("int", `\d+`) })
+
+	var tmpl = Parse("{year:testyear}")
+	checkValue(t, tmpl.Content(), "{year:testyear}")
+	checkValue(t, tmpl.valueConverter("year") == nil, true)
+	checkValue(t, tmpl.SimilarityWith(Parse(`{year:\d{4}}`)), TheSame)
+
+	var cases = []struct {
+		tmplStr    string
+		matches    []string
+		nonMatches []string
+	}{
+		{"{v:int}", []string{"0", "123"}, []string{"-1", "a1"}},
+		{"{v:uint}", []string{"0", "123"}, []string{"+1", "x"}},
+		{"{v:alpha}", []string{"abc", "ABc"}, []string{"ab1", "a-b"}},
+		{"{v:alnum}", []string{"ab1", "A2"}, []string{"a_1", "a-1"}},
+		{"{v:slug}", []string{"a", "a-b-1"}, []string{"a--b", "-a", "A"}},
+		{
+			"{v:uuid}",
+			[]string{"123e4567-e89b-12d3-a456-426614174000"},
+			[]string{"123e4567e89b12d3a456426614174000"},
+		},
+		{"{v:date}", []string{"2021-12-31"}, []string{"2021-1-31", "21-12-31"}},
+		{"{v:hex}", []string{"0aF9"}, []string{"0x1", "g"}},
+		{"{v:base64url}", []string{"aZ0-_"}, []string{"a+b", "a/b", "a="}},
+		{"{v:testyear}", []string{"2021"}, []string{"21", "20211"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.tmplStr, func(t *testing.T) {
+			var tmpl = Parse(c.tmplStr)
+			checkValue(t, tmpl.Content(), c.tmplStr)
+
+			for _, m := range c.matches {
+				var matched, _ = tmpl.Match(m, nil)
+				checkValue(t, matched, true)
+			}
+
+			for _, nm := range c.nonMatches {
+				var matched, _ = tmpl.Match(nm, nil)
+				checkValue(t, matched, false)
+			}
+		})
+	}
+
+	checkValue(t, Parse("{id:int}").SimilarityWith(Parse(`{id:\d+}`)), TheSame)
+	checkValue(
+		t,
+		Parse("{id:int}").SimilarityWith(Parse("{id:alpha}")),
+		Different,
+	)
+
+	var v, err = Parse("{id:int}").valueConverter("id")("12")
+	checkErr(t, err, false)
+	checkValue(t, v, 12)
+
+	v, err = Parse("{id:uint}").valueConverter("id")("12")
+	checkErr(t, err, false)
+	checkValue(t, v, uint(12))
+
+	v, err = Parse("{d:date}").valueConverter("d")("2021-12-31")
+	checkErr(t, err, false)
+	checkValue(t, v, time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC))
+
+	_, err = Parse("{d:date}").valueConverter("d")("2021-13-31")
+	checkErr(t, err, true)
+
+	// The type name wrapped in a non-capturing group is matched literally.
+	var matched, _ = Parse("{v:(?:date)}").Match("date", nil)
+	checkValue(t, matched, true)
+
+	matched, _ = Parse("{v:date}").Match("date", nil)
+	checkValue(t, matched, false)
+}
+
 // -------------------------
 
 func TestTemplate_SetName(t *testing.T) {
@@ -394,6 +592,94 @@ func TestTemplate_IsWildCard(t *testing.T) {
 	}
 }
 
+func TestTemplate_IsCatchAll(t *testing.T) {
+	var tmpl = Parse("{filepath...}")
+	checkValue(t, tmpl.IsCatchAll(), true)
+	checkValue(t, tmpl.IsWildcard(), true)
+	checkValue(t, tmpl.Name(), "filepath")
+	checkValue(t, tmpl.ValueNames(), []string{"filepath"})
+	checkValue(t, tmpl.Content(), "{filepath...}")
+	checkValue(t, tmpl.UnescapedContent(), "{filepath...}")
+	checkValue(t, tmpl.String(), "$filepath:{filepath...}")
+	checkValue(
+		t,
+		Parse("$files:{filepath...}").String(),
+		"$files:{filepath...}",
+	)
+
+	var matched, values = tmpl.Match("a/b c/d", nil)
+	checkValue(t, matched, true)
+	checkValue(t, values.Get("filepath"), "a/b c/d")
+
+	checkValue(
+		t,
+		tmpl.Apply(TemplateValues{{"filepath", "a/b"}}, false),
+		"a/b",
+	)
+
+	checkValue(t, tmpl.SimilarityWith(Parse("{filepath...}")), TheSame)
+	checkValue(t, tmpl.SimilarityWith(Parse("{filepath}")), Different)
+	checkValue(t, Parse("{filepath}").SimilarityWith(tmpl), Different)
+	checkValue(t, Parse("{filepath}").IsCatchAll(), false)
+
+	var _, err = TryToParse("{...}")
+	checkErr(t, err, true)
+
+	_, err = TryToParse("static{filepath...}")
+	checkErr(t, err, true)
+
+	_, err = TryToParse("{id:\\d+}{filepath...}")
+	checkErr(t, err, true)
+
+	tmpl.Clear()
+	checkValue(t, tmpl.IsCatchAll(), false)
+}
+
+func TestTemplate_IsOptional(t *testing.T) {
+	var tmpl = Parse("{lang?=en}")
+	checkValue(t, tmpl.IsOptional(), true)
+	checkValue(t, tmpl.IsWildcard(), true)
+	checkValue(t, tmpl.DefaultValue(), "en")
+	checkValue(t, tmpl.Name(), "lang")
+	checkValue(t, tmpl.Content(), "{lang?=en}")
+	checkValue(t, tmpl.UnescapedContent(), "{lang?=en}")
+
+	var matched, values = tmpl.Match("fr", nil)
+	checkValue(t, matched, true)
+	checkValue(t, values.Get("lang"), "fr")
+
+	checkValue(t, tmpl.Apply(TemplateValues{{"lang", "fr"}}, false), "fr")
+	checkValue(t, tmpl.Apply(TemplateValues{{"lang", "en"}}, false), "")
+	checkValue(t, tmpl.Apply(nil, false), "")
+
+	checkValue(t, tmpl.SimilarityWith(Parse("{lang?=en}")), TheSame)
+	checkValue(t, tmpl.SimilarityWith(Parse("{lang?=fr}")), Different)
+	checkValue(t, tmpl.SimilarityWith(Parse("{lang}")), Different)
+
+	tmpl = Parse("{lang?}")
+	checkValue(t, tmpl.IsOptional(), true)
+	checkValue(t, tmpl.DefaultValue(), "")
+	checkValue(t, tmpl.Content(), "{lang?}")
+
+	tmpl = Parse(`{time?=12\:00}`)
+	checkValue(t, tmpl.DefaultValue(), "12:00")
+	checkValue(t, tmpl.Content(), `{time?=12\:00}`)
+	checkValue(t, Parse(tmpl.Content()).DefaultValue(), "12:00")
+
+	var _, err = TryToParse("{?=en}")
+	checkErr(t, err, true)
+
+	_, err = TryToParse("{lang?en}")
+	checkErr(t, err, true)
+
+	_, err = TryToParse("static{lang?=en}")
+	checkErr(t, err, true)
+
+	tmpl.Clear()
+	checkValue(t, tmpl.IsOptional(), false)
+	checkValue(t, tmpl.DefaultValue(), "")
+}
+
 func TestTemplate_HasPattern(t *testing.T) {
 	var cases = []struct {
 		name string
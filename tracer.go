@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"context"
+	"net/http"
+)
+
+// --------------------------------------------------
+
+// Tracer is the interface of the hooks the Router calls while it's routing
+// and handling a request. It can be used to trace the time spent on routing,
+// the redirect decisions, and the time spent in the handlers. The hooks are
+// called only when the request is served by the Router.
+//
+// The context passed to the hooks is the request's context. The contexts
+// returned from the StartRouting and StartHandler hooks replace the request's
+// context, so spans can be carried in them.
+type Tracer interface {
+	// StartRouting is called when the Router starts routing the request,
+	// before the request is passed to any host or resource.
+	StartRouting(ctx context.Context, r *http.Request) context.Context
+
+	// MatchedResponder is called each time the host or resource in the
+	// args matches the request's URL segment and receives the request.
+	MatchedResponder(ctx context.Context, args *Args)
+
+	// StartHandler is called before the request handler of the host or
+	// resource in the args is called. The routing is done at this point.
+	// It's not called when the request is redirected or not found.
+	StartHandler(ctx context.Context, args *Args) context.Context
+
+	// End is called when the request was responded to. It's also called
+	// when one of the handlers panics. The ctx is the one returned from the
+	// StartRouting. The args' Redirected, NotFound, and MethodNotAllowed
+	// methods report how the request was responded to.
+	End(ctx context.Context, args *Args)
+}
+
+// NopTracer is a Tracer whose hooks do nothing. It's the Router's default
+// tracer and can be embedded in the tracers that implement only some of
+// the hooks.
+type NopTracer struct{}
+
+func (NopTracer) StartRouting(
+	ctx context.Context,
+	_ *http.Request,
+) context.Context {
+	return ctx
+}
+
+func (NopTracer) MatchedResponder(context.Context, *Args) {}
+
+func (NopTracer) StartHandler(ctx context.Context, _ *Args) context.Context {
+	return ctx
+}
+
+func (NopTracer) End(context.Context, *Args) {}
+
+// -------------------------
+
+// SetTracer sets the tracer whose hooks the router calls while it's routing
+// and handling the requests.
+func (ro *Router) SetTracer(t Tracer) {
+	if err := ro.TryToSetTracer(t); err != nil {
+		panicWithErr("%w", err)
+	}
+}
+
+// TryToSetTracer is like SetTracer but returns an error instead of panicking.
+//
+// NopTracer and *NopTracer unset the tracer, so the hooks are not called at
+// all.
+func (ro *Router) TryToSetTracer(t Tracer) error {
+	if err := ro.checkChangeable(); err != nil {
+		return newErr("%w", err)
+	}
+
+	if t == nil {
+		return newErr("%w", ErrNilArgument)
+	}
+
+	switch t.(type) {
+	case NopTracer, *NopTracer:
+		// The nil tracer is not called at all.
+		t = nil
+	}
+
+	ro.tracer = t
+	return nil
+}
+
+// Tracer returns the router's tracer. If the tracer wasn't set, NopTracer
+// is returned.
+func (ro *Router) Tracer() Tracer {
+	if ro.tracer == nil {
+		return NopTracer{}
+	}
+
+	return ro.tracer
+}
+
+// -------------------------
+
+// matchedResponder is called each time the host or resource in the args
+// matches the segment of the request's URL. It adds the step to the
+// resolution when the request is being resolved and calls the
+// MatchedResponder hook of the tracer in the args, if there is one.
+func (args *Args) matchedResponder(r *http.Request, segment string) {
+	if args.resolution != nil {
+		args.resolution.addStep(segment, args._r)
+	}
+
+	if args.tracer != nil {
+		args.tracer.MatchedResponder(r.Context(), args)
+	}
+}
+
+// traceHandler calls the StartHandler hook of the tracer in the args, if
+// there is one, and returns the request with the context returned from it.
+func (args *Args) traceHandler(r *http.Request) *http.Request {
+	if args.tracer == nil {
+		return r
+	}
+
+	return r.WithContext(args.tracer.StartHandler(r.Context(), args))
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// --------------------------------------------------
+
+type _TracerKey struct{}
+
+// _Tracer records the hook calls.
+type _Tracer struct {
+	NopTracer
+	calls []string
+}
+
+func (t *_Tracer) StartRouting(
+	ctx context.Context,
+	r *http.Request,
+) context.Context {
+	t.calls = append(t.calls, "routing")
+	return context.WithValue(ctx, _TracerKey{}, "routing")
+}
+
+func (t *_Tracer) MatchedResponder(ctx context.Context, args *Args) {
+	t.calls = append(t.calls, "matched "+args.MatchedTemplate())
+}
+
+func (t *_Tracer) StartHandler(
+	ctx context.Context,
+	args *Args,
+) context.Context {
+	var v, _ = ctx.Value(_TracerKey{}).(string)
+	t.calls = append(t.calls, "handler after "+v)
+	return context.WithValue(ctx, _TracerKey{}, "handler")
+}
+
+func (t *_Tracer) End(ctx context.Context, args *Args) {
+	var v, _ = ctx.Value(_TracerKey{}).(string)
+	switch {
+	case args.Redirected():
+		v += " redirected"
+	case args.NotFound():
+		v += " not found"
+	}
+
+	t.calls = append(t.calls, "end "+v)
+}
+
+func TestRouter_SetTracer(t *testing.T) {
+	var tracer = &_Tracer{}
+	var ctxValue string
+	var hr = func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		ctxValue, _ = r.Context().Value(_TracerKey{}).(string)
+		return true
+	}
+
+	var ro = NewRouter()
+	checkValue(t, ro.Tracer(), Tracer(NopTracer{}))
+
+	ro.SetURLHandlerFor("GET", "http://example.com/a/{b}", hr)
+	ro.SetURLHandlerFor("GET", "http://example.com/c/", hr)
+	ro.SetURLHandlerFor(
+		"GET",
+		"http://example.com/panic",
+		func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			panic(fmt.Errorf("handler"))
+		},
+	)
+
+	ro.SetTracer(tracer)
+	checkValue(t, ro.Tracer(), Tracer(tracer))
+
+	serve(ro, "GET", "http://example.com/a/1")
+	checkValue(t, ctxValue, "handler")
+	checkValue(t, tracer.calls, []string{
+		"routing",
+		"matched http://example.com",
+		"matched http://example.com/a",
+		"matched http://example.com/a/{b}",
+		"handler after routing",
+		"end routing",
+	})
+
+	tracer.calls = nil
+	serve(ro, "GET", "http://example.com/c")
+	checkValue(t, tracer.calls, []string{
+		"routing",
+		"matched http://example.com",
+		"matched http://example.com/c/",
+		"end routing redirected",
+	})
+
+	tracer.calls = nil
+	serve(ro, "GET", "http://example.com/d")
+	checkValue(t, tracer.calls, []string{
+		"routing",
+		"matched http://example.com",
+		"end routing not found",
+	})
+
+	// The tracer is kept in the snapshot.
+	checkErr(t, ro.Update(func(*RouterTx) error { return nil }), false)
+	tracer.calls = nil
+	serve(ro, "GET", "http://example.com/a/1")
+	checkValue(t, len(tracer.calls), 6)
+
+	// The End hook is called even if the handler panics.
+	tracer.calls = nil
+	testPanicker(t, true, func() {
+		serve(ro, "GET", "http://example.com/panic")
+	})
+
+	checkValue(t, tracer.calls, []string{
+		"routing",
+		"matched http://example.com",
+		"matched http://example.com/panic",
+		"handler after routing",
+		"end routing",
+	})
+
+	var err = ro.Update(func(tx *RouterTx) error {
+		tx.SetTracer(NopTracer{})
+		return nil
+	})
+
+	checkErr(t, err, false)
+	tracer.calls = nil
+	serve(ro, "GET", "http://example.com/a/1")
+	checkValue(t, len(tracer.calls), 0)
+	checkValue(t, ro.Tracer(), Tracer(NopTracer{}))
+
+	err = ro.Update(func(tx *RouterTx) error {
+		tx.SetTracer(&NopTracer{})
+		return nil
+	})
+
+	checkErr(t, err, false)
+	checkValue(t, ro.Tracer(), Tracer(NopTracer{}))
+
+	testPanicker(t, true, func() { ro.SetTracer(nil) })
+	checkValue(t, errors.Is(ro.TryToSetTracer(tracer), ErrOutsideUpdate), true)
+}
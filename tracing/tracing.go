@@ -0,0 +1,216 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+// Package tracing adapts a tracer with the shape of the OpenTelemetry
+// tracing API to the nanomux.Tracer hooks.
+//
+// The package doesn't depend on OpenTelemetry. Its Tracer and Span
+// interfaces have the shape of the trace.Tracer and trace.Span of the
+// OpenTelemetry API, so a thin wrapper is enough to use them.
+//
+// Each request gets a routing span that lasts until the request handler
+// is called and a handler span that lasts until the request is responded
+// to. The routing span has an event for each matched host and resource. When
+// the request is redirected or not found, there is only a routing span.
+//
+// Example:
+// 	type otelTracer struct{ trace.Tracer }
+//
+// 	func (t otelTracer) Start(
+// 		ctx context.Context,
+// 		name string,
+// 	) (context.Context, tracing.Span) {
+// 		var c, s = t.Tracer.Start(ctx, name)
+// 		return c, otelSpan{s}
+// 	}
+//
+// 	type otelSpan struct{ trace.Span }
+//
+// 	func (s otelSpan) SetAttributes(attrs ...tracing.Attribute) {
+// 		// Convert the attrs to attribute.KeyValue.
+// 	}
+//
+// 	func (s otelSpan) AddEvent(name string, attrs ...tracing.Attribute) {
+// 		// Convert the attrs to attribute.KeyValue.
+// 	}
+//
+// 	func (s otelSpan) End() { s.Span.End() }
+//
+// 	// ...
+//
+// 	router.SetTracer(tracing.New(otelTracer{otel.Tracer("nanomux")}))
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/shohruhadham/nanomux"
+)
+
+// --------------------------------------------------
+
+// Names of the spans, events, and attributes.
+const (
+	RoutingSpanName = "nanomux.routing"
+	HandlerSpanName = "nanomux.handler"
+
+	MatchedResponderEventName = "nanomux.matched_responder"
+
+	MethodKey           = "http.method"
+	TargetKey           = "http.target"
+	RouteKey            = "http.route"
+	RedirectedKey       = "nanomux.redirected"
+	NotFoundKey         = "nanomux.not_found"
+	MethodNotAllowedKey = "nanomux.method_not_allowed"
+)
+
+// Attribute is a key-value pair of a span or event.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span has the shape of the OpenTelemetry API's trace.Span.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	AddEvent(name string, attrs ...Attribute)
+	End()
+}
+
+// Tracer has the shape of the OpenTelemetry API's trace.Tracer.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// --------------------------------------------------
+
+type _TraceKey struct{}
+
+// traceKey is used to retrieve the *_Trace from the request's context.
+var traceKey interface{} = _TraceKey{}
+
+// _Trace keeps the spans of the request and the context the routing span was
+// started from.
+type _Trace struct {
+	parent  context.Context
+	routing Span
+	handler Span
+}
+
+// traceFrom returns the *_Trace from the context. If there isn't one, nil
+// is returned.
+func traceFrom(ctx context.Context) *_Trace {
+	var t, _ = ctx.Value(traceKey).(*_Trace)
+	return t
+}
+
+// -------------------------
+
+// _HandlerContext is the request's context with the handler span. The values
+// are looked up first in the context the handler span was started with, so
+// the handler span replaces the routing span, and then in the request's
+// context, so the values added to it while routing are kept.
+type _HandlerContext struct {
+	context.Context
+	span context.Context
+}
+
+func (c *_HandlerContext) Value(key interface{}) interface{} {
+	if v := c.span.Value(key); v != nil {
+		return v
+	}
+
+	return c.Context.Value(key)
+}
+
+// --------------------------------------------------
+
+// _Hooks implements the nanomux.Tracer with the Tracer.
+type _Hooks struct {
+	t Tracer
+}
+
+// New returns the nanomux.Tracer that starts the spans with the tracer. It
+// panics with an error wrapping the nanomux.ErrNilArgument if the tracer is
+// nil.
+func New(t Tracer) nanomux.Tracer {
+	if t == nil {
+		panic(fmt.Errorf("tracing: nil tracer: %w", nanomux.ErrNilArgument))
+	}
+
+	return &_Hooks{t: t}
+}
+
+func (h *_Hooks) StartRouting(
+	ctx context.Context,
+	r *http.Request,
+) context.Context {
+	var t = &_Trace{parent: ctx}
+	ctx, t.routing = h.t.Start(ctx, RoutingSpanName)
+	t.routing.SetAttributes(
+		Attribute{MethodKey, r.Method},
+		Attribute{TargetKey, r.URL.RequestURI()},
+	)
+
+	return context.WithValue(ctx, traceKey, t)
+}
+
+func (h *_Hooks) MatchedResponder(ctx context.Context, args *nanomux.Args) {
+	if t := traceFrom(ctx); t != nil && t.routing != nil {
+		t.routing.AddEvent(
+			MatchedResponderEventName,
+			Attribute{RouteKey, args.MatchedTemplate()},
+		)
+	}
+}
+
+func (h *_Hooks) StartHandler(
+	ctx context.Context,
+	args *nanomux.Args,
+) context.Context {
+	var t = traceFrom(ctx)
+	if t == nil {
+		return ctx
+	}
+
+	var route = Attribute{RouteKey, args.MatchedTemplate()}
+	if t.routing != nil {
+		t.routing.SetAttributes(route)
+		t.routing.End()
+		t.routing = nil
+	}
+
+	// The handler span is the sibling of the routing span.
+	var sctx context.Context
+	sctx, t.handler = h.t.Start(t.parent, HandlerSpanName)
+	t.handler.SetAttributes(route)
+	return &_HandlerContext{Context: ctx, span: sctx}
+}
+
+func (h *_Hooks) End(ctx context.Context, args *nanomux.Args) {
+	var t = traceFrom(ctx)
+	if t == nil {
+		return
+	}
+
+	var s = t.handler
+	if s == nil {
+		s = t.routing
+	}
+
+	if s == nil {
+		return
+	}
+
+	s.SetAttributes(
+		Attribute{RouteKey, args.MatchedTemplate()},
+		Attribute{RedirectedKey, args.Redirected()},
+		Attribute{NotFoundKey, args.NotFound()},
+		Attribute{MethodNotAllowedKey, args.MethodNotAllowed()},
+	)
+
+	s.End()
+	t.routing, t.handler = nil, nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/shohruhadham/nanomux"
+)
+
+// --------------------------------------------------
+
+type _SpanKey struct{}
+
+// _Span records the attributes and events of the span.
+type _Span struct {
+	name   string
+	parent *_Span
+	attrs  map[string]interface{}
+	events []string
+	ended  bool
+}
+
+func (s *_Span) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *_Span) AddEvent(name string, attrs ...Attribute) {
+	var e = name
+	for _, a := range attrs {
+		e += fmt.Sprintf(" %s=%v", a.Key, a.Value)
+	}
+
+	s.events = append(s.events, e)
+}
+
+func (s *_Span) End() {
+	s.ended = true
+}
+
+// _Tracer records the started spans.
+type _Tracer struct {
+	spans []*_Span
+}
+
+func (t *_Tracer) Start(
+	ctx context.Context,
+	name string,
+) (context.Context, Span) {
+	var p, _ = ctx.Value(_SpanKey{}).(*_Span)
+	var s = &_Span{name: name, parent: p, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, _SpanKey{}, s), s
+}
+
+func checkValue(t *testing.T, v, want interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("got %v, want %v", v, want)
+	}
+}
+
+func TestNew(t *testing.T) {
+	var tracer = &_Tracer{}
+	var handlerSpan *_Span
+	var hr = func(
+		w http.ResponseWriter,
+		r *http.Request,
+		args *nanomux.Args,
+	) bool {
+		handlerSpan, _ = r.Context().Value(_SpanKey{}).(*_Span)
+		return true
+	}
+
+	var ro = nanomux.NewRouter()
+	ro.SetURLHandlerFor("GET", "http://example.com/users/{id}", hr)
+	ro.SetTracer(New(tracer))
+
+	var serve = func(method, url string) {
+		ro.ServeHTTP(
+			httptest.NewRecorder(),
+			httptest.NewRequest(method, url, nil),
+		)
+	}
+
+	var root = &_Span{name: "root"}
+	var r = httptest.NewRequest("GET", "http://example.com/users/1?a=b", nil)
+	r = r.WithContext(context.WithValue(r.Context(), _SpanKey{}, root))
+	ro.ServeHTTP(httptest.NewRecorder(), r)
+	checkValue(t, len(tracer.spans), 2)
+
+	var routing, handler = tracer.spans[0], tracer.spans[1]
+	checkValue(t, routing.name, RoutingSpanName)
+	checkValue(t, routing.parent, root)
+	checkValue(t, routing.ended, true)
+	checkValue(t, routing.attrs[MethodKey], "GET")
+	checkValue(t, routing.attrs[TargetKey], "/users/1?a=b")
+	checkValue(t, routing.attrs[RouteKey], "http://example.com/users/{id}")
+
+	var event = MatchedResponderEventName + " http.route="
+	checkValue(t, routing.events, []string{
+		event + "http://example.com",
+		event + "http://example.com/users",
+		event + "http://example.com/users/{id}",
+	})
+
+	checkValue(t, handler.name, HandlerSpanName)
+	checkValue(t, handler.parent, root)
+	checkValue(t, handler.ended, true)
+	checkValue(t, handlerSpan, handler)
+	checkValue(t, handler.attrs[RouteKey], "http://example.com/users/{id}")
+	checkValue(t, handler.attrs[RedirectedKey], false)
+	checkValue(t, handler.attrs[NotFoundKey], false)
+	checkValue(t, handler.attrs[MethodNotAllowedKey], false)
+
+	tracer.spans = nil
+	serve("DELETE", "http://example.com/users/1")
+	checkValue(t, len(tracer.spans), 2)
+	checkValue(t, tracer.spans[1].attrs[MethodNotAllowedKey], true)
+
+	tracer.spans = nil
+	serve("GET", "http://example.com/users/1/")
+	checkValue(t, len(tracer.spans), 1)
+	checkValue(t, tracer.spans[0].ended, true)
+	checkValue(t, tracer.spans[0].attrs[RedirectedKey], true)
+
+	tracer.spans = nil
+	serve("GET", "http://example.com/posts")
+	checkValue(t, len(tracer.spans), 1)
+	checkValue(t, tracer.spans[0].attrs[NotFoundKey], true)
+	checkValue(t, tracer.spans[0].attrs[RouteKey], "http://example.com")
+
+	defer func() {
+		var err, _ = recover().(error)
+		if !errors.Is(err, nanomux.ErrNilArgument) {
+			t.Fatalf("New: got %v on nil tracer, want ErrNilArgument", err)
+		}
+	}()
+
+	New(nil)
+}
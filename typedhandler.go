@@ -0,0 +1,417 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// --------------------------------------------------
+
+// Validator can be implemented by the request types of the typed handlers.
+// The Validate method is called after the request is decoded and bound. The
+// returned error is rendered with the "400 Bad Request" status code unless
+// it's an *HTTPError.
+type Validator interface {
+	Validate() error
+}
+
+// StatusCoder can be implemented by the response types of the typed handlers
+// to set the status code of the response. By default, the status code is 200.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// -------------------------
+
+// JSON converts the typed function to a nanomux.Handler. The returned handler
+// decodes the request's body into a value of the Req type depending on the
+// request's "Content-Type" header, binds the host and path values and the
+// query values to the fields of the Req, calls the fn and encodes the
+// returned Resp as JSON.
+//
+// The body is decoded as JSON when its media type is "application/json" or
+// has the "+json" suffix. The form bodies are bound to the fields with the
+// "form" tag. The bodies of other media types are rejected with the
+// "415 Unsupported Media Type" status code. The host and path values are
+// bound to the fields with the "path" tag, and the query values to the
+// fields with the "query" tag. Fields can be of string, bool, integer, and
+// float kinds, slices of them, or implement the encoding.TextUnmarshaler
+// interface. When the dynamic segment's value has a registered converter,
+// the converted value is used if it's assignable to the field.
+//
+// Decoding and binding errors are rendered with the "400 Bad Request" status
+// code. The errors returned from the fn are rendered with the error renderer
+// of the responder. When the Resp is a nil pointer or interface, the
+// "204 No Content" status code is sent. A nil slice or map is encoded as an
+// empty JSON array or object.
+//
+// Example:
+// 	type GetUserRequest struct {
+// 		ID     int  `path:"id"`
+// 		Detail bool `query:"detail"`
+// 	}
+//
+// 	// ...
+//
+// 	users.SetPathHandlerFor("GET", "{id:int}", nanomux.JSON(GetUser))
+func JSON[Req, Resp any](
+	fn func(ctx context.Context, req Req, args *Args) (Resp, error),
+) Handler {
+	if fn == nil {
+		panicWithErr("%w", ErrNilArgument)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		var req Req
+		var err = decodeRequest(r, args, &req)
+		if err == nil {
+			err = validateRequest(&req)
+		}
+
+		if err != nil {
+			return handleErr(w, r, args, err)
+		}
+
+		var resp Resp
+		resp, err = fn(r.Context(), req, args)
+		if err != nil {
+			return handleErr(w, r, args, err)
+		}
+
+		return encodeResponse(w, r, args, resp)
+	}
+}
+
+// --------------------------------------------------
+
+// decodeRequest decodes the request's body into the v and binds the host,
+// path, and query values to it.
+func decodeRequest(r *http.Request, args *Args, v interface{}) error {
+	var err = decodeBody(r, v)
+	if err != nil {
+		return err
+	}
+
+	var rv = reflect.ValueOf(v).Elem()
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var query = r.URL.Query()
+	return bindFields(rv, func(f reflect.StructField, fv reflect.Value) error {
+		if name, ok := f.Tag.Lookup("path"); ok {
+			return bindPathValue(args, name, fv)
+		}
+
+		if name, ok := f.Tag.Lookup("query"); ok {
+			if vs, ok := query[name]; ok {
+				return setField(fv, vs, name)
+			}
+		}
+
+		if name, ok := f.Tag.Lookup("form"); ok && r.PostForm != nil {
+			if vs, ok := r.PostForm[name]; ok {
+				return setField(fv, vs, name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// validateRequest calls the Validate method of the request if the request or
+// the pointer to it implements the Validator interface.
+func validateRequest[Req any](req *Req) error {
+	var v, ok = interface{}(*req).(Validator)
+	if !ok {
+		if v, ok = interface{}(req).(Validator); !ok {
+			return nil
+		}
+	}
+
+	var err = v.Validate()
+	if err != nil {
+		var he *HTTPError
+		if !errors.As(err, &he) {
+			return &HTTPError{Status: http.StatusBadRequest, Err: err}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// decodeBody decodes the request's body into the v depending on the body's
+// media type.
+func decodeBody(r *http.Request, v interface{}) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+
+	var ct = r.Header.Get("Content-Type")
+	if ct == "" {
+		return &HTTPError{Status: http.StatusUnsupportedMediaType}
+	}
+
+	var mt, _, err = mime.ParseMediaType(ct)
+	if err != nil {
+		return &HTTPError{Status: http.StatusBadRequest, Err: err}
+	}
+
+	switch {
+	case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+		err = json.NewDecoder(r.Body).Decode(v)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return &HTTPError{Status: http.StatusBadRequest, Err: err}
+		}
+	case mt == "application/x-www-form-urlencoded":
+		err = r.ParseForm()
+		if err != nil {
+			return &HTTPError{Status: http.StatusBadRequest, Err: err}
+		}
+	case mt == "multipart/form-data":
+		err = r.ParseMultipartForm(32 << 20)
+		if err != nil {
+			return &HTTPError{Status: http.StatusBadRequest, Err: err}
+		}
+	default:
+		return &HTTPError{Status: http.StatusUnsupportedMediaType}
+	}
+
+	return nil
+}
+
+// bindFields calls the bind function for each exported field of the struct
+// value, including the fields of the embedded structs.
+func bindFields(
+	rv reflect.Value,
+	bind func(reflect.StructField, reflect.Value) error,
+) error {
+	var rt = rv.Type()
+	for i, nf := 0, rt.NumField(); i < nf; i++ {
+		var f = rt.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			var err = bindFields(rv.Field(i), bind)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		var err = bind(f, rv.Field(i))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindPathValue sets the host or path value of the name to the field. If the
+// value's converter returns a value assignable to the field, it's used.
+func bindPathValue(args *Args, name string, fv reflect.Value) error {
+	if args == nil {
+		return nil
+	}
+
+	var i, str = args.hostPathValues.get(name)
+	if i < 0 {
+		return nil
+	}
+
+	var v, err = args.PathValue(name)
+	if err != nil {
+		return &HTTPError{Status: http.StatusBadRequest, Err: err}
+	}
+
+	if _, ok := v.(string); !ok {
+		var cv = reflect.ValueOf(v)
+		if cv.Type().AssignableTo(fv.Type()) {
+			fv.Set(cv)
+			return nil
+		}
+	}
+
+	return setField(fv, []string{str}, name)
+}
+
+// setField parses the values and sets them to the field.
+func setField(fv reflect.Value, vs []string, name string) error {
+	if len(vs) == 0 {
+		return nil
+	}
+
+	var err error
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+		var s = reflect.MakeSlice(fv.Type(), len(vs), len(vs))
+		for i, v := range vs {
+			if err = setValue(s.Index(i), v); err != nil {
+				break
+			}
+		}
+
+		if err == nil {
+			fv.Set(s)
+		}
+	} else {
+		err = setValue(fv, vs[0])
+	}
+
+	if err != nil {
+		return &HTTPError{
+			Status: http.StatusBadRequest,
+			Err:    newErr("%w for %q: %v", ErrInvalidValue, name, err),
+		}
+	}
+
+	return nil
+}
+
+// setValue parses the string and sets it to the value.
+func setValue(rv reflect.Value, str string) error {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		return setValue(rv.Elem(), str)
+	}
+
+	if tu, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(str))
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(str)
+	case reflect.Bool:
+		var b, err = strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n, err = strconv.ParseInt(str, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		var n, err = strconv.ParseUint(str, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		rv.SetUint(n)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return newErr("unsupported kind %s", rv.Kind())
+		}
+
+		rv.SetBytes([]byte(str))
+	case reflect.Float32, reflect.Float64:
+		var n, err = strconv.ParseFloat(str, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		rv.SetFloat(n)
+	default:
+		return newErr("unsupported kind %s", rv.Kind())
+	}
+
+	return nil
+}
+
+// -------------------------
+
+// encodeResponse encodes the resp as JSON. If the resp is a nil pointer or
+// interface, the "204 No Content" status code is sent. A nil slice or map is
+// encoded as an empty JSON array or object.
+func encodeResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	args *Args,
+	resp interface{},
+) bool {
+	if isNil(resp) {
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+
+	var status = http.StatusOK
+	if sc, ok := resp.(StatusCoder); ok {
+		status = sc.StatusCode()
+	}
+
+	var b, err = marshalResponse(resp)
+	if err != nil {
+		return handleErr(w, r, args, err)
+	}
+
+	var h = w.Header()
+	h.Set("Content-Type", "application/json")
+	h.Set("Content-Length", strconv.Itoa(len(b)+1))
+	w.WriteHeader(status)
+	w.Write(b)
+	w.Write([]byte{'\n'})
+	return true
+}
+
+// marshalResponse returns the JSON encoding of the resp. Unlike the
+// json.Marshal function, it encodes a nil slice or map as an empty JSON array
+// or object.
+func marshalResponse(resp interface{}) ([]byte, error) {
+	var rv = reflect.ValueOf(resp)
+	switch {
+	case rv.Kind() == reflect.Slice && rv.IsNil():
+		return []byte("[]"), nil
+	case rv.Kind() == reflect.Map && rv.IsNil():
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(resp)
+}
+
+// isNil returns true if the v is nil or a nil pointer or interface.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	var rv = reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+
+	return false
+}
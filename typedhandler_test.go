@@ -0,0 +1,222 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --------------------------------------------------
+
+type _UpdateUserRequest struct {
+	ID      int       `path:"id"`
+	Since   time.Time `path:"since"`
+	Notify  bool      `query:"notify"`
+	Tags    []string  `query:"tag"`
+	Name    string    `json:"name" form:"name"`
+	Age     *uint8    `json:"age" form:"age"`
+	private string
+}
+
+func (req *_UpdateUserRequest) Validate() error {
+	if req.Name == "" {
+		return fmt.Errorf("empty name")
+	}
+
+	if req.Name == "root" {
+		return NewHTTPError(http.StatusForbidden, "")
+	}
+
+	return nil
+}
+
+type _UpdateUserResponse struct {
+	ID      int      `json:"id"`
+	Since   string   `json:"since"`
+	Name    string   `json:"name"`
+	Age     uint8    `json:"age"`
+	Notify  bool     `json:"notify"`
+	Tags    []string `json:"tags"`
+	created bool
+}
+
+func (resp *_UpdateUserResponse) StatusCode() int {
+	if resp.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func TestJSON(t *testing.T) {
+	testPanicker(t, true, func() {
+		JSON[struct{}, struct{}](nil)
+	})
+
+	var ro = NewRouter()
+	ro.SetURLHandlerFor(
+		"PUT",
+		"http://example.com/users/{id:int}/{since:date}",
+		JSON(func(
+			_ context.Context,
+			req _UpdateUserRequest,
+			_ *Args,
+		) (*_UpdateUserResponse, error) {
+			if req.ID == 0 {
+				return nil, nil
+			}
+
+			if req.ID == 500 {
+				return nil, fmt.Errorf("database is down")
+			}
+
+			var resp = &_UpdateUserResponse{
+				ID:      req.ID,
+				Since:   req.Since.Format("2006-01-02"),
+				Name:    req.Name,
+				Notify:  req.Notify,
+				Tags:    req.Tags,
+				created: req.ID > 100,
+			}
+
+			if req.Age != nil {
+				resp.Age = *req.Age
+			}
+
+			return resp, nil
+		}),
+	)
+
+	var cases = []struct {
+		name, url, contentType, body string
+		wantStatusCode               int
+		wantBody                     string
+	}{
+		{
+			"json",
+			"http://example.com/users/1/2022-05-01?notify=true&tag=a&tag=b",
+			"application/json; charset=utf-8",
+			`{"name":"John","age":30}`,
+			http.StatusOK,
+			`{"id":1,"since":"2022-05-01","name":"John","age":30,"notify":true,"tags":["a","b"]}` + "\n",
+		},
+		{
+			"form",
+			"http://example.com/users/101/2022-05-01",
+			"application/x-www-form-urlencoded",
+			"name=Jane&age=25",
+			http.StatusCreated,
+			`{"id":101,"since":"2022-05-01","name":"Jane","age":25,"notify":false,"tags":null}` + "\n",
+		},
+		{
+			"no content",
+			"http://example.com/users/0/2022-05-01",
+			"application/json",
+			`{"name":"John"}`,
+			http.StatusNoContent,
+			"",
+		},
+		{
+			"invalid json",
+			"http://example.com/users/1/2022-05-01",
+			"application/json",
+			`{"name":`,
+			http.StatusBadRequest,
+			"Bad Request\n",
+		},
+		{
+			"invalid query value",
+			"http://example.com/users/1/2022-05-01?notify=maybe",
+			"application/json",
+			`{"name":"John"}`,
+			http.StatusBadRequest,
+			"Bad Request\n",
+		},
+		{
+			"unsupported media type",
+			"http://example.com/users/1/2022-05-01",
+			"text/plain",
+			"John",
+			http.StatusUnsupportedMediaType,
+			"Unsupported Media Type\n",
+		},
+		{
+			"validation error",
+			"http://example.com/users/1/2022-05-01",
+			"application/json",
+			`{"age":30}`,
+			http.StatusBadRequest,
+			"Bad Request\n",
+		},
+		{
+			"validation HTTPError",
+			"http://example.com/users/1/2022-05-01",
+			"application/json",
+			`{"name":"root"}`,
+			http.StatusForbidden,
+			"Forbidden\n",
+		},
+		{
+			"handler error",
+			"http://example.com/users/500/2022-05-01",
+			"application/json",
+			`{"name":"John"}`,
+			http.StatusInternalServerError,
+			"Internal Server Error\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			var r = httptest.NewRequest("PUT", c.url, strings.NewReader(c.body))
+			r.Header.Set("Content-Type", c.contentType)
+			ro.ServeHTTP(w, r)
+			checkValue(t, w.Code, c.wantStatusCode)
+			checkValue(t, w.Body.String(), c.wantBody)
+		})
+	}
+
+	ro.SetURLHandlerFor(
+		"GET",
+		"http://example.com/tags",
+		JSON(func(context.Context, struct{}, *Args) ([]string, error) {
+			return nil, nil
+		}),
+	)
+
+	var w = serve(ro, "GET", "http://example.com/tags")
+	checkValue(t, w.Code, http.StatusOK)
+	checkValue(t, w.Body.String(), "[]\n")
+
+	// Encoding errors are seen by the middlewares.
+	var mwErr error
+	ro.SetURLHandlerFor(
+		"GET",
+		"http://example.com/chan",
+		JSON(func(context.Context, struct{}, *Args) (chan int, error) {
+			return make(chan int), nil
+		}),
+	)
+
+	var mw = func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			var handled = next(w, r, args)
+			mwErr = args.Err()
+			return handled
+		}
+	}
+
+	ro.WrapURLHandlerOf("GET", "http://example.com/chan", mw)
+
+	w = serve(ro, "GET", "http://example.com/chan")
+	checkValue(t, w.Code, http.StatusInternalServerError)
+	checkValue(t, mwErr != nil, true)
+}
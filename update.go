@@ -0,0 +1,285 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+// --------------------------------------------------
+
+// RouterTx is passed to the function of the Router's Update method. It gives
+// access to the router's tree while the update is in progress. RouterTx must
+// not be used after the function returns.
+type RouterTx struct {
+	*Router
+}
+
+// Update calls the function with a copy of the router's tree and, if the
+// function returns nil, publishes the copy atomically. The requests that are
+// being served continue on the previous tree, and the new requests are served
+// by the new one. Updates are serialized, so only one function runs at a time.
+//
+// If the function returns an error or panics, the copy is discarded and the
+// router's tree is left as it was. The error is returned, and the panic is
+// propagated.
+//
+// After an update, the router's methods return the hosts and resources of the
+// published tree, and the requests are served by them. The hosts and
+// resources retrieved from the router before the update are not in the tree
+// anymore and must be retrieved again.
+//
+// Once the first update is published, the router's tree can be changed only
+// through the RouterTx passed to the function. The methods that change the
+// router, its hosts, or its resources outside the function return
+// ErrOutsideUpdate, or panic with it if they don't have a TryTo counterpart.
+//
+// Example:
+// 	var err = router.Update(func(tx *nanomux.RouterTx) error {
+// 		tx.SetURLHandlerFor("get", "http://example.com/plugin", handler)
+// 		tx.UnregisterResourceAt("http://example.com/legacy")
+// 		return nil
+// 	})
+func (ro *Router) Update(fn func(tx *RouterTx) error) error {
+	if fn == nil {
+		panicWithErr("%w", ErrNilArgument)
+	}
+
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+
+	var c = ro.clone()
+	c.origin = ro
+
+	// Once the function returns, the copy can be changed only by a new update.
+	defer c.live.Store(c)
+
+	var err = fn(&RouterTx{c})
+	if err != nil {
+		return err
+	}
+
+	ro.adopt(c)
+	ro.live.Store(c)
+	return nil
+}
+
+// current returns the published tree of the router. If no tree was
+// published, the router itself is returned.
+func (ro *Router) current() *Router {
+	if c, ok := ro.live.Load().(*Router); ok {
+		return c
+	}
+
+	return ro
+}
+
+// adopt makes the tree of the published copy the router's tree.
+func (ro *Router) adopt(c *Router) {
+	ro.staticHosts = c.staticHosts
+	ro.caseInsensitiveHosts = c.caseInsensitiveHosts
+	ro.patternHosts = c.patternHosts
+	ro.r = c.r
+	ro.notFoundHandler = c.notFoundHandler
+	ro.permanentRedirectCode = c.permanentRedirectCode
+	ro.redirectHandler = c.redirectHandler
+	ro.corsPolicy = c.corsPolicy
+	ro.errorRenderer = c.errorRenderer
+	ro.tracer = c.tracer
+	ro.passerMws = c.passerMws
+	ro.requestPasser = c.requestPasser
+}
+
+// checkChangeable returns ErrOutsideUpdate if the router's tree was
+// published and can no longer be changed directly.
+func (ro *Router) checkChangeable() error {
+	if ro.live.Load() != nil {
+		return ErrOutsideUpdate
+	}
+
+	return nil
+}
+
+// checkChangeable returns ErrOutsideUpdate if the responder belongs to a
+// published tree.
+func (rb *_ResponderBase) checkChangeable() error {
+	for p := rb.papa; p != nil; p = p.parent() {
+		if ro, ok := p.(*Router); ok {
+			return ro.checkChangeable()
+		}
+	}
+
+	return nil
+}
+
+// -------------------------
+
+// clone returns a deep copy of the router's tree. The handlers bound to the
+// router, hosts, and resources are rebound to their copies.
+func (ro *Router) clone() *Router {
+	var c = &Router{
+		notFoundHandler:       ro.notFoundHandler,
+		permanentRedirectCode: ro.permanentRedirectCode,
+		redirectHandler:       ro.redirectHandler,
+		corsPolicy:            ro.corsPolicy,
+		errorRenderer:         ro.errorRenderer,
+		tracer:                ro.tracer,
+	}
+
+	c.passerMws = clipMiddlewares(ro.passerMws)
+	c.requestPasser = wrapWithMiddlewares(c.passRequest, c.passerMws)
+
+	if ro.staticHosts != nil {
+		c.staticHosts = make(map[string]*Host, len(ro.staticHosts))
+		for k, h := range ro.staticHosts {
+			var ch = h.clone()
+			ch.papa = c
+			c.staticHosts[k] = ch
+		}
+
+		c.indexCaseInsensitiveHosts()
+	}
+
+	for _, h := range ro.patternHosts {
+		var ch = h.clone()
+		ch.papa = c
+		c.patternHosts = append(c.patternHosts, ch)
+	}
+
+	if ro.r != nil {
+		c.r = ro.r.clone()
+		c.r.papa = c
+	}
+
+	return c
+}
+
+// clone returns a deep copy of the host and its subtree. The returned host
+// doesn't have a parent.
+func (hb *Host) clone() *Host {
+	var c = &Host{}
+	c.derived = c
+	c.requestReceiver = c.handleOrPassRequest
+	hb._ResponderBase.cloneTo(&c._ResponderBase)
+	return c
+}
+
+// clone returns a deep copy of the resource and its subtree. The returned
+// resource doesn't have a parent.
+func (rb *Resource) clone() *Resource {
+	var c = &Resource{urlt: rb.urlt}
+	c.derived = c
+	c.requestReceiver = c.handleOrPassRequest
+	rb._ResponderBase.cloneTo(&c._ResponderBase)
+	return c
+}
+
+// cloneTo copies the responder's state and its subtree to c. The derived
+// and requestReceiver fields of c must be set before the call.
+func (rb *_ResponderBase) cloneTo(c *_ResponderBase) {
+	c.impl = rb.impl
+	c.tmpl = rb.tmpl
+	c.permanentRedirectCode = rb.permanentRedirectCode
+	c.redirectHandler = rb.redirectHandler
+	c.notFoundHandler = rb.notFoundHandler
+	c.corsPolicy = rb.corsPolicy
+	c.errorRenderer = rb.errorRenderer
+	c.cfs = rb.cfs
+	c.sharedData = rb.sharedData
+
+	if rb._RequestHandlerBase != nil {
+		c.setRequestHandlerBase(rb._RequestHandlerBase.clone())
+		c.handlerMws = clipMiddlewares(rb.handlerMws)
+		c.requestHandler = wrapWithMiddlewares(c.requestHandler, c.handlerMws)
+	}
+
+	c.passerMws = clipMiddlewares(rb.passerMws)
+	c.requestPasser = wrapWithMiddlewares(c.passRequest, c.passerMws)
+
+	if ra := rb.requestRedirectArgs; ra != nil {
+		// Redirect arguments were validated when they were set.
+		c.requestRedirector, _ = redirector(c, ra.url, ra.code)
+		c.requestRedirectArgs = ra
+	}
+
+	if ra := rb.anyRequestRedirectArgs; ra != nil {
+		c.requestReceiver, _ = redirector(c, ra.url, ra.code)
+		c.anyRequestRedirectArgs = ra
+	}
+
+	if rb.staticResources != nil {
+		c.staticResources = make(map[string]*Resource, len(rb.staticResources))
+		for k, r := range rb.staticResources {
+			var cr = r.clone()
+			cr.papa = c.derived
+			c.staticResources[k] = cr
+		}
+
+		c.indexCaseInsensitiveResources()
+	}
+
+	for _, r := range rb.patternResources {
+		var cr = r.clone()
+		cr.papa = c.derived
+		c.patternResources = append(c.patternResources, cr)
+	}
+
+	if rb.wildcardResource != nil {
+		c.wildcardResource = rb.wildcardResource.clone()
+		c.wildcardResource.papa = c.derived
+	}
+}
+
+// clone returns a copy of the _RequestHandlerBase. The default handlers bound
+// to the original are rebound to the copy.
+func (rhb *_RequestHandlerBase) clone() *_RequestHandlerBase {
+	var c = &_RequestHandlerBase{
+		notAllowedHTTPMethodHandler: rhb.notAllowedHTTPMethodHandler,
+		notAllowedHandlerBase:       rhb.notAllowedHandlerBase,
+		notAllowedHandlerMws:        clipMiddlewares(rhb.notAllowedHandlerMws),
+	}
+
+	if rhb.mhPairs != nil {
+		c.mhPairs = make(_MethodHandlerPairs, len(rhb.mhPairs))
+		copy(c.mhPairs, rhb.mhPairs)
+
+		for i := range c.mhPairs {
+			c.mhPairs[i].mws = clipMiddlewares(c.mhPairs[i].mws)
+			if c.mhPairs[i].dflt {
+				c.mhPairs[i].handler = wrapWithMiddlewares(
+					c.handleOptionsHTTPMethod,
+					c.mhPairs[i].mws,
+				)
+			}
+		}
+	}
+
+	if c.notAllowedHandlerMws != nil && c.notAllowedHandlerBase == nil {
+		c.notAllowedHTTPMethodHandler = wrapWithMiddlewares(
+			c.handleNotAllowedHTTPMethod,
+			c.notAllowedHandlerMws,
+		)
+	}
+
+	return c
+}
+
+// --------------------------------------------------
+
+// _RedirectArgs keeps the arguments the redirector was created with.
+type _RedirectArgs struct {
+	url  string
+	code int
+}
+
+// wrapWithMiddlewares wraps the handler with the middlewares in their order.
+func wrapWithMiddlewares(h Handler, mws []Middleware) Handler {
+	for _, mw := range mws {
+		h = mw(h)
+	}
+
+	return h
+}
+
+// clipMiddlewares limits the capacity of the middlewares slice to its length,
+// so appending to it in the copy doesn't affect the original.
+func clipMiddlewares(mws []Middleware) []Middleware {
+	return mws[:len(mws):len(mws)]
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2021 Shohruh Adham
+// Use of this source code is governed by the MIT License.
+
+package nanomux
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// --------------------------------------------------
+
+func serve(ro *Router, method, url string) *httptest.ResponseRecorder {
+	var w = httptest.NewRecorder()
+	var r = httptest.NewRequest(method, url, nil)
+	ro.ServeHTTP(w, r)
+	return w
+}
+
+func textHandler(text string) Handler {
+	return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+		w.Write([]byte(text))
+		return true
+	}
+}
+
+func textRedirectHandler(text string) RedirectHandler {
+	return func(
+		w http.ResponseWriter,
+		r *http.Request,
+		url string,
+		code int,
+		args *Args,
+	) bool {
+		w.Write([]byte(text + url))
+		return true
+	}
+}
+
+func textMiddleware(text string) Middleware {
+	return func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			w.Write([]byte(text))
+			return next(w, r, args)
+		}
+	}
+}
+
+// --------------------------------------------------
+
+func TestRouter_Update(t *testing.T) {
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("get", "http://example.com/a", textHandler("a"))
+	checkValue(t, serve(ro, "GET", "http://example.com/a").Body.String(), "a")
+
+	var err = ro.Update(func(tx *RouterTx) error {
+		tx.SetURLHandlerFor("get", "http://example.com/b", textHandler("b"))
+
+		// Requests are served by the previous tree until the update is
+		// published.
+		var w = serve(ro, "GET", "http://example.com/b")
+		checkValue(t, w.Body.String() != "b", true)
+		return nil
+	})
+
+	checkErr(t, err, false)
+	checkValue(t, serve(ro, "GET", "http://example.com/a").Body.String(), "a")
+	checkValue(t, serve(ro, "GET", "http://example.com/b").Body.String(), "b")
+
+	// The tree can't be changed outside the update once it's published.
+	var a = ro.RegisteredResource("http://example.com/a")
+	err = ro.TryToSetURLHandlerFor(
+		"get",
+		"http://example.com/c",
+		textHandler("c"),
+	)
+
+	checkValue(t, errors.Is(err, ErrOutsideUpdate), true)
+	err = a.TryToSetHandlerFor("post", textHandler("a"))
+	checkValue(t, errors.Is(err, ErrOutsideUpdate), true)
+	testPanicker(t, true, func() { a.SetSharedData(1) })
+	testPanicker(t, true, func() { ro.SetSharedDataForAll(1) })
+	checkValue(t, ro.RegisteredResource("http://example.com/c") == nil, true)
+
+	var tx *RouterTx
+	err = ro.Update(func(utx *RouterTx) error {
+		tx = utx
+		utx.RegisteredResource("http://example.com/a").SetHandlerFor(
+			"get",
+			textHandler("A"),
+		)
+
+		return nil
+	})
+
+	checkErr(t, err, false)
+	checkValue(t, serve(ro, "GET", "http://example.com/a").Body.String(), "A")
+
+	// The published tree is the router's tree.
+	checkValue(t, ro.RegisteredResource("http://example.com/a") != a, true)
+	checkValue(
+		t,
+		ro.RegisteredResource("http://example.com/a"),
+		tx.RegisteredResource("http://example.com/a"),
+	)
+
+	// The responders replaced by the update and the RouterTx can't be changed.
+	err = a.TryToSetHandlerFor("get", textHandler("a"))
+	checkValue(t, errors.Is(err, ErrOutsideUpdate), true)
+	err = tx.TryToSetURLHandlerFor("get", "/c", textHandler("c"))
+	checkValue(t, errors.Is(err, ErrOutsideUpdate), true)
+
+	// A failed update is discarded.
+	a = ro.RegisteredResource("http://example.com/a")
+	var errTest = errors.New("test error")
+	err = ro.Update(func(tx *RouterTx) error {
+		tx.UnregisterResourceAt("http://example.com/a")
+		tx.SetURLHandlerFor("get", "/d", textHandler("d"))
+		return errTest
+	})
+
+	checkValue(t, errors.Is(err, errTest), true)
+	checkValue(t, serve(ro, "GET", "http://example.com/a").Body.String(), "A")
+	checkValue(t, serve(ro, "GET", "/d").Body.String() != "d", true)
+	checkValue(t, ro.RegisteredResource("http://example.com/a"), a)
+	checkValue(t, a.HandlerOf("get") != nil, true)
+	checkValue(t, ro.RegisteredResource("/d"), (*Resource)(nil))
+
+	// A panicking update is discarded too.
+	testPanicker(t, true, func() {
+		ro.Update(func(tx *RouterTx) error {
+			tx.SetURLHandlerFor("get", "/e", textHandler("e"))
+			tx.UnregisterResourceAt("http://example.com/non-existent")
+			return nil
+		})
+	})
+
+	checkValue(t, ro.RegisteredResource("/e"), (*Resource)(nil))
+	checkValue(t, ro.RegisteredResource("http://example.com/a"), a)
+	checkValue(t, serve(ro, "GET", "http://example.com/a").Body.String(), "A")
+
+	// The router is not locked after the panic.
+	err = ro.Update(func(tx *RouterTx) error { return nil })
+	checkErr(t, err, false)
+
+	testPanicker(t, true, func() { ro.Update(nil) })
+}
+
+func TestRouter_Update_snapshot(t *testing.T) {
+	var ro = NewRouter()
+	var h = ro.Host("example.com")
+	h.SetHandlerFor("get", textHandler("host"))
+	h.WrapRequestHandler(textMiddleware("hmw-"))
+	h.WrapRequestPasser(func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, args *Args) bool {
+			w.Header().Set("X-Passer", "host")
+			return next(w, r, args)
+		}
+	})
+
+	var r = ro.Resource("http://example.com/r")
+	r.SetHandlerFor("get", textHandler("r"))
+	r.WrapHandlerOf("options", textMiddleware("omw-"))
+	r.WrapHandlerOf("!", textMiddleware("namw-"))
+
+	ro.RedirectRequestAt(
+		"http://example.com/old",
+		"http://example.com/r",
+		http.StatusMovedPermanently,
+	)
+
+	ro.RedirectAnyRequestAt(
+		"http://example.com/moved",
+		"http://example.com/new",
+		http.StatusPermanentRedirect,
+	)
+
+	ro.SetRedirectHandlerAt("http://example.com/old", textRedirectHandler(""))
+	ro.SetRedirectHandlerAt("http://example.com/moved", textRedirectHandler(""))
+	ro.WrapRequestPasser(textMiddleware("romw-"))
+
+	var err = ro.Update(func(tx *RouterTx) error { return nil })
+	checkErr(t, err, false)
+
+	var snapshot = ro.current()
+	if snapshot == ro {
+		t.Fatalf("Router.Update(): snapshot wasn't published")
+	}
+
+	checkValue(t, snapshot.origin, ro)
+
+	var sh = snapshot.staticHosts["example.com"]
+	var sr = sh.staticResources["r"]
+	if sh == h || sr == r {
+		t.Fatalf("Router.Update(): snapshot shares responders")
+	}
+
+	checkValue(t, ro.RegisteredHost("example.com"), sh)
+	checkValue(t, ro.RegisteredResource("http://example.com/r"), sr)
+
+	checkValue(t, sh.parent(), _Parent(snapshot))
+	checkValue(t, sr.parent(), _Parent(sh))
+	checkValue(t, sr.Router(), ro)
+	checkValue(t, sr.Template(), r.Template())
+
+	var w = serve(ro, "GET", "http://example.com")
+	checkValue(t, w.Body.String(), "romw-hmw-host")
+
+	w = serve(ro, "GET", "http://example.com/r")
+	checkValue(t, w.Body.String(), "romw-r")
+	checkValue(t, w.Header().Get("X-Passer"), "host")
+
+	// Default handlers must be bound to the snapshot's responders.
+	err = ro.Update(func(tx *RouterTx) error {
+		tx.RegisteredResource("http://example.com/r").SetHandlerFor(
+			"post",
+			textHandler("r"),
+		)
+
+		return nil
+	})
+
+	checkErr(t, err, false)
+
+	w = serve(ro, "OPTIONS", "http://example.com/r")
+	checkValue(t, w.Body.String(), "romw-omw-")
+	checkValue(t, w.Header().Get("Allow"), "GET, OPTIONS, POST")
+
+	w = serve(ro, "PUT", "http://example.com/r")
+	checkValue(t, strings.HasPrefix(w.Body.String(), "romw-namw-"), true)
+	checkValue(t, w.Header().Get("Allow"), "GET, OPTIONS, POST")
+
+	w = serve(ro, "GET", "http://example.com/old")
+	checkValue(t, w.Body.String(), "romw-http://example.com/r")
+
+	w = serve(ro, "GET", "http://example.com/moved/a")
+	checkValue(t, w.Body.String(), "romw-http://example.com/new/a")
+
+	// Redirectors must use the redirect handler of the current responder.
+	err = ro.Update(func(tx *RouterTx) error {
+		tx.SetRedirectHandlerAt(
+			"http://example.com/old",
+			textRedirectHandler("redirect-"),
+		)
+
+		return nil
+	})
+
+	checkErr(t, err, false)
+
+	w = serve(ro, "GET", "http://example.com/old")
+	checkValue(t, w.Body.String(), "romw-redirect-http://example.com/r")
+}
+
+func TestRouter_Update_concurrently(t *testing.T) {
+	// The test must be run with the -race flag to be useful.
+	var ro = NewRouter()
+	ro.SetURLHandlerFor("get", "/static", textHandler("static"))
+
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				var w = serve(ro, "GET", "/static")
+				if w.Body.String() != "static" {
+					t.Errorf("static resource wasn't served")
+					return
+				}
+
+				serve(ro, "GET", "/plugins/p1")
+				serve(ro, "GET", "http://tenant1.example.com")
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		var err = ro.Update(func(tx *RouterTx) error {
+			var name = fmt.Sprintf("p%d", i%3)
+			if i%2 == 0 {
+				tx.SetURLHandlerFor("get", "/plugins/"+name, textHandler(name))
+				tx.Resource("/plugins").WrapRequestPasser(textMiddleware("-"))
+			} else if r := tx.RegisteredResource("/plugins/" + name); r != nil {
+				r.Parent().UnregisterResource(r)
+			}
+
+			var host = fmt.Sprintf("tenant%d.example.com", i%2)
+			if h := tx.RegisteredHost(host); h != nil {
+				tx.UnregisterHost(h)
+			} else {
+				tx.Host(host).SetHandlerFor("get", textHandler(host))
+			}
+
+			return nil
+		})
+
+		checkErr(t, err, false)
+	}
+
+	close(done)
+	wg.Wait()
+}
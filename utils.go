@@ -4,6 +4,7 @@
 package nanomux
 
 import (
+	"net"
 	"net/http"
 	"net/url"
 	"path"
@@ -89,6 +90,48 @@ func (psi *_PathSegmentIterator) pathHasTrailingSlash() bool {
 
 // --------------------------------------------------
 
+// requestHost returns the host of the request without the port.
+func requestHost(r *http.Request) string {
+	var host = r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	if strings.LastIndexByte(host, ':') >= 0 {
+		var h, _, err = net.SplitHostPort(host)
+		if err == nil {
+			host = h
+		}
+	}
+
+	return host
+}
+
+// addVary adds the header names to the "Vary" header of the response unless
+// they are already listed in it.
+func addVary(h http.Header, names ...string) {
+	for _, name := range names {
+		var listed bool
+		for _, v := range h.Values("Vary") {
+			for _, vn := range strings.Split(v, ",") {
+				vn = strings.TrimSpace(vn)
+				if vn == "*" || strings.EqualFold(vn, name) {
+					listed = true
+					break
+				}
+			}
+
+			if listed {
+				break
+			}
+		}
+
+		if !listed {
+			h.Add("Vary", name)
+		}
+	}
+}
+
 func cloneRequestURL(r *http.Request) *url.URL {
 	var url = &url.URL{}
 	*url = *r.URL
@@ -334,6 +377,11 @@ loop:
 				return nil, newErr("%w", err)
 			}
 
+			if ps == "" && tmpl.IsOptional() {
+				// The optional segment with the default value is omitted.
+				continue
+			}
+
 			pss = append(pss, ps)
 		case *Host:
 			var tmpl = p.Template()
@@ -413,19 +461,39 @@ func (args _Args) get(key interface{}) (int, interface{}) {
 
 // --------------------------------------------------
 
+// _Outcome is the outcome of the request other than being handled by the
+// responder's request handler.
+type _Outcome uint8
+
+const (
+	outcomeHandled _Outcome = iota
+	outcomeRedirected
+	outcomeNotFound
+	outcomeMethodNotAllowed
+	outcomeBadRequest
+)
+
+// --------------------------------------------------
+
 // Args is created for each request and passed to handlers. The middleware must
 // pass the *Args argument to the next handler.
 type Args struct {
 	path                  string
 	rawPath               bool
 	cleanPath             bool
+	canonicalPath         bool
 	currentPathSegmentIdx int
+	rawCatchAllValue      string
 
 	subtreeExists bool
 	handled       bool
+	outcome       _Outcome
 
 	hostPathValues HostPathValues
 	_r             _Responder
+	tracer         Tracer
+	resolution     *Resolution
+	err            error
 
 	slc _Args
 }
@@ -495,6 +563,49 @@ func (args *Args) nextPathSegment() (string, error) {
 	return args.path[cIdx:idx], nil
 }
 
+// pathSegmentsFrom returns the unescaped path segments of the request's URL,
+// starting from the index and without a trailing slash. The path segments are
+// considered passed after the call.
+//
+// The escaped path segments are kept as the raw catch-all value, because
+// after unescaping, the escaped slashes "%2F" can't be told from the
+// separators.
+func (args *Args) pathSegmentsFrom(idx int) (string, error) {
+	var pss = args.path[idx:]
+	args.currentPathSegmentIdx = len(args.path)
+	if lpss := len(pss); lpss > 0 && pss[lpss-1] == '/' {
+		pss = pss[:lpss-1]
+	}
+
+	if args.rawPath {
+		args.rawCatchAllValue = pss
+		return url.PathUnescape(pss)
+	}
+
+	args.rawCatchAllValue = (&url.URL{Path: pss}).EscapedPath()
+	return pss, nil
+}
+
+// replacePathSegment replaces the path segment that starts at the index with
+// the passed segment. It's used to redirect the request to the URL with the
+// path segment in its canonical case.
+func (args *Args) replacePathSegment(idx int, ps string) {
+	var end = strings.IndexByte(args.path[idx:], '/')
+	if end < 0 {
+		end = len(args.path)
+	} else {
+		end += idx
+	}
+
+	if args.rawPath {
+		ps = url.PathEscape(ps)
+	}
+
+	args.path = args.path[:idx] + ps + args.path[end:]
+	args.currentPathSegmentIdx += len(ps) - (end - idx)
+	args.canonicalPath = true
+}
+
 // reachedTheLastPathSegment returns true when the responder that is using the
 // routing data is the last responder in the request's URL.
 func (args *Args) reachedTheLastPathSegment() bool {
@@ -514,6 +625,47 @@ func (args *Args) HostPathValues() HostPathValues {
 	return args.hostPathValues
 }
 
+// RawCatchAllValue returns the escaped value of the catch-all path segment
+// that matched the request's URL. The value in the HostPathValues is
+// unescaped, so an escaped slash "%2F" in it can't be told from a separator
+// "/". The raw value keeps the escaped slashes as they were in the request's
+// URL. If the request's URL wasn't matched by a catch-all path segment,
+// an empty string is returned.
+func (args *Args) RawCatchAllValue() string {
+	return args.rawCatchAllValue
+}
+
+// PathValue returns the host or path value of the name. If the value's
+// dynamic segment was given the type name of a registered converter, the
+// value is returned converted. Otherwise, the value is returned as a string.
+//
+// If the value doesn't exist, the error wraps the ErrMissingValue. If the
+// converter fails, the error wraps the ErrInvalidValue.
+func (args *Args) PathValue(name string) (interface{}, error) {
+	var i, v = args.hostPathValues.get(name)
+	if i < 0 {
+		return nil, newErr("%w for %q", ErrMissingValue, name)
+	}
+
+	for p := _Parent(args._r); p != nil; p = p.parent() {
+		var _r, ok = p.(_Responder)
+		if !ok {
+			break
+		}
+
+		if convert := _r.Template().valueConverter(name); convert != nil {
+			var cv, err = convert(v)
+			if err != nil {
+				return nil, newErr("%w for %q: %v", ErrInvalidValue, name, err)
+			}
+
+			return cv, nil
+		}
+	}
+
+	return v, nil
+}
+
 // RemainingPath returns the escaped remaining path of the request's URL
 // that's below the responder's segment that is currently passing or handling
 // the request.
@@ -588,6 +740,78 @@ func (args *Args) CurrentResource() *Resource {
 	return nil
 }
 
+// MatchedTemplate returns the URL template of the host or resource that is
+// currently passing or handling the request, in the form of the Route's
+// URLTemplate. Unlike the request's path, the template has a low cardinality,
+// so it can be used as a label in metrics and logs.
+//
+// In a middleware of the router's request passer, the template is available
+// after the next handler returns. If the request was not found, it's the
+// template of the last responder that matched the request's URL. If no
+// responder matched the URL, an empty string is returned.
+func (args *Args) MatchedTemplate() string {
+	if args._r == nil {
+		return ""
+	}
+
+	return responderURLTmplStr(args._r)
+}
+
+// MatchedResources returns the resources that matched the path segments of
+// the request's URL, from the first one below the host, or the root resource,
+// to the current resource. The host is returned by the Host method. If the
+// request is being handled by a host, nil is returned.
+func (args *Args) MatchedResources() []*Resource {
+	var rs []*Resource
+	for p := _Parent(args._r); p != nil; p = p.parent() {
+		var r, ok = p.(*Resource)
+		if !ok {
+			break
+		}
+
+		rs = append(rs, r)
+	}
+
+	for i, j := 0, len(rs)-1; i < j; i, j = i+1, j-1 {
+		rs[i], rs[j] = rs[j], rs[i]
+	}
+
+	return rs
+}
+
+// Redirected returns true if the request was redirected. It's also true for
+// the automatic redirects, such as the redirects from HTTP to HTTPS, to the
+// clean path, or to the URL with or without a trailing slash.
+func (args *Args) Redirected() bool {
+	return args.outcome == outcomeRedirected
+}
+
+// NotFound returns true if the request was passed to the not-found handler.
+func (args *Args) NotFound() bool {
+	return args.outcome == outcomeNotFound
+}
+
+// MethodNotAllowed returns true if the request's HTTP method was not allowed
+// by the responder and the request was passed to its not-allowed method
+// handler.
+func (args *Args) MethodNotAllowed() bool {
+	return args.outcome == outcomeMethodNotAllowed
+}
+
+// BadRequest returns true if the request's path couldn't be unescaped and
+// the "400 Bad Request" error was rendered instead of passing the request on.
+func (args *Args) BadRequest() bool {
+	return args.outcome == outcomeBadRequest
+}
+
+// Err returns the error returned from the handler converted with the ErrHr
+// or JSON functions. The error is rendered after the request handler and its
+// middlewares return, so the middlewares can get it after the next handler
+// returns. If the handler didn't return an error, nil is returned.
+func (args *Args) Err() error {
+	return args.err
+}
+
 // Set sets the custom argument that is passed between middlewares and/or
 // handlers. The rules for defining a key are the same as in the context
 // package. The key must be comparable and its type must be custom defined.
@@ -631,10 +855,16 @@ var argsPool = sync.Pool{
 
 func putArgsInThePool(args *Args) {
 	args.cleanPath = false
+	args.canonicalPath = false
 	args.currentPathSegmentIdx = 0
+	args.rawCatchAllValue = ""
 
 	args.subtreeExists = false
 	args.handled = false
+	args.outcome = outcomeHandled
+	args.tracer = nil
+	args.resolution = nil
+	args.err = nil
 
 	if args.hostPathValues != nil {
 		args.hostPathValues = args.hostPathValues[:0]